@@ -10,15 +10,40 @@ import (
 	"time"
 
 	pg "github.com/vortex-fintech/go-lib/data/postgres"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
 )
 
-type PostgresStore struct{}
+type PostgresStore struct {
+	clock timeutil.Clock
+}
+
+type PostgresStoreOption func(*PostgresStore)
+
+// WithClock sets the time source for created_at/updated_at defaults, completed_at
+// and the DeleteExpired cutoff. Values are still normalized to UTC microseconds.
+// nil keeps the default (system time).
+func WithClock(c timeutil.Clock) PostgresStoreOption {
+	return func(s *PostgresStore) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
 
-func NewPostgresStore() *PostgresStore {
-	return &PostgresStore{}
+func NewPostgresStore(opts ...PostgresStoreOption) *PostgresStore {
+	s := &PostgresStore{clock: timeutil.UTCClock{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
 }
 
-var _ Store = (*PostgresStore)(nil)
+var (
+	_ Store        = (*PostgresStore)(nil)
+	_ LeaseToucher = (*PostgresStore)(nil)
+)
 
 func (s *PostgresStore) Reserve(ctx context.Context, run pg.Runner, rec Record) (ReserveResult, error) {
 	ctx = ensureContext(ctx)
@@ -26,39 +51,12 @@ func (s *PostgresStore) Reserve(ctx context.Context, run pg.Runner, rec Record)
 	if err := validateRunner(run); err != nil {
 		return ReserveResult{}, err
 	}
-	if err := validateIdentity(rec.Principal, rec.GRPCMethod, rec.IdempotencyKey); err != nil {
+	rec, err := prepareReserve(rec, s.nowUTC())
+	if err != nil {
 		return ReserveResult{}, err
 	}
-	if strings.TrimSpace(rec.RequestHash) == "" {
-		return ReserveResult{}, ErrRequestHashRequired
-	}
 
-	now := nowUTC()
-	if rec.CreatedAt.IsZero() {
-		rec.CreatedAt = now
-	} else {
-		rec.CreatedAt = normalizeUTC(rec.CreatedAt)
-	}
-	if rec.UpdatedAt.IsZero() {
-		rec.UpdatedAt = now
-	} else {
-		rec.UpdatedAt = normalizeUTC(rec.UpdatedAt)
-	}
-	if rec.Status == "" {
-		rec.Status = StatusInProgress
-	}
-	if !rec.Status.IsValid() {
-		return ReserveResult{}, fmt.Errorf("%w: %q", ErrInvalidStatus, rec.Status)
-	}
-	if rec.ExpiresAt.IsZero() {
-		return ReserveResult{}, ErrExpiresAtRequired
-	}
-	rec.ExpiresAt = normalizeUTC(rec.ExpiresAt)
-	if !rec.ExpiresAt.After(rec.CreatedAt) {
-		return ReserveResult{}, ErrExpiresAtInvalid
-	}
-
-	err := run.QueryRow(ctx, `
+	err = run.QueryRow(ctx, `
 		INSERT INTO idempotency_keys (
 			principal, grpc_method, idempotency_key, request_hash,
 			status, response_code, response_payload, error_message,
@@ -109,6 +107,47 @@ func (s *PostgresStore) Reserve(ctx context.Context, run pg.Runner, rec Record)
 	if getErr != nil {
 		return ReserveResult{}, getErr
 	}
+	return existingReservation(rec, existing)
+}
+
+// prepareReserve validates rec for Reserve and fills the defaults shared by all stores:
+// created_at/updated_at default to now, status to IN_PROGRESS; times are normalized.
+func prepareReserve(rec Record, now time.Time) (Record, error) {
+	if err := validateIdentity(rec.Principal, rec.GRPCMethod, rec.IdempotencyKey); err != nil {
+		return Record{}, err
+	}
+	if strings.TrimSpace(rec.RequestHash) == "" {
+		return Record{}, ErrRequestHashRequired
+	}
+
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = now
+	} else {
+		rec.CreatedAt = normalizeUTC(rec.CreatedAt)
+	}
+	if rec.UpdatedAt.IsZero() {
+		rec.UpdatedAt = now
+	} else {
+		rec.UpdatedAt = normalizeUTC(rec.UpdatedAt)
+	}
+	if rec.Status == "" {
+		rec.Status = StatusInProgress
+	}
+	if !rec.Status.IsValid() {
+		return Record{}, fmt.Errorf("%w: %q", ErrInvalidStatus, rec.Status)
+	}
+	if rec.ExpiresAt.IsZero() {
+		return Record{}, ErrExpiresAtRequired
+	}
+	rec.ExpiresAt = normalizeUTC(rec.ExpiresAt)
+	if !rec.ExpiresAt.After(rec.CreatedAt) {
+		return Record{}, ErrExpiresAtInvalid
+	}
+	return rec, nil
+}
+
+// existingReservation is the Reserve result when rec's key was already taken.
+func existingReservation(rec Record, existing *Record) (ReserveResult, error) {
 	if existing == nil {
 		return ReserveResult{}, ErrInconsistentState
 	}
@@ -216,18 +255,12 @@ func (s *PostgresStore) Complete(ctx context.Context, run pg.Runner, principal,
 	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
 		return false, err
 	}
-	if !done.Status.IsValid() {
-		return false, fmt.Errorf("%w: %q", ErrInvalidStatus, done.Status)
-	}
-	if !done.Status.IsTerminal() {
-		return false, fmt.Errorf("%w: %q", ErrCompletionNotTerminal, done.Status)
+	if err := validateCompletion(done); err != nil {
+		return false, err
 	}
 
-	if done.UpdatedAt.IsZero() {
-		return false, ErrUpdatedAtRequired
-	}
 	expectedUpdatedAt := normalizeUTC(done.UpdatedAt)
-	completedAt := nowUTC()
+	completedAt := s.nowUTC()
 
 	res, err := run.Exec(ctx, `
 		UPDATE idempotency_keys
@@ -248,6 +281,39 @@ func (s *PostgresStore) Complete(ctx context.Context, run pg.Runner, principal,
 	return res.RowsAffected() > 0, nil
 }
 
+func (s *PostgresStore) TouchLease(ctx context.Context, run pg.Runner, principal, grpcMethod, idemKey string, prevUpdatedAt, newUpdatedAt time.Time) (bool, error) {
+	ctx = ensureContext(ctx)
+
+	if err := validateRunner(run); err != nil {
+		return false, err
+	}
+	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
+		return false, err
+	}
+	if prevUpdatedAt.IsZero() || newUpdatedAt.IsZero() {
+		return false, ErrUpdatedAtRequired
+	}
+	prevUpdatedAt = normalizeUTC(prevUpdatedAt)
+	newUpdatedAt = normalizeUTC(newUpdatedAt)
+	if !newUpdatedAt.After(prevUpdatedAt) {
+		return false, ErrUpdatedAtNotAdvanced
+	}
+
+	res, err := run.Exec(ctx, `
+		UPDATE idempotency_keys
+		   SET updated_at = $1
+		 WHERE principal = $2
+		   AND grpc_method = $3
+		   AND idempotency_key = $4
+		   AND status = 'IN_PROGRESS'
+		   AND updated_at = $5
+	`, newUpdatedAt, principal, grpcMethod, idemKey, prevUpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	return res.RowsAffected() > 0, nil
+}
+
 func (s *PostgresStore) DeleteExpired(ctx context.Context, run pg.Runner, before time.Time) (int64, error) {
 	ctx = ensureContext(ctx)
 
@@ -255,7 +321,7 @@ func (s *PostgresStore) DeleteExpired(ctx context.Context, run pg.Runner, before
 		return 0, err
 	}
 	if before.IsZero() {
-		before = nowUTC()
+		before = s.nowUTC()
 	} else {
 		before = normalizeUTC(before)
 	}
@@ -271,6 +337,54 @@ func (s *PostgresStore) DeleteExpired(ctx context.Context, run pg.Runner, before
 	return res.RowsAffected(), nil
 }
 
+const MaxDeleteBatchSize = 10000
+
+func (s *PostgresStore) DeleteExpiredBatch(ctx context.Context, run pg.Runner, before time.Time, batchSize int) (int64, error) {
+	ctx = ensureContext(ctx)
+
+	if err := validateRunner(run); err != nil {
+		return 0, err
+	}
+	if batchSize <= 0 {
+		return 0, ErrBatchSizeInvalid
+	}
+	batchSize = min(batchSize, MaxDeleteBatchSize)
+	if before.IsZero() {
+		before = s.nowUTC()
+	} else {
+		before = normalizeUTC(before)
+	}
+
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		res, err := run.Exec(ctx, `
+			DELETE FROM idempotency_keys
+			 WHERE (principal, grpc_method, idempotency_key) IN (
+				SELECT principal, grpc_method, idempotency_key
+				  FROM idempotency_keys
+				 WHERE expires_at <= $1
+				   AND status IN ('SUCCEEDED', 'FAILED_RETRYABLE', 'FAILED_FINAL')
+				 ORDER BY expires_at
+				 LIMIT $2
+				 FOR UPDATE SKIP LOCKED
+			 )
+		`, before, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		n := res.RowsAffected()
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
 func nullIfEmpty(v string) any {
 	if strings.TrimSpace(v) == "" {
 		return nil
@@ -291,6 +405,21 @@ func validateIdentity(principal, grpcMethod, idemKey string) error {
 	return nil
 }
 
+// validateCompletion checks that done moves a record to a terminal status and names the
+// updated_at it expects to replace.
+func validateCompletion(done Completion) error {
+	if !done.Status.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, done.Status)
+	}
+	if !done.Status.IsTerminal() {
+		return fmt.Errorf("%w: %q", ErrCompletionNotTerminal, done.Status)
+	}
+	if done.UpdatedAt.IsZero() {
+		return ErrUpdatedAtRequired
+	}
+	return nil
+}
+
 func validateRunner(run pg.Runner) error {
 	if run == nil {
 		return ErrNilRunner
@@ -305,8 +434,11 @@ func validateRunner(run pg.Runner) error {
 	return nil
 }
 
-func nowUTC() time.Time {
-	return normalizeUTC(time.Now())
+func (s *PostgresStore) nowUTC() time.Time {
+	if s == nil || s.clock == nil {
+		return normalizeUTC(time.Now())
+	}
+	return normalizeUTC(s.clock.Now())
 }
 
 func normalizeUTC(v time.Time) time.Time {
@@ -154,6 +154,59 @@ func TestPostgresStore_DeleteExpiredOnlyTerminal_Integration(t *testing.T) {
 	require.Nil(t, completed, "terminal row should be removed")
 }
 
+func TestPostgresStore_DeleteExpiredBatchOnlyTerminal_Integration(t *testing.T) {
+	c := openIntegrationClient(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	run := c.RunnerFromPool()
+	require.NoError(t, ensureIdempotencySchema(ctx, run))
+	require.NoError(t, truncateIdempotencyKeys(ctx, run))
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	createdAt := now.Add(-10 * time.Minute)
+	expiredAt := now.Add(-5 * time.Minute)
+
+	rows := []struct {
+		key    string
+		status string
+	}{
+		{"idem-batch-1", "SUCCEEDED"},
+		{"idem-batch-2", "FAILED_FINAL"},
+		{"idem-batch-3", "FAILED_RETRYABLE"},
+		{"idem-batch-4", "SUCCEEDED"},
+		{"idem-batch-5", "SUCCEEDED"},
+		{"idem-batch-in-progress", "IN_PROGRESS"},
+	}
+	for _, row := range rows {
+		_, err := run.Exec(ctx, `
+			INSERT INTO idempotency_keys (
+				principal, grpc_method, idempotency_key, request_hash,
+				status, response_code, response_payload, error_message,
+				created_at, updated_at, expires_at
+			) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		`, "merchant-4", "/payments.v1.Payments/Refund", row.key, "hash-"+row.key, row.status, 0, nil, nil, createdAt, createdAt, expiredAt)
+		require.NoError(t, err)
+	}
+
+	s := idempotency.NewPostgresStore()
+	deleted, err := s.DeleteExpiredBatch(ctx, run, now, 2)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, deleted)
+
+	inProgress, err := s.Get(ctx, run, "merchant-4", "/payments.v1.Payments/Refund", "idem-batch-in-progress")
+	require.NoError(t, err)
+	require.NotNil(t, inProgress, "in-progress row must stay")
+
+	for _, row := range rows[:5] {
+		rec, err := s.Get(ctx, run, "merchant-4", "/payments.v1.Payments/Refund", row.key)
+		require.NoError(t, err)
+		require.Nil(t, rec, "terminal row should be removed")
+	}
+}
+
 func openIntegrationClient(t *testing.T) *postgres.Client {
 	t.Helper()
 
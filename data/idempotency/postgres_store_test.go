@@ -10,6 +10,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
 )
 
 func TestReserve_RequiresExpiresAt(t *testing.T) {
@@ -251,6 +252,119 @@ func TestComplete_RequiresUpdatedAt(t *testing.T) {
 	}
 }
 
+func TestComplete_UsesClockForCompletedAt(t *testing.T) {
+	t.Parallel()
+
+	frozen := time.Date(2026, 3, 4, 5, 6, 7, 123456789, time.FixedZone("X", 3600))
+	s := NewPostgresStore(WithClock(timeutil.NewFrozenClock(frozen)))
+	r := &runnerStub{execResults: []execResult{{tag: mustTag("UPDATE 1")}}}
+
+	ok, err := s.Complete(context.Background(), r, "u1", "/svc.Method", "k1", Completion{
+		Status:    StatusSucceeded,
+		UpdatedAt: frozen.Add(-time.Second),
+	})
+	if err != nil || !ok {
+		t.Fatalf("unexpected result: ok=%v err=%v", ok, err)
+	}
+
+	got, isTime := r.execArgs[0][4].(time.Time)
+	want := frozen.UTC().Truncate(time.Microsecond)
+	if !isTime || !got.Equal(want) || got.Location() != time.UTC {
+		t.Fatalf("completed_at = %v, want %v", r.execArgs[0][4], want)
+	}
+}
+
+func TestReserve_UsesClockForDefaults(t *testing.T) {
+	t.Parallel()
+
+	frozen := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	rec := Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		Status:         StatusInProgress,
+		CreatedAt:      frozen,
+		UpdatedAt:      frozen,
+		ExpiresAt:      frozen.Add(time.Minute),
+	}
+	r := &runnerStub{rows: []pgx.Row{rowStub{scanFn: scanRecord(rec)}}}
+	s := NewPostgresStore(WithClock(timeutil.NewFrozenClock(frozen)))
+
+	if _, err := s.Reserve(context.Background(), r, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      frozen.Add(time.Minute),
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, i := range []int{8, 9} {
+		if got := r.queryRowArgs[0][i]; got != frozen {
+			t.Fatalf("arg %d = %v, want %v", i, got, frozen)
+		}
+	}
+}
+
+func TestTouchLease_Matched(t *testing.T) {
+	t.Parallel()
+
+	r := &runnerStub{execResults: []execResult{{tag: mustTag("UPDATE 1")}}}
+	s := NewPostgresStore()
+
+	prev := time.Date(2026, 1, 2, 3, 4, 5, 6000, time.FixedZone("X", 3600))
+	next := prev.Add(30 * time.Second)
+
+	ok, err := s.TouchLease(context.Background(), r, "u1", "/svc.Method", "k1", prev, next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected touch to succeed")
+	}
+	q := firstOrEmpty(r.execSQL)
+	if !strings.Contains(q, "status = 'IN_PROGRESS'") || !strings.Contains(q, "updated_at = $5") {
+		t.Fatalf("expected status and updated_at guards, got %q", q)
+	}
+	if got := r.execArgs[0][0].(time.Time); !got.Equal(next) || got.Location() != time.UTC {
+		t.Fatalf("expected new updated_at in UTC, got %v", got)
+	}
+	if got := r.execArgs[0][4].(time.Time); !got.Equal(prev) || got.Location() != time.UTC {
+		t.Fatalf("expected previous updated_at in UTC, got %v", got)
+	}
+}
+
+func TestTouchLease_StaleLease(t *testing.T) {
+	t.Parallel()
+
+	r := &runnerStub{execResults: []execResult{{tag: mustTag("UPDATE 0")}}}
+	s := NewPostgresStore()
+
+	prev := time.Now().UTC()
+	ok, err := s.TouchLease(context.Background(), r, "u1", "/svc.Method", "k1", prev, prev.Add(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected touch to report a lost race")
+	}
+}
+
+func TestTouchLease_Validation(t *testing.T) {
+	t.Parallel()
+
+	s := NewPostgresStore()
+	now := time.Now().UTC()
+
+	if _, err := s.TouchLease(context.Background(), &runnerStub{}, "u1", "/svc.Method", "k1", time.Time{}, now); !errors.Is(err, ErrUpdatedAtRequired) {
+		t.Fatalf("expected ErrUpdatedAtRequired, got %v", err)
+	}
+	if _, err := s.TouchLease(context.Background(), &runnerStub{}, "u1", "/svc.Method", "k1", now, now); !errors.Is(err, ErrUpdatedAtNotAdvanced) {
+		t.Fatalf("expected ErrUpdatedAtNotAdvanced, got %v", err)
+	}
+}
+
 func TestDeleteExpired(t *testing.T) {
 	t.Parallel()
 
@@ -269,6 +383,113 @@ func TestDeleteExpired(t *testing.T) {
 	}
 }
 
+func TestDeleteExpiredBatch_LoopsUntilShortBatch(t *testing.T) {
+	t.Parallel()
+
+	r := &runnerStub{execResults: []execResult{
+		{tag: mustTag("DELETE 2")},
+		{tag: mustTag("DELETE 2")},
+		{tag: mustTag("DELETE 1")},
+	}}
+	s := NewPostgresStore()
+
+	n, err := s.DeleteExpiredBatch(context.Background(), r, time.Now(), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expected 5 deleted rows, got %d", n)
+	}
+	if len(r.execSQL) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(r.execSQL))
+	}
+	for i, q := range r.execSQL {
+		if !strings.Contains(q, "status IN ('SUCCEEDED', 'FAILED_RETRYABLE', 'FAILED_FINAL')") {
+			t.Fatalf("batch %d: expected terminal-status guard, got %q", i, q)
+		}
+		if !strings.Contains(q, "LIMIT $2") {
+			t.Fatalf("batch %d: expected bounded batch, got %q", i, q)
+		}
+		if got := r.execArgs[i][1]; got != 2 {
+			t.Fatalf("batch %d: expected batch size 2, got %v", i, got)
+		}
+	}
+}
+
+func TestDeleteExpiredBatch_StopsOnEmptyBatch(t *testing.T) {
+	t.Parallel()
+
+	r := &runnerStub{execResults: []execResult{
+		{tag: mustTag("DELETE 3")},
+		{tag: mustTag("DELETE 0")},
+	}}
+	s := NewPostgresStore()
+
+	n, err := s.DeleteExpiredBatch(context.Background(), r, time.Now(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 || len(r.execSQL) != 2 {
+		t.Fatalf("expected 3 rows in 2 batches, got %d rows in %d batches", n, len(r.execSQL))
+	}
+}
+
+func TestDeleteExpiredBatch_ClampsBatchSize(t *testing.T) {
+	t.Parallel()
+
+	r := &runnerStub{execResults: []execResult{{tag: mustTag("DELETE 0")}}}
+	s := NewPostgresStore()
+
+	if _, err := s.DeleteExpiredBatch(context.Background(), r, time.Now(), MaxDeleteBatchSize*10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := r.execArgs[0][1]; got != MaxDeleteBatchSize {
+		t.Fatalf("expected batch size clamped to %d, got %v", MaxDeleteBatchSize, got)
+	}
+}
+
+func TestDeleteExpiredBatch_RejectsInvalidBatchSize(t *testing.T) {
+	t.Parallel()
+
+	s := NewPostgresStore()
+	_, err := s.DeleteExpiredBatch(context.Background(), &runnerStub{}, time.Now(), 0)
+	if !errors.Is(err, ErrBatchSizeInvalid) {
+		t.Fatalf("expected ErrBatchSizeInvalid, got %v", err)
+	}
+}
+
+func TestDeleteExpiredBatch_StopsOnContextCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &cancelingRunner{runnerStub: &runnerStub{execResults: []execResult{
+		{tag: mustTag("DELETE 2")},
+		{tag: mustTag("DELETE 2")},
+	}}, cancel: cancel}
+	s := NewPostgresStore()
+
+	n, err := s.DeleteExpiredBatch(ctx, r, time.Now(), 2)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected rows from completed batch to be reported, got %d", n)
+	}
+	if len(r.execSQL) != 1 {
+		t.Fatalf("expected no batches after cancel, got %d", len(r.execSQL))
+	}
+}
+
+type cancelingRunner struct {
+	*runnerStub
+	cancel context.CancelFunc
+}
+
+func (r *cancelingRunner) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	defer r.cancel()
+	return r.runnerStub.Exec(ctx, sql, args...)
+}
+
 func TestNullIfEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,312 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	pg "github.com/vortex-fintech/go-lib/data/postgres"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
+)
+
+// DefaultRedisKeyPrefix is prepended to every RedisStore key.
+const DefaultRedisKeyPrefix = "idempotency:"
+
+var ErrNilRedisClient = errors.New("idempotency: redis client is required")
+
+// Each record is one hash. Timestamps are unix microseconds, so updated_at compares exactly
+// as a string; the key expires at expires_at (PEXPIREAT, rounded up to the millisecond).
+const (
+	redisReserveLua = `
+if redis.call("EXISTS", KEYS[1]) == 1 then return 0 end
+redis.call("HSET", KEYS[1], unpack(ARGV, 2))
+redis.call("PEXPIREAT", KEYS[1], ARGV[1])
+return 1`
+
+	redisReacquireLua = `
+local h = redis.call("HMGET", KEYS[1], "request_hash", "status", "updated_at", "expires_at")
+if h[1] ~= ARGV[1] or h[2] ~= "FAILED_RETRYABLE" then return 0 end
+local at = tonumber(ARGV[2])
+if tonumber(h[4]) <= at or tonumber(h[3]) >= at then return 0 end
+redis.call("HSET", KEYS[1], "status", "IN_PROGRESS", "response_code", "0",
+	"response_payload", "", "error_message", "", "updated_at", ARGV[2])
+return 1`
+
+	redisCompleteLua = `
+local h = redis.call("HMGET", KEYS[1], "status", "updated_at")
+if h[1] ~= "IN_PROGRESS" or h[2] ~= ARGV[1] then return 0 end
+redis.call("HSET", KEYS[1], "status", ARGV[2], "response_code", ARGV[3],
+	"response_payload", ARGV[4], "error_message", ARGV[5], "updated_at", ARGV[6])
+return 1`
+
+	redisTouchLua = `
+local h = redis.call("HMGET", KEYS[1], "status", "updated_at")
+if h[1] ~= "IN_PROGRESS" or h[2] ~= ARGV[1] then return 0 end
+redis.call("HSET", KEYS[1], "updated_at", ARGV[2])
+return 1`
+)
+
+var (
+	redisReserveScript   = redis.NewScript(redisReserveLua)
+	redisReacquireScript = redis.NewScript(redisReacquireLua)
+	redisCompleteScript  = redis.NewScript(redisCompleteLua)
+	redisTouchScript     = redis.NewScript(redisTouchLua)
+)
+
+// redisStoreClient is the part of redis.UniversalClient used by RedisStore.
+type redisStoreClient interface {
+	HGetAll(ctx context.Context, key string) *redis.MapStringStringCmd
+	redis.Scripter
+}
+
+// RedisStore implements Store and LeaseToucher on Redis: one hash per key, expiring at
+// expires_at. Conditional updates run as Lua scripts with the same updated_at checks as
+// PostgresStore. The pg.Runner arguments are ignored; pass nil (see RunnerlessStore).
+type RedisStore struct {
+	rdb    redisStoreClient
+	prefix string
+	clock  timeutil.Clock
+}
+
+type RedisStoreOption func(*RedisStore)
+
+// WithRedisStoreClock sets the time source for created_at/updated_at defaults and completed_at.
+// nil keeps the default (system time).
+func WithRedisStoreClock(c timeutil.Clock) RedisStoreOption {
+	return func(s *RedisStore) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// WithRedisStoreKeyPrefix replaces DefaultRedisKeyPrefix, e.g. to share a Redis between services.
+func WithRedisStoreKeyPrefix(prefix string) RedisStoreOption {
+	return func(s *RedisStore) { s.prefix = prefix }
+}
+
+func NewRedisStore(rdb redis.UniversalClient, opts ...RedisStoreOption) *RedisStore {
+	s := &RedisStore{prefix: DefaultRedisKeyPrefix, clock: timeutil.UTCClock{}}
+	if rdb != nil {
+		s.rdb = rdb
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+var (
+	_ Store           = (*RedisStore)(nil)
+	_ LeaseToucher    = (*RedisStore)(nil)
+	_ RunnerlessStore = (*RedisStore)(nil)
+)
+
+// Runnerless implements RunnerlessStore.
+func (*RedisStore) Runnerless() {}
+
+func (s *RedisStore) Reserve(ctx context.Context, _ pg.Runner, rec Record) (ReserveResult, error) {
+	ctx = ensureContext(ctx)
+
+	if err := s.validateClient(); err != nil {
+		return ReserveResult{}, err
+	}
+	rec, err := prepareReserve(rec, s.nowUTC())
+	if err != nil {
+		return ReserveResult{}, err
+	}
+
+	key := s.key(rec.Principal, rec.GRPCMethod, rec.IdempotencyKey)
+	expireAtMs := (rec.ExpiresAt.UnixMicro() + 999) / 1000
+	created, err := redisReserveScript.Run(ctx, s.rdb, []string{key},
+		expireAtMs,
+		"principal", rec.Principal,
+		"grpc_method", rec.GRPCMethod,
+		"idempotency_key", rec.IdempotencyKey,
+		"request_hash", rec.RequestHash,
+		"status", string(rec.Status),
+		"response_code", strconv.FormatInt(int64(rec.ResponseCode), 10),
+		"response_payload", rec.ResponsePayload,
+		"error_message", rec.ErrorMessage,
+		"created_at", formatMicros(rec.CreatedAt),
+		"updated_at", formatMicros(rec.UpdatedAt),
+		"expires_at", formatMicros(rec.ExpiresAt),
+	).Int64()
+	if err != nil {
+		return ReserveResult{}, err
+	}
+	if created == 1 {
+		if len(rec.ResponsePayload) == 0 {
+			rec.ResponsePayload = nil
+		}
+		return ReserveResult{Reserved: true, Record: &rec}, nil
+	}
+
+	existing, err := s.Get(ctx, nil, rec.Principal, rec.GRPCMethod, rec.IdempotencyKey)
+	if err != nil {
+		return ReserveResult{}, err
+	}
+	return existingReservation(rec, existing)
+}
+
+func (s *RedisStore) Get(ctx context.Context, _ pg.Runner, principal, grpcMethod, idemKey string) (*Record, error) {
+	ctx = ensureContext(ctx)
+
+	if err := s.validateClient(); err != nil {
+		return nil, err
+	}
+	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
+		return nil, err
+	}
+
+	h, err := s.rdb.HGetAll(ctx, s.key(principal, grpcMethod, idemKey)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(h) == 0 {
+		return nil, nil
+	}
+	return recordFromHash(h)
+}
+
+func (s *RedisStore) ReacquireRetryable(ctx context.Context, _ pg.Runner, principal, grpcMethod, idemKey, requestHash string, updatedAt time.Time) (bool, error) {
+	ctx = ensureContext(ctx)
+
+	if err := s.validateClient(); err != nil {
+		return false, err
+	}
+	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
+		return false, err
+	}
+	if requestHash == "" {
+		return false, ErrRequestHashRequired
+	}
+	if updatedAt.IsZero() {
+		return false, ErrUpdatedAtRequired
+	}
+
+	return runCondScript(ctx, s.rdb, redisReacquireScript, s.key(principal, grpcMethod, idemKey),
+		requestHash, formatMicros(normalizeUTC(updatedAt)))
+}
+
+func (s *RedisStore) Complete(ctx context.Context, _ pg.Runner, principal, grpcMethod, idemKey string, done Completion) (bool, error) {
+	ctx = ensureContext(ctx)
+
+	if err := s.validateClient(); err != nil {
+		return false, err
+	}
+	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
+		return false, err
+	}
+	if err := validateCompletion(done); err != nil {
+		return false, err
+	}
+
+	return runCondScript(ctx, s.rdb, redisCompleteScript, s.key(principal, grpcMethod, idemKey),
+		formatMicros(normalizeUTC(done.UpdatedAt)),
+		string(done.Status),
+		strconv.FormatInt(int64(done.ResponseCode), 10),
+		done.ResponsePayload,
+		done.ErrorMessage,
+		formatMicros(s.nowUTC()),
+	)
+}
+
+func (s *RedisStore) TouchLease(ctx context.Context, _ pg.Runner, principal, grpcMethod, idemKey string, prevUpdatedAt, newUpdatedAt time.Time) (bool, error) {
+	ctx = ensureContext(ctx)
+
+	if err := s.validateClient(); err != nil {
+		return false, err
+	}
+	if err := validateIdentity(principal, grpcMethod, idemKey); err != nil {
+		return false, err
+	}
+	if prevUpdatedAt.IsZero() || newUpdatedAt.IsZero() {
+		return false, ErrUpdatedAtRequired
+	}
+	prevUpdatedAt = normalizeUTC(prevUpdatedAt)
+	newUpdatedAt = normalizeUTC(newUpdatedAt)
+	if !newUpdatedAt.After(prevUpdatedAt) {
+		return false, ErrUpdatedAtNotAdvanced
+	}
+
+	return runCondScript(ctx, s.rdb, redisTouchScript, s.key(principal, grpcMethod, idemKey),
+		formatMicros(prevUpdatedAt), formatMicros(newUpdatedAt))
+}
+
+// DeleteExpired is a no-op: Redis drops each key at its expires_at, so a Sweeper is not needed.
+// Unlike PostgresStore, IN_PROGRESS records expire too.
+func (s *RedisStore) DeleteExpired(context.Context, pg.Runner, time.Time) (int64, error) {
+	if err := s.validateClient(); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+// key length-prefixes principal and method, so values containing ':' cannot collide.
+func (s *RedisStore) key(principal, grpcMethod, idemKey string) string {
+	return fmt.Sprintf("%s%d:%s:%d:%s:%s", s.prefix, len(principal), principal, len(grpcMethod), grpcMethod, idemKey)
+}
+
+func (s *RedisStore) validateClient() error {
+	if s == nil || s.rdb == nil {
+		return ErrNilRedisClient
+	}
+	return nil
+}
+
+func (s *RedisStore) nowUTC() time.Time {
+	if s == nil || s.clock == nil {
+		return normalizeUTC(time.Now())
+	}
+	return normalizeUTC(s.clock.Now())
+}
+
+func runCondScript(ctx context.Context, rdb redis.Scripter, script *redis.Script, key string, args ...any) (bool, error) {
+	n, err := script.Run(ctx, rdb, []string{key}, args...).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func recordFromHash(h map[string]string) (*Record, error) {
+	code, err := strconv.ParseInt(h["response_code"], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: response_code: %v", ErrInconsistentState, err)
+	}
+	var ts [3]time.Time
+	for i, f := range []string{"created_at", "updated_at", "expires_at"} {
+		us, err := strconv.ParseInt(h[f], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInconsistentState, f, err)
+		}
+		ts[i] = time.UnixMicro(us).UTC()
+	}
+
+	rec := &Record{
+		Principal:      h["principal"],
+		GRPCMethod:     h["grpc_method"],
+		IdempotencyKey: h["idempotency_key"],
+		RequestHash:    h["request_hash"],
+		Status:         Status(h["status"]),
+		ResponseCode:   int32(code),
+		ErrorMessage:   h["error_message"],
+		CreatedAt:      ts[0],
+		UpdatedAt:      ts[1],
+		ExpiresAt:      ts[2],
+	}
+	if p := h["response_payload"]; p != "" {
+		rec.ResponsePayload = []byte(p)
+	}
+	return rec, nil
+}
+
+func formatMicros(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 10)
+}
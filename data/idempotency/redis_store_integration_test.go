@@ -0,0 +1,159 @@
+//go:build integration
+
+package idempotency_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"github.com/vortex-fintech/go-lib/data/idempotency"
+	redispkg "github.com/vortex-fintech/go-lib/data/redis"
+)
+
+func TestRedisStore_RequestHashMismatch_Integration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := idempotency.NewRedisStore(openIntegrationRedis(t), idempotency.WithRedisStoreKeyPrefix(integrationRedisPrefix()))
+	expiresAt := time.Now().UTC().Add(30 * time.Minute)
+
+	res, err := s.Reserve(ctx, nil, idempotency.Record{
+		Principal:      "merchant-1",
+		GRPCMethod:     "/payments.v1.Payments/Authorize",
+		IdempotencyKey: "idem-hash-mismatch",
+		RequestHash:    "hash-v1",
+		ExpiresAt:      expiresAt,
+	})
+	require.NoError(t, err)
+	require.True(t, res.Reserved)
+
+	again, err := s.Reserve(ctx, nil, idempotency.Record{
+		Principal:      "merchant-1",
+		GRPCMethod:     "/payments.v1.Payments/Authorize",
+		IdempotencyKey: "idem-hash-mismatch",
+		RequestHash:    "hash-v1",
+		ExpiresAt:      expiresAt,
+	})
+	require.NoError(t, err)
+	require.False(t, again.Reserved)
+	require.Equal(t, res.Record.UpdatedAt, again.Record.UpdatedAt)
+
+	_, err = s.Reserve(ctx, nil, idempotency.Record{
+		Principal:      "merchant-1",
+		GRPCMethod:     "/payments.v1.Payments/Authorize",
+		IdempotencyKey: "idem-hash-mismatch",
+		RequestHash:    "hash-v2",
+		ExpiresAt:      expiresAt,
+	})
+	require.ErrorIs(t, err, idempotency.ErrRequestHashMismatch)
+}
+
+func TestRedisStore_StaleCompletionRejectedAfterReacquire_Integration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := idempotency.NewRedisStore(openIntegrationRedis(t), idempotency.WithRedisStoreKeyPrefix(integrationRedisPrefix()))
+	expiresAt := time.Now().UTC().Add(30 * time.Minute)
+
+	reserved, err := s.Reserve(ctx, nil, idempotency.Record{
+		Principal:      "merchant-2",
+		GRPCMethod:     "/payments.v1.Payments/Capture",
+		IdempotencyKey: "idem-stale-complete",
+		RequestHash:    "hash-capture",
+		ExpiresAt:      expiresAt,
+	})
+	require.NoError(t, err)
+	require.True(t, reserved.Reserved)
+	require.NotNil(t, reserved.Record)
+
+	firstLease := reserved.Record.UpdatedAt
+
+	ok, err := s.Complete(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete", idempotency.Completion{
+		Status:    idempotency.StatusFailedRetry,
+		UpdatedAt: firstLease,
+	})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	rec, err := s.Get(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+
+	secondLease := rec.UpdatedAt.Add(2 * time.Second)
+	ok, err = s.ReacquireRetryable(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete", "hash-capture", secondLease)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	staleOK, err := s.Complete(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete", idempotency.Completion{
+		Status:    idempotency.StatusSucceeded,
+		UpdatedAt: firstLease,
+	})
+	require.NoError(t, err)
+	require.False(t, staleOK, "stale worker must not complete newer attempt")
+
+	freshOK, err := s.Complete(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete", idempotency.Completion{
+		Status:          idempotency.StatusSucceeded,
+		UpdatedAt:       secondLease,
+		ResponsePayload: []byte("captured"),
+	})
+	require.NoError(t, err)
+	require.True(t, freshOK)
+
+	finalRec, err := s.Get(ctx, nil, "merchant-2", "/payments.v1.Payments/Capture", "idem-stale-complete")
+	require.NoError(t, err)
+	require.NotNil(t, finalRec)
+	require.Equal(t, idempotency.StatusSucceeded, finalRec.Status)
+	require.Equal(t, []byte("captured"), finalRec.ResponsePayload)
+}
+
+func TestRedisStore_KeyExpiresAtExpiresAt_Integration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := idempotency.NewRedisStore(openIntegrationRedis(t), idempotency.WithRedisStoreKeyPrefix(integrationRedisPrefix()))
+
+	_, err := s.Reserve(ctx, nil, idempotency.Record{
+		Principal:      "merchant-3",
+		GRPCMethod:     "/payments.v1.Payments/Refund",
+		IdempotencyKey: "idem-ttl",
+		RequestHash:    "hash-refund",
+		ExpiresAt:      time.Now().UTC().Add(300 * time.Millisecond),
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		rec, err := s.Get(ctx, nil, "merchant-3", "/payments.v1.Payments/Refund", "idem-ttl")
+		return err == nil && rec == nil
+	}, 3*time.Second, 50*time.Millisecond)
+}
+
+func openIntegrationRedis(t *testing.T) redis.UniversalClient {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	c, err := redispkg.NewRedisClient(ctx, redispkg.Config{Mode: redispkg.ModeSingle, Addr: integrationRedisAddr()})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = c.Close()
+	})
+	return c
+}
+
+// integrationRedisPrefix isolates each test run, so no cleanup of earlier keys is needed.
+func integrationRedisPrefix() string {
+	return fmt.Sprintf("go-lib:data:idempotency:it:%d:", time.Now().UnixNano())
+}
+
+func integrationRedisAddr() string {
+	if v := os.Getenv("REDIS_TEST_ADDR"); v != "" {
+		return v
+	}
+	return "localhost:6380"
+}
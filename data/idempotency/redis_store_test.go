@@ -0,0 +1,378 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
+)
+
+// memIdemRedis emulates HGETALL and the RedisStore scripts in memory.
+type memIdemRedis struct {
+	mu       sync.Mutex
+	hashes   map[string]map[string]string
+	expireAt map[string]int64
+	err      error
+}
+
+func newMemIdemRedis() *memIdemRedis {
+	return &memIdemRedis{hashes: map[string]map[string]string{}, expireAt: map[string]int64{}}
+}
+
+func (c *memIdemRedis) HGetAll(_ context.Context, key string) *redis.MapStringStringCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return redis.NewMapStringStringResult(nil, c.err)
+	}
+	out := map[string]string{}
+	for k, v := range c.hashes[key] {
+		out[k] = v
+	}
+	return redis.NewMapStringStringResult(out, nil)
+}
+
+func (c *memIdemRedis) Eval(_ context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return redis.NewCmdResult(nil, c.err)
+	}
+	argv := make([]string, len(args))
+	for i, a := range args {
+		if b, ok := a.([]byte); ok {
+			argv[i] = string(b)
+		} else {
+			argv[i] = fmt.Sprint(a)
+		}
+	}
+	h, exists := c.hashes[keys[0]]
+	num := func(f string) int64 { n, _ := strconv.ParseInt(h[f], 10, 64); return n }
+
+	switch script {
+	case redisReserveLua:
+		if exists {
+			return redis.NewCmdResult(int64(0), nil)
+		}
+		h = map[string]string{}
+		for i := 1; i+1 < len(argv); i += 2 {
+			h[argv[i]] = argv[i+1]
+		}
+		c.hashes[keys[0]] = h
+		c.expireAt[keys[0]], _ = strconv.ParseInt(argv[0], 10, 64)
+	case redisReacquireLua:
+		at, _ := strconv.ParseInt(argv[1], 10, 64)
+		if !exists || h["request_hash"] != argv[0] || h["status"] != string(StatusFailedRetry) ||
+			num("expires_at") <= at || num("updated_at") >= at {
+			return redis.NewCmdResult(int64(0), nil)
+		}
+		h["status"], h["response_code"], h["response_payload"], h["error_message"], h["updated_at"] =
+			string(StatusInProgress), "0", "", "", argv[1]
+	case redisCompleteLua:
+		if !exists || h["status"] != string(StatusInProgress) || h["updated_at"] != argv[0] {
+			return redis.NewCmdResult(int64(0), nil)
+		}
+		h["status"], h["response_code"], h["response_payload"], h["error_message"], h["updated_at"] =
+			argv[1], argv[2], argv[3], argv[4], argv[5]
+	case redisTouchLua:
+		if !exists || h["status"] != string(StatusInProgress) || h["updated_at"] != argv[0] {
+			return redis.NewCmdResult(int64(0), nil)
+		}
+		h["updated_at"] = argv[1]
+	default:
+		return redis.NewCmdResult(nil, errors.New("unknown script"))
+	}
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func (c *memIdemRedis) EvalSha(ctx context.Context, sha string, keys []string, args ...any) *redis.Cmd {
+	for _, src := range []string{redisReserveLua, redisReacquireLua, redisCompleteLua, redisTouchLua} {
+		if h := sha1.Sum([]byte(src)); hex.EncodeToString(h[:]) == sha {
+			return c.Eval(ctx, src, keys, args...)
+		}
+	}
+	return redis.NewCmdResult(nil, errors.New("unknown sha"))
+}
+
+func (c *memIdemRedis) EvalRO(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	return c.Eval(ctx, script, keys, args...)
+}
+
+func (c *memIdemRedis) EvalShaRO(ctx context.Context, sha string, keys []string, args ...any) *redis.Cmd {
+	return c.EvalSha(ctx, sha, keys, args...)
+}
+
+func (c *memIdemRedis) ScriptExists(_ context.Context, hashes ...string) *redis.BoolSliceCmd {
+	return redis.NewBoolSliceResult(make([]bool, len(hashes)), nil)
+}
+
+func (c *memIdemRedis) ScriptLoad(_ context.Context, script string) *redis.StringCmd {
+	h := sha1.Sum([]byte(script))
+	return redis.NewStringResult(hex.EncodeToString(h[:]), nil)
+}
+
+func newTestRedisStore(rdb *memIdemRedis, opts ...RedisStoreOption) *RedisStore {
+	s := NewRedisStore(nil, opts...)
+	s.rdb = rdb
+	return s
+}
+
+func TestRedisStore_ReserveConflictReturnsExisting(t *testing.T) {
+	t.Parallel()
+
+	frozen := time.Date(2026, 3, 4, 5, 6, 7, 123456789, time.UTC)
+	rdb := newMemIdemRedis()
+	s := newTestRedisStore(rdb, WithRedisStoreClock(timeutil.NewFrozenClock(frozen)))
+	rec := Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      frozen.Add(time.Minute),
+	}
+
+	res, err := s.Reserve(context.Background(), nil, rec)
+	if err != nil || !res.Reserved || res.Record == nil {
+		t.Fatalf("expected reserved, got %+v err=%v", res, err)
+	}
+	want := frozen.Truncate(time.Microsecond)
+	if res.Record.Status != StatusInProgress || !res.Record.UpdatedAt.Equal(want) || !res.Record.CreatedAt.Equal(want) {
+		t.Fatalf("unexpected record %+v", res.Record)
+	}
+	key := "idempotency:2:u1:11:/svc.Method:k1"
+	if got, wantMs := rdb.expireAt[key], frozen.Add(time.Minute).UnixMilli()+1; got != wantMs {
+		t.Fatalf("expire at = %d, want %d (rounded up to ms)", got, wantMs)
+	}
+
+	again, err := s.Reserve(context.Background(), nil, rec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Reserved || again.Record == nil || again.Record.Status != StatusInProgress || !again.Record.UpdatedAt.Equal(want) {
+		t.Fatalf("expected existing record, got %+v", again)
+	}
+
+	rec.RequestHash = "h2"
+	if _, err := s.Reserve(context.Background(), nil, rec); !errors.Is(err, ErrRequestHashMismatch) {
+		t.Fatalf("expected ErrRequestHashMismatch, got %v", err)
+	}
+}
+
+func TestRedisStore_StaleCompletionRejectedAfterReacquire(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestRedisStore(newMemIdemRedis())
+	res, err := s.Reserve(ctx, nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Capture",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      time.Now().Add(time.Hour),
+	})
+	if err != nil || !res.Reserved {
+		t.Fatalf("reserve: %+v err=%v", res, err)
+	}
+	firstLease := res.Record.UpdatedAt
+
+	ok, err := s.Complete(ctx, nil, "u1", "/svc.Capture", "k1", Completion{Status: StatusFailedRetry, UpdatedAt: firstLease, ErrorMessage: "unavailable"})
+	if err != nil || !ok {
+		t.Fatalf("complete retryable: ok=%v err=%v", ok, err)
+	}
+
+	if ok, err := s.ReacquireRetryable(ctx, nil, "u1", "/svc.Capture", "k1", "other-hash", firstLease.Add(time.Second)); err != nil || ok {
+		t.Fatalf("reacquire with other hash: ok=%v err=%v", ok, err)
+	}
+	secondLease := firstLease.Add(2 * time.Second)
+	if ok, err := s.ReacquireRetryable(ctx, nil, "u1", "/svc.Capture", "k1", "h1", secondLease); err != nil || !ok {
+		t.Fatalf("reacquire: ok=%v err=%v", ok, err)
+	}
+	got, err := s.Get(ctx, nil, "u1", "/svc.Capture", "k1")
+	if err != nil || got.Status != StatusInProgress || got.ErrorMessage != "" || !got.UpdatedAt.Equal(secondLease) {
+		t.Fatalf("after reacquire: %+v err=%v", got, err)
+	}
+
+	if ok, err := s.Complete(ctx, nil, "u1", "/svc.Capture", "k1", Completion{Status: StatusSucceeded, UpdatedAt: firstLease}); err != nil || ok {
+		t.Fatalf("stale complete must be rejected: ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.Complete(ctx, nil, "u1", "/svc.Capture", "k1", Completion{Status: StatusSucceeded, UpdatedAt: secondLease, ResponsePayload: []byte("resp")}); err != nil || !ok {
+		t.Fatalf("fresh complete: ok=%v err=%v", ok, err)
+	}
+
+	final, err := s.Get(ctx, nil, "u1", "/svc.Capture", "k1")
+	if err != nil || final.Status != StatusSucceeded || string(final.ResponsePayload) != "resp" {
+		t.Fatalf("final record: %+v err=%v", final, err)
+	}
+}
+
+func TestRedisStore_ReacquireRetryableGuards(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	s := newTestRedisStore(newMemIdemRedis())
+	if _, err := s.Reserve(ctx, nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		Status:         StatusFailedRetry,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		ExpiresAt:      now.Add(time.Minute),
+	}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"not after updated_at", now, false},
+		{"at expires_at", now.Add(time.Minute), false},
+		{"inside window", now.Add(time.Second), true},
+		{"already in progress", now.Add(2 * time.Second), false},
+	}
+	for _, tc := range cases {
+		ok, err := s.ReacquireRetryable(ctx, nil, "u1", "/svc.Method", "k1", "h1", tc.at)
+		if err != nil || ok != tc.want {
+			t.Fatalf("%s: ok=%v err=%v, want %v", tc.name, ok, err, tc.want)
+		}
+	}
+	if ok, err := s.ReacquireRetryable(ctx, nil, "u1", "/svc.Method", "missing", "h1", now.Add(time.Second)); err != nil || ok {
+		t.Fatalf("missing key: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRedisStore_TouchLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := newTestRedisStore(newMemIdemRedis())
+	res, err := s.Reserve(ctx, nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	prev := res.Record.UpdatedAt
+	next := prev.Add(time.Second)
+
+	if ok, err := s.TouchLease(ctx, nil, "u1", "/svc.Method", "k1", prev, next); err != nil || !ok {
+		t.Fatalf("touch: ok=%v err=%v", ok, err)
+	}
+	if ok, err := s.TouchLease(ctx, nil, "u1", "/svc.Method", "k1", prev, next.Add(time.Second)); err != nil || ok {
+		t.Fatalf("stale touch: ok=%v err=%v", ok, err)
+	}
+	if _, err := s.TouchLease(ctx, nil, "u1", "/svc.Method", "k1", next, next); !errors.Is(err, ErrUpdatedAtNotAdvanced) {
+		t.Fatalf("expected ErrUpdatedAtNotAdvanced, got %v", err)
+	}
+	if ok, err := s.Complete(ctx, nil, "u1", "/svc.Method", "k1", Completion{Status: StatusSucceeded, UpdatedAt: next}); err != nil || !ok {
+		t.Fatalf("complete after touch: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRedisStore_GetNotFound(t *testing.T) {
+	t.Parallel()
+
+	s := newTestRedisStore(newMemIdemRedis())
+	rec, err := s.Get(context.Background(), nil, "u1", "/svc.Method", "k1")
+	if err != nil || rec != nil {
+		t.Fatalf("expected nil record, got %+v err=%v", rec, err)
+	}
+}
+
+func TestRedisStore_GetCorruptHash(t *testing.T) {
+	t.Parallel()
+
+	rdb := newMemIdemRedis()
+	s := newTestRedisStore(rdb)
+	rdb.hashes[s.key("u1", "/svc.Method", "k1")] = map[string]string{"status": "IN_PROGRESS", "response_code": "x"}
+
+	if _, err := s.Get(context.Background(), nil, "u1", "/svc.Method", "k1"); !errors.Is(err, ErrInconsistentState) {
+		t.Fatalf("expected ErrInconsistentState, got %v", err)
+	}
+}
+
+func TestRedisStore_KeyIsUnambiguous(t *testing.T) {
+	t.Parallel()
+
+	s := NewRedisStore(nil, WithRedisStoreKeyPrefix("svc:"))
+	if a, b := s.key("a:b", "c", "k"), s.key("a", "b:c", "k"); a == b {
+		t.Fatalf("keys collide: %q", a)
+	}
+	if got := s.key("u1", "/m", "k1"); got != "svc:2:u1:2:/m:k1" {
+		t.Fatalf("key = %q", got)
+	}
+}
+
+func TestRedisStore_Validation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var nilStore *RedisStore
+	if _, err := nilStore.Get(ctx, nil, "u1", "/m", "k1"); !errors.Is(err, ErrNilRedisClient) {
+		t.Fatalf("nil store: expected ErrNilRedisClient, got %v", err)
+	}
+	if _, err := NewRedisStore(nil).Reserve(ctx, nil, Record{}); !errors.Is(err, ErrNilRedisClient) {
+		t.Fatalf("nil client: expected ErrNilRedisClient, got %v", err)
+	}
+
+	s := newTestRedisStore(newMemIdemRedis())
+	now := time.Now()
+	if _, err := s.Reserve(ctx, nil, Record{Principal: "u1", GRPCMethod: "/m", IdempotencyKey: "k1", RequestHash: "h1"}); !errors.Is(err, ErrExpiresAtRequired) {
+		t.Fatalf("expected ErrExpiresAtRequired, got %v", err)
+	}
+	if _, err := s.Get(ctx, nil, "", "/m", "k1"); err == nil {
+		t.Fatal("expected identity error")
+	}
+	if _, err := s.ReacquireRetryable(ctx, nil, "u1", "/m", "k1", "", now); !errors.Is(err, ErrRequestHashRequired) {
+		t.Fatalf("expected ErrRequestHashRequired, got %v", err)
+	}
+	if _, err := s.ReacquireRetryable(ctx, nil, "u1", "/m", "k1", "h1", time.Time{}); !errors.Is(err, ErrUpdatedAtRequired) {
+		t.Fatalf("expected ErrUpdatedAtRequired, got %v", err)
+	}
+	if _, err := s.Complete(ctx, nil, "u1", "/m", "k1", Completion{Status: StatusInProgress, UpdatedAt: now}); !errors.Is(err, ErrCompletionNotTerminal) {
+		t.Fatalf("expected ErrCompletionNotTerminal, got %v", err)
+	}
+	if _, err := s.Complete(ctx, nil, "u1", "/m", "k1", Completion{Status: StatusSucceeded}); !errors.Is(err, ErrUpdatedAtRequired) {
+		t.Fatalf("expected ErrUpdatedAtRequired, got %v", err)
+	}
+	if n, err := s.DeleteExpired(ctx, nil, now); err != nil || n != 0 {
+		t.Fatalf("DeleteExpired: n=%d err=%v", n, err)
+	}
+}
+
+func TestRedisStore_PropagatesRedisError(t *testing.T) {
+	t.Parallel()
+
+	rdb := newMemIdemRedis()
+	rdb.err = errors.New("boom")
+	s := newTestRedisStore(rdb)
+
+	if _, err := s.Reserve(context.Background(), nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/m",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}); err == nil || err.Error() != "boom" {
+		t.Fatalf("expected redis error, got %v", err)
+	}
+	if _, err := s.Get(context.Background(), nil, "u1", "/m", "k1"); err == nil {
+		t.Fatal("expected redis error from Get")
+	}
+}
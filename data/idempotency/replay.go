@@ -0,0 +1,26 @@
+package idempotency
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r Record) ReplayResponse() ([]byte, *status.Status, error) {
+	switch r.Status {
+	case StatusSucceeded:
+		if r.ResponseCode != int32(codes.OK) {
+			return nil, nil, fmt.Errorf("%w: succeeded record with response code %d", ErrInconsistentState, r.ResponseCode)
+		}
+		return r.ResponsePayload, status.New(codes.OK, ""), nil
+	case StatusFailedFinal:
+		code := codes.Code(r.ResponseCode)
+		if r.ResponseCode <= 0 {
+			code = codes.Unknown
+		}
+		return r.ResponsePayload, status.New(code, r.ErrorMessage), nil
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrNotReplayable, r.Status)
+	}
+}
@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecordReplayResponse_SucceededWithPayload(t *testing.T) {
+	t.Parallel()
+
+	rec := Record{Status: StatusSucceeded, ResponsePayload: []byte("resp")}
+	payload, st, err := rec.ReplayResponse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(payload, []byte("resp")) {
+		t.Fatalf("unexpected payload: %q", payload)
+	}
+	if st.Code() != codes.OK || st.Err() != nil {
+		t.Fatalf("expected OK status, got %v", st.Err())
+	}
+}
+
+func TestRecordReplayResponse_FailedFinalWithStatus(t *testing.T) {
+	t.Parallel()
+
+	rec := Record{
+		Status:       StatusFailedFinal,
+		ResponseCode: int32(codes.FailedPrecondition),
+		ErrorMessage: "insufficient funds",
+	}
+	payload, st, err := rec.ReplayResponse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if payload != nil {
+		t.Fatalf("expected nil payload, got %q", payload)
+	}
+	if st.Code() != codes.FailedPrecondition || st.Message() != "insufficient funds" {
+		t.Fatalf("unexpected status: %v", st.Err())
+	}
+	if got := status.Code(st.Err()); got != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition error, got %v", got)
+	}
+}
+
+func TestRecordReplayResponse_FailedFinalWithoutCodeIsUnknown(t *testing.T) {
+	t.Parallel()
+
+	_, st, err := Record{Status: StatusFailedFinal, ErrorMessage: "boom"}.ReplayResponse()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Code() != codes.Unknown {
+		t.Fatalf("expected Unknown, got %v", st.Code())
+	}
+}
+
+func TestRecordReplayResponse_RejectsNonReplayable(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []Status{StatusInProgress, StatusFailedRetry, ""} {
+		_, _, err := Record{Status: s}.ReplayResponse()
+		if !errors.Is(err, ErrNotReplayable) {
+			t.Fatalf("status %q: expected ErrNotReplayable, got %v", s, err)
+		}
+	}
+
+	_, _, err := Record{Status: StatusSucceeded, ResponseCode: int32(codes.Internal)}.ReplayResponse()
+	if !errors.Is(err, ErrInconsistentState) {
+		t.Fatalf("expected ErrInconsistentState, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package idempotency
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	pg "github.com/vortex-fintech/go-lib/data/postgres"
@@ -31,6 +32,12 @@ var (
 	ErrCompletionNotTerminal  = errors.New("idempotency: completion status must be terminal")
 	ErrRequestHashMismatch    = errors.New("idempotency: idempotency key reused with different request hash")
 	ErrInconsistentState      = errors.New("idempotency: inconsistent state")
+	ErrBatchSizeInvalid       = errors.New("idempotency: batch size must be positive")
+	ErrUpdatedAtNotAdvanced   = errors.New("idempotency: new updated_at must be after previous updated_at")
+	ErrNilExecuteFunc         = errors.New("idempotency: execute func is required")
+	ErrLeaseLost              = errors.New("idempotency: lease lost before completion")
+	ErrNotReplayable          = errors.New("idempotency: record is not replayable")
+	ErrIllegalTransition      = errors.New("idempotency: illegal status transition")
 )
 
 func (s Status) IsValid() bool {
@@ -51,6 +58,36 @@ func (s Status) IsTerminal() bool {
 	}
 }
 
+// CanTransition reports whether a record may move from one status to another.
+// Records are created IN_PROGRESS by Reserve; Complete moves IN_PROGRESS to a
+// terminal status and ReacquireRetryable moves FAILED_RETRYABLE back to IN_PROGRESS.
+// Same-status pairs are not transitions (TouchLease keeps IN_PROGRESS) and return false.
+func CanTransition(from, to Status) bool {
+	switch from {
+	case StatusInProgress:
+		return to == StatusSucceeded || to == StatusFailedRetry || to == StatusFailedFinal
+	case StatusFailedRetry:
+		return to == StatusInProgress
+	default:
+		return false
+	}
+}
+
+// ValidateTransition is CanTransition as an error: ErrInvalidStatus for unknown
+// statuses, ErrIllegalTransition for known but disallowed pairs.
+func ValidateTransition(from, to Status) error {
+	if !from.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, from)
+	}
+	if !to.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, to)
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, from, to)
+	}
+	return nil
+}
+
 type Record struct {
 	Principal       string
 	GRPCMethod      string
@@ -86,6 +123,23 @@ type Store interface {
 	DeleteExpired(ctx context.Context, run pg.Runner, before time.Time) (int64, error)
 }
 
+type LeaseToucher interface {
+	TouchLease(ctx context.Context, run pg.Runner, principal, grpcMethod, idemKey string, prevUpdatedAt, newUpdatedAt time.Time) (bool, error)
+}
+
+// RunnerlessStore marks a Store that ignores its pg.Runner arguments (e.g. RedisStore),
+// so callers such as Sweeper and the gRPC middleware may pass nil.
+type RunnerlessStore interface {
+	Store
+	Runnerless()
+}
+
+// NeedsRunner reports whether store uses its pg.Runner arguments.
+func NeedsRunner(store Store) bool {
+	_, ok := store.(RunnerlessStore)
+	return !ok
+}
+
 func ensureContext(ctx context.Context) context.Context {
 	if ctx == nil {
 		return context.Background()
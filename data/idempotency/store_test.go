@@ -0,0 +1,52 @@
+package idempotency
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanTransition_AllPairs(t *testing.T) {
+	t.Parallel()
+
+	statuses := []Status{StatusInProgress, StatusSucceeded, StatusFailedRetry, StatusFailedFinal}
+	legal := map[[2]Status]bool{
+		{StatusInProgress, StatusSucceeded}:   true,
+		{StatusInProgress, StatusFailedRetry}: true,
+		{StatusInProgress, StatusFailedFinal}: true,
+		{StatusFailedRetry, StatusInProgress}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := legal[[2]Status{from, to}]
+			if got := CanTransition(from, to); got != want {
+				t.Fatalf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+
+			err := ValidateTransition(from, to)
+			switch {
+			case want && err != nil:
+				t.Fatalf("ValidateTransition(%s, %s) = %v, want nil", from, to, err)
+			case !want && !errors.Is(err, ErrIllegalTransition):
+				t.Fatalf("ValidateTransition(%s, %s) = %v, want ErrIllegalTransition", from, to, err)
+			}
+		}
+	}
+}
+
+func TestValidateTransition_InvalidStatus(t *testing.T) {
+	t.Parallel()
+
+	for _, pair := range [][2]Status{
+		{"", StatusInProgress},
+		{StatusInProgress, "DONE"},
+		{"unknown", "unknown"},
+	} {
+		if CanTransition(pair[0], pair[1]) {
+			t.Fatalf("CanTransition(%q, %q) = true, want false", pair[0], pair[1])
+		}
+		if err := ValidateTransition(pair[0], pair[1]); !errors.Is(err, ErrInvalidStatus) {
+			t.Fatalf("ValidateTransition(%q, %q) = %v, want ErrInvalidStatus", pair[0], pair[1], err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pg "github.com/vortex-fintech/go-lib/data/postgres"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
+)
+
+var (
+	ErrNilRunnerProvider    = errors.New("idempotency: runner provider is required")
+	ErrSweepIntervalInvalid = errors.New("idempotency: sweep interval must be positive")
+	ErrSweeperStarted       = errors.New("idempotency: sweeper already started")
+)
+
+// SweeperMetrics observes sweeper runs. Implement it to export deleted counts and failures.
+type SweeperMetrics interface {
+	ObserveDeleted(n int64)
+	IncSweepError()
+}
+
+type SweeperOption func(*Sweeper)
+
+// WithSweeperMetrics sets the metrics hook. nil disables metrics.
+func WithSweeperMetrics(m SweeperMetrics) SweeperOption {
+	return func(s *Sweeper) { s.metrics = m }
+}
+
+// WithSweeperMaxBackoff caps the wait after consecutive failures.
+// Values below the interval are ignored.
+func WithSweeperMaxBackoff(d time.Duration) SweeperOption {
+	return func(s *Sweeper) {
+		if d > 0 {
+			s.maxBackoff = d
+		}
+	}
+}
+
+// WithSweeperClock sets the time source for the DeleteExpired cutoff. nil keeps system time.
+func WithSweeperClock(c timeutil.Clock) SweeperOption {
+	return func(s *Sweeper) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// Sweeper calls Store.DeleteExpired every interval until stopped.
+// It implements the runtime/shutdown Server interface (Serve, GracefulStopWithTimeout,
+// ForceStop, Name), so it can be registered with shutdown.Manager.
+type Sweeper struct {
+	store          Store
+	runnerProvider func() pg.Runner
+	interval       time.Duration
+	maxBackoff     time.Duration
+	clock          timeutil.Clock
+	metrics        SweeperMetrics
+
+	started   atomic.Bool
+	stop      chan struct{}
+	stopOnce  sync.Once
+	force     chan struct{}
+	forceOnce sync.Once
+	done      chan struct{}
+}
+
+// NewSweeper creates a sweeper. runnerProvider is called before every sweep, so it may
+// hand out a fresh pool or connection; it may be nil for a RunnerlessStore. After an error the next sweep waits twice as long
+// as the previous one, up to the max backoff (10 * interval by default).
+func NewSweeper(store Store, runnerProvider func() pg.Runner, interval time.Duration, opts ...SweeperOption) *Sweeper {
+	s := &Sweeper{
+		store:          store,
+		runnerProvider: runnerProvider,
+		interval:       interval,
+		maxBackoff:     10 * interval,
+		clock:          timeutil.UTCClock{},
+		stop:           make(chan struct{}),
+		force:          make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	if s.maxBackoff < s.interval {
+		s.maxBackoff = s.interval
+	}
+	return s
+}
+
+// Run sweeps once immediately and then every interval. It returns nil when ctx is done
+// or Stop is called; a sweep in progress finishes first unless ForceStop is called.
+// Sweep errors are reported to metrics and retried with backoff, never returned.
+func (s *Sweeper) Run(ctx context.Context) error {
+	if err := validateStore(s.store); err != nil {
+		return err
+	}
+	if s.runnerProvider == nil && NeedsRunner(s.store) {
+		return ErrNilRunnerProvider
+	}
+	if s.interval <= 0 {
+		return ErrSweepIntervalInvalid
+	}
+	if !s.started.CompareAndSwap(false, true) {
+		return ErrSweeperStarted
+	}
+	defer close(s.done)
+
+	runCtx, cancel := context.WithCancel(ensureContext(ctx))
+	defer cancel()
+	go func() {
+		select {
+		case <-s.force:
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	wait := s.interval
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-runCtx.Done():
+			return nil
+		case <-s.stop:
+			return nil
+		case <-timer.C:
+		}
+
+		if err := s.sweep(runCtx); err != nil {
+			if runCtx.Err() != nil {
+				return nil
+			}
+			if s.metrics != nil {
+				s.metrics.IncSweepError()
+			}
+			wait = min(wait*2, s.maxBackoff)
+		} else {
+			wait = s.interval
+		}
+		timer.Reset(wait)
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) error {
+	var run pg.Runner
+	if s.runnerProvider != nil {
+		run = s.runnerProvider()
+	}
+	if NeedsRunner(s.store) {
+		if err := validateRunner(run); err != nil {
+			return err
+		}
+	}
+	n, err := s.store.DeleteExpired(ctx, run, s.clock.Now().UTC())
+	if err != nil {
+		return err
+	}
+	if s.metrics != nil {
+		s.metrics.ObserveDeleted(n)
+	}
+	return nil
+}
+
+// Stop asks Run to return after the current sweep. Safe to call more than once.
+func (s *Sweeper) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Serve is Run; it lets the sweeper be used as a shutdown.Server.
+func (s *Sweeper) Serve(ctx context.Context) error {
+	return s.Run(ctx)
+}
+
+// GracefulStopWithTimeout calls Stop and waits for Run to return or ctx to be done.
+func (s *Sweeper) GracefulStopWithTimeout(ctx context.Context) error {
+	ctx = ensureContext(ctx)
+	s.Stop()
+	if !s.started.Load() {
+		return nil
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ForceStop stops the sweeper and cancels a sweep in progress.
+func (s *Sweeper) ForceStop() {
+	s.Stop()
+	s.forceOnce.Do(func() { close(s.force) })
+}
+
+func (s *Sweeper) Name() string {
+	return "idempotency-sweeper"
+}
@@ -0,0 +1,278 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	pg "github.com/vortex-fintech/go-lib/data/postgres"
+	"github.com/vortex-fintech/go-lib/foundation/timeutil"
+)
+
+type sweeperMetricsStub struct {
+	mu      sync.Mutex
+	deleted []int64
+	errors  int
+}
+
+func (m *sweeperMetricsStub) ObserveDeleted(n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, n)
+}
+
+func (m *sweeperMetricsStub) IncSweepError() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors++
+}
+
+func (m *sweeperMetricsStub) snapshot() ([]int64, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int64(nil), m.deleted...), m.errors
+}
+
+func runSweeper(t *testing.T, ctx context.Context, s *Sweeper) <-chan error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Run(ctx) }()
+	return errCh
+}
+
+func waitForCalls(t *testing.T, st *workflowStoreStub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(st.deleteExpiredCalls()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at least %d DeleteExpired calls, got %d", n, len(st.deleteExpiredCalls()))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func waitRunReturned(t *testing.T, errCh <-chan error) {
+	t.Helper()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func TestSweeper_RunsOnInterval(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	st := &workflowStoreStub{deleteN: 3}
+	metrics := &sweeperMetricsStub{}
+	var providerCalls int
+	var providerMu sync.Mutex
+	provider := func() pg.Runner {
+		providerMu.Lock()
+		defer providerMu.Unlock()
+		providerCalls++
+		return &runnerStub{}
+	}
+
+	s := NewSweeper(st, provider, 10*time.Millisecond,
+		WithSweeperMetrics(metrics),
+		WithSweeperClock(timeutil.NewFrozenClock(now)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	start := time.Now()
+	errCh := runSweeper(t, ctx, s)
+
+	waitForCalls(t, st, 3)
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("3 sweeps must take at least 2 intervals, took %v", elapsed)
+	}
+	cancel()
+	waitRunReturned(t, errCh)
+
+	calls := st.deleteExpiredCalls()
+	for i, before := range calls {
+		if !before.Equal(now) {
+			t.Fatalf("call %d: expected cutoff %v, got %v", i, now, before)
+		}
+	}
+	providerMu.Lock()
+	if providerCalls != len(calls) {
+		t.Fatalf("expected runner provider per sweep (%d), got %d", len(calls), providerCalls)
+	}
+	providerMu.Unlock()
+
+	deleted, errs := metrics.snapshot()
+	if len(deleted) != len(calls) || errs != 0 {
+		t.Fatalf("expected %d observations and no errors, got %v / %d", len(calls), deleted, errs)
+	}
+	for _, n := range deleted {
+		if n != 3 {
+			t.Fatalf("expected deleted count 3, got %d", n)
+		}
+	}
+}
+
+func TestSweeper_StopsOnContextCancel(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{}
+	s := NewSweeper(st, func() pg.Runner { return &runnerStub{} }, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := runSweeper(t, ctx, s)
+
+	waitForCalls(t, st, 1)
+	cancel()
+	waitRunReturned(t, errCh)
+
+	if got := len(st.deleteExpiredCalls()); got != 1 {
+		t.Fatalf("expected exactly 1 sweep before cancel, got %d", got)
+	}
+}
+
+func TestSweeper_StopAndGracefulStop(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{}
+	s := NewSweeper(st, func() pg.Runner { return &runnerStub{} }, time.Hour)
+	errCh := runSweeper(t, context.Background(), s)
+	waitForCalls(t, st, 1)
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.GracefulStopWithTimeout(stopCtx); err != nil {
+		t.Fatalf("GracefulStopWithTimeout: %v", err)
+	}
+	waitRunReturned(t, errCh)
+
+	s.Stop()
+	s.ForceStop()
+	if err := s.Run(context.Background()); !errors.Is(err, ErrSweeperStarted) {
+		t.Fatalf("expected ErrSweeperStarted on second Run, got %v", err)
+	}
+	if s.Name() != "idempotency-sweeper" {
+		t.Fatalf("unexpected name %q", s.Name())
+	}
+}
+
+func TestSweeper_ErrorsAreCountedAndBackedOff(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{deleteErr: errors.New("db down")}
+	metrics := &sweeperMetricsStub{}
+	s := NewSweeper(st, func() pg.Runner { return &runnerStub{} }, 5*time.Millisecond,
+		WithSweeperMetrics(metrics),
+		WithSweeperMaxBackoff(time.Hour),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := runSweeper(t, ctx, s)
+
+	// Waits after failures: 10ms, 20ms, 40ms, ... so 3 calls need at least 30ms.
+	start := time.Now()
+	waitForCalls(t, st, 3)
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Fatalf("expected backoff between failed sweeps, 3 calls took %v", elapsed)
+	}
+	cancel()
+	waitRunReturned(t, errCh)
+
+	deleted, errs := metrics.snapshot()
+	if len(deleted) != 0 {
+		t.Fatalf("failed sweeps must not observe deleted counts, got %v", deleted)
+	}
+	if errs < 3 {
+		t.Fatalf("expected at least 3 sweep errors, got %d", errs)
+	}
+}
+
+func TestSweeper_NilRunnerIsSweepError(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{}
+	metrics := &sweeperMetricsStub{}
+	done := make(chan struct{})
+	var once sync.Once
+	provider := func() pg.Runner {
+		once.Do(func() { close(done) })
+		return nil
+	}
+	s := NewSweeper(st, provider, time.Hour, WithSweeperMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := runSweeper(t, ctx, s)
+	<-done
+	s.Stop()
+	waitRunReturned(t, errCh)
+	cancel()
+
+	if got := len(st.deleteExpiredCalls()); got != 0 {
+		t.Fatalf("DeleteExpired must not be called with nil runner, got %d calls", got)
+	}
+	if _, errs := metrics.snapshot(); errs != 1 {
+		t.Fatalf("expected 1 sweep error, got %d", errs)
+	}
+}
+
+func TestSweeper_RunnerlessStoreWithoutProvider(t *testing.T) {
+	t.Parallel()
+
+	metrics := &sweeperMetricsStub{}
+	s := NewSweeper(newTestRedisStore(newMemIdemRedis()), nil, time.Hour, WithSweeperMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := runSweeper(t, ctx, s)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if deleted, _ := metrics.snapshot(); len(deleted) > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("sweep did not run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	s.Stop()
+	waitRunReturned(t, errCh)
+
+	if _, errs := metrics.snapshot(); errs != 0 {
+		t.Fatalf("expected no sweep errors, got %d", errs)
+	}
+}
+
+func TestSweeper_RunValidation(t *testing.T) {
+	t.Parallel()
+
+	provider := func() pg.Runner { return &runnerStub{} }
+	tests := []struct {
+		name string
+		s    *Sweeper
+		want error
+	}{
+		{name: "nil store", s: NewSweeper(nil, provider, time.Second), want: ErrNilStore},
+		{name: "typed nil store", s: NewSweeper((*workflowStoreStub)(nil), provider, time.Second), want: ErrNilStore},
+		{name: "nil provider", s: NewSweeper(&workflowStoreStub{}, nil, time.Second), want: ErrNilRunnerProvider},
+		{name: "zero interval", s: NewSweeper(&workflowStoreStub{}, provider, 0), want: ErrSweepIntervalInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.Run(context.Background()); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if err := tt.s.GracefulStopWithTimeout(context.Background()); err != nil {
+				t.Fatalf("stop of never-started sweeper must not fail: %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package idempotency
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -76,6 +77,58 @@ func Begin(ctx context.Context, store Store, run pg.Runner, in BeginInput) (Begi
 	return result, nil
 }
 
+type Outcome struct {
+	Decision   BeginDecision
+	Existing   *Record
+	Lease      *Record
+	Completion Completion
+	Completed  bool
+}
+
+func Execute(ctx context.Context, store Store, run pg.Runner, in BeginInput, fn func(ctx context.Context) (Completion, error)) (Outcome, error) {
+	ctx = ensureContext(ctx)
+
+	if fn == nil {
+		return Outcome{}, ErrNilExecuteFunc
+	}
+
+	begin, err := Begin(ctx, store, run, in)
+	if err != nil {
+		return Outcome{}, err
+	}
+	if begin.Decision != BeginDecisionExecute {
+		return Outcome{Decision: begin.Decision, Existing: begin.Existing}, nil
+	}
+	if begin.Lease == nil {
+		return Outcome{}, ErrInconsistentState
+	}
+
+	done, fnErr := fn(ctx)
+	if fnErr != nil && !done.Status.IsTerminal() {
+		done.Status = StatusFailedRetry
+	}
+	if fnErr != nil && done.ErrorMessage == "" {
+		done.ErrorMessage = fnErr.Error()
+	}
+	if done.Status == "" {
+		done.Status = StatusSucceeded
+	}
+	if done.UpdatedAt.IsZero() {
+		done.UpdatedAt = begin.Lease.UpdatedAt
+	}
+
+	out := Outcome{Decision: BeginDecisionExecute, Lease: begin.Lease, Completion: done}
+	ok, finishErr := Finish(ctx, store, run, *begin.Lease, done)
+	if finishErr != nil {
+		return out, errors.Join(fnErr, finishErr)
+	}
+	out.Completed = ok
+	if !ok {
+		return out, errors.Join(fnErr, ErrLeaseLost)
+	}
+	return out, fnErr
+}
+
 func Finish(ctx context.Context, store Store, run pg.Runner, lease Record, done Completion) (bool, error) {
 	ctx = ensureContext(ctx)
 
@@ -122,18 +175,47 @@ func Reacquire(ctx context.Context, store Store, run pg.Runner, rec Record, newU
 	)
 }
 
+func Touch(ctx context.Context, store LeaseToucher, run pg.Runner, lease Record, newUpdatedAt time.Time) (bool, error) {
+	ctx = ensureContext(ctx)
+
+	if isNilValue(store) {
+		return false, ErrNilStore
+	}
+	if err := validateIdentityFields(lease.Principal, lease.GRPCMethod, lease.IdempotencyKey); err != nil {
+		return false, err
+	}
+	if lease.UpdatedAt.IsZero() || newUpdatedAt.IsZero() {
+		return false, ErrUpdatedAtRequired
+	}
+
+	return store.TouchLease(
+		ctx,
+		run,
+		lease.Principal,
+		lease.GRPCMethod,
+		lease.IdempotencyKey,
+		lease.UpdatedAt,
+		newUpdatedAt,
+	)
+}
+
 func validateStore(store Store) error {
-	if store == nil {
+	if isNilValue(store) {
 		return ErrNilStore
 	}
-	rv := reflect.ValueOf(store)
+	return nil
+}
+
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
 	switch rv.Kind() {
 	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
-		if rv.IsNil() {
-			return ErrNilStore
-		}
+		return rv.IsNil()
 	}
-	return nil
+	return false
 }
 
 func validateIdentityFields(principal, grpcMethod, idemKey string) error {
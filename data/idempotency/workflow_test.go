@@ -3,6 +3,7 @@ package idempotency
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -200,6 +201,237 @@ func TestReacquire_RequiresUpdatedAt(t *testing.T) {
 	}
 }
 
+func TestTouch_UsesLeaseUpdatedAtAsPrevious(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{touchOK: true}
+	lease := Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		UpdatedAt:      time.Now().UTC(),
+	}
+	next := lease.UpdatedAt.Add(10 * time.Second)
+
+	ok, err := Touch(context.Background(), st, nil, lease, next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected touch true")
+	}
+	if !st.touchCall.prevUpdatedAt.Equal(lease.UpdatedAt) || !st.touchCall.newUpdatedAt.Equal(next) {
+		t.Fatalf("unexpected touch timestamps: %+v", st.touchCall)
+	}
+	if st.touchCall.principal != lease.Principal || st.touchCall.idemKey != lease.IdempotencyKey {
+		t.Fatalf("unexpected touch identity: %+v", st.touchCall)
+	}
+}
+
+func TestTouch_StaleLeaseReturnsFalse(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{touchOK: false}
+	ok, err := Touch(context.Background(), st, nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		UpdatedAt:      time.Now().UTC(),
+	}, time.Now().UTC().Add(time.Second))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected touch false on lost race")
+	}
+}
+
+func TestTouch_RequiresStoreAndUpdatedAt(t *testing.T) {
+	t.Parallel()
+
+	var nilStore *workflowStoreStub
+	if _, err := Touch(context.Background(), nilStore, nil, Record{}, time.Now()); !errors.Is(err, ErrNilStore) {
+		t.Fatalf("expected ErrNilStore, got %v", err)
+	}
+
+	_, err := Touch(context.Background(), &workflowStoreStub{}, nil, Record{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+	}, time.Now())
+	if !errors.Is(err, ErrUpdatedAtRequired) {
+		t.Fatalf("expected ErrUpdatedAtRequired, got %v", err)
+	}
+}
+
+func TestExecute_RunsAndFinishesWithLease(t *testing.T) {
+	t.Parallel()
+
+	leaseAt := time.Now().UTC()
+	st := &workflowStoreStub{
+		reserveResult: ReserveResult{Reserved: true, Record: &Record{
+			Principal:      "u1",
+			GRPCMethod:     "/svc.Method",
+			IdempotencyKey: "k1",
+			RequestHash:    "h1",
+			Status:         StatusInProgress,
+			UpdatedAt:      leaseAt,
+		}},
+		completeOK: true,
+	}
+
+	calls := 0
+	out, err := Execute(context.Background(), st, nil, BeginInput{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      leaseAt.Add(time.Minute),
+	}, func(context.Context) (Completion, error) {
+		calls++
+		return Completion{ResponsePayload: []byte("ok")}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected fn to run once, got %d", calls)
+	}
+	if out.Decision != BeginDecisionExecute || !out.Completed || out.Lease == nil {
+		t.Fatalf("unexpected outcome: %+v", out)
+	}
+	done := st.completeCall.done
+	if done.Status != StatusSucceeded || string(done.ResponsePayload) != "ok" {
+		t.Fatalf("unexpected completion: %+v", done)
+	}
+	if !done.UpdatedAt.Equal(leaseAt) {
+		t.Fatalf("expected lease UpdatedAt %v to be propagated, got %v", leaseAt, done.UpdatedAt)
+	}
+}
+
+func TestExecute_ReplaySkipsFn(t *testing.T) {
+	t.Parallel()
+
+	existing := &Record{
+		Principal:       "u1",
+		GRPCMethod:      "/svc.Method",
+		IdempotencyKey:  "k1",
+		RequestHash:     "h1",
+		Status:          StatusSucceeded,
+		ResponsePayload: []byte("stored"),
+	}
+	st := &workflowStoreStub{reserveResult: ReserveResult{Reserved: false, Record: existing}}
+
+	out, err := Execute(context.Background(), st, nil, BeginInput{
+		Principal:      "u1",
+		GRPCMethod:     "/svc.Method",
+		IdempotencyKey: "k1",
+		RequestHash:    "h1",
+		ExpiresAt:      time.Now().UTC().Add(time.Minute),
+	}, func(context.Context) (Completion, error) {
+		t.Fatal("fn must not run on replay")
+		return Completion{}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Decision != BeginDecisionReplay || out.Existing != existing {
+		t.Fatalf("unexpected outcome: %+v", out)
+	}
+	if st.completeCtx != nil {
+		t.Fatalf("Complete must not be called on replay")
+	}
+}
+
+func TestExecute_FnErrorRecordsRetryable(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{
+		reserveResult: ReserveResult{Reserved: true, Record: &Record{
+			Principal:      "u1",
+			GRPCMethod:     "/svc.Method",
+			IdempotencyKey: "k1",
+			Status:         StatusInProgress,
+			UpdatedAt:      time.Now().UTC(),
+		}},
+		completeOK: true,
+	}
+	fnErr := errors.New("upstream unavailable")
+
+	out, err := Execute(context.Background(), st, nil, BeginInput{}, func(context.Context) (Completion, error) {
+		return Completion{}, fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+	if !out.Completed || st.completeCall.done.Status != StatusFailedRetry {
+		t.Fatalf("expected FAILED_RETRYABLE completion, got %+v", st.completeCall.done)
+	}
+	if st.completeCall.done.ErrorMessage != fnErr.Error() {
+		t.Fatalf("expected error message to be recorded, got %q", st.completeCall.done.ErrorMessage)
+	}
+}
+
+func TestExecute_FnErrorKeepsTerminalStatus(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{
+		reserveResult: ReserveResult{Reserved: true, Record: &Record{
+			Principal:      "u1",
+			GRPCMethod:     "/svc.Method",
+			IdempotencyKey: "k1",
+			Status:         StatusInProgress,
+			UpdatedAt:      time.Now().UTC(),
+		}},
+		completeOK: true,
+	}
+	fnErr := errors.New("card declined")
+
+	_, err := Execute(context.Background(), st, nil, BeginInput{}, func(context.Context) (Completion, error) {
+		return Completion{Status: StatusFailedFinal, ResponseCode: 9}, fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Fatalf("expected fn error, got %v", err)
+	}
+	if st.completeCall.done.Status != StatusFailedFinal || st.completeCall.done.ResponseCode != 9 {
+		t.Fatalf("expected FAILED_FINAL completion to be kept, got %+v", st.completeCall.done)
+	}
+}
+
+func TestExecute_LeaseLost(t *testing.T) {
+	t.Parallel()
+
+	st := &workflowStoreStub{
+		reserveResult: ReserveResult{Reserved: true, Record: &Record{
+			Principal:      "u1",
+			GRPCMethod:     "/svc.Method",
+			IdempotencyKey: "k1",
+			Status:         StatusInProgress,
+			UpdatedAt:      time.Now().UTC(),
+		}},
+		completeOK: false,
+	}
+
+	out, err := Execute(context.Background(), st, nil, BeginInput{}, func(context.Context) (Completion, error) {
+		return Completion{}, nil
+	})
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("expected ErrLeaseLost, got %v", err)
+	}
+	if out.Completed {
+		t.Fatalf("expected Completed=false")
+	}
+}
+
+func TestExecute_RequiresFn(t *testing.T) {
+	t.Parallel()
+
+	_, err := Execute(context.Background(), &workflowStoreStub{}, nil, BeginInput{}, nil)
+	if !errors.Is(err, ErrNilExecuteFunc) {
+		t.Fatalf("expected ErrNilExecuteFunc, got %v", err)
+	}
+}
+
 func TestWorkflow_TODOContext_IsPropagated(t *testing.T) {
 	t.Parallel()
 
@@ -271,6 +503,15 @@ type workflowStoreStub struct {
 	reacquireCall reacquireCall
 	reacquireOK   bool
 	reacquireErr  error
+
+	touchCall touchCall
+	touchOK   bool
+	touchErr  error
+
+	deleteMu    sync.Mutex
+	deleteCalls []time.Time
+	deleteN     int64
+	deleteErr   error
 }
 
 func (s *workflowStoreStub) Reserve(ctx context.Context, _ pg.Runner, rec Record) (ReserveResult, error) {
@@ -301,8 +542,28 @@ func (s *workflowStoreStub) Complete(ctx context.Context, _ pg.Runner, principal
 	return s.completeOK, s.completeErr
 }
 
-func (s *workflowStoreStub) DeleteExpired(context.Context, pg.Runner, time.Time) (int64, error) {
-	return 0, nil
+func (s *workflowStoreStub) DeleteExpired(_ context.Context, _ pg.Runner, before time.Time) (int64, error) {
+	s.deleteMu.Lock()
+	defer s.deleteMu.Unlock()
+	s.deleteCalls = append(s.deleteCalls, before)
+	return s.deleteN, s.deleteErr
+}
+
+func (s *workflowStoreStub) deleteExpiredCalls() []time.Time {
+	s.deleteMu.Lock()
+	defer s.deleteMu.Unlock()
+	return append([]time.Time(nil), s.deleteCalls...)
+}
+
+func (s *workflowStoreStub) TouchLease(_ context.Context, _ pg.Runner, principal, grpcMethod, idemKey string, prevUpdatedAt, newUpdatedAt time.Time) (bool, error) {
+	s.touchCall = touchCall{
+		principal:     principal,
+		grpcMethod:    grpcMethod,
+		idemKey:       idemKey,
+		prevUpdatedAt: prevUpdatedAt,
+		newUpdatedAt:  newUpdatedAt,
+	}
+	return s.touchOK, s.touchErr
 }
 
 type completeCall struct {
@@ -319,3 +580,11 @@ type reacquireCall struct {
 	requestHash string
 	updatedAt   time.Time
 }
+
+type touchCall struct {
+	principal     string
+	grpcMethod    string
+	idemKey       string
+	prevUpdatedAt time.Time
+	newUpdatedAt  time.Time
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	errNilBatchCallback = errors.New("postgres: batch callback is nil")
+	errNilBatchResults  = errors.New("postgres: batch results are nil")
+	errRunnerNotTx      = errors.New("postgres: runner in context is not a transaction")
+)
+
+type batchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// WithBatch builds a batch via fn and sends it in one round trip.
+// Inside WithTx the batch joins the transaction from ctx, otherwise it uses the pool.
+func (c *Client) WithBatch(ctx context.Context, fn func(b *pgx.Batch) error) error {
+	if fn == nil {
+		return errNilBatchCallback
+	}
+	if ctx != nil {
+		if r, ok := ctx.Value(ctxKeyRunner{}).(Runner); ok {
+			if tx, ok := asTx(r); ok {
+				return runBatch(ctx, tx, fn)
+			}
+		}
+	}
+	if c == nil || c.Pool == nil {
+		return errNilClientPool
+	}
+	return runBatch(ctx, c.Pool, fn)
+}
+
+// WithBatchTx sends the batch through the transaction stored in ctx.
+// Like MustRunnerFromContext, it panics when ctx carries no Runner.
+func WithBatchTx(ctx context.Context, fn func(b *pgx.Batch) error) error {
+	if fn == nil {
+		return errNilBatchCallback
+	}
+	tx, ok := asTx(MustRunnerFromContext(ctx))
+	if !ok {
+		return errRunnerNotTx
+	}
+	return runBatch(ctx, tx, fn)
+}
+
+func runBatch(ctx context.Context, sender batchSender, fn func(b *pgx.Batch) error) (err error) {
+	b := &pgx.Batch{}
+	if err := fn(b); err != nil {
+		return err
+	}
+	if b.Len() == 0 {
+		return nil
+	}
+
+	br := sender.SendBatch(ctx, b)
+	if br == nil {
+		return errNilBatchResults
+	}
+	defer func() {
+		if cerr := br.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("postgres: close batch: %w", cerr)
+		}
+	}()
+
+	for i := 0; i < b.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return fmt.Errorf("postgres: batch query %d: %w", i, err)
+		}
+	}
+	return nil
+}
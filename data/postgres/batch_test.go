@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type batchResultsStub struct {
+	execs    int
+	errAt    map[int]error
+	closeErr error
+	closed   bool
+}
+
+func (b *batchResultsStub) Exec() (pgconn.CommandTag, error) {
+	i := b.execs
+	b.execs++
+	if err, ok := b.errAt[i]; ok {
+		return pgconn.CommandTag{}, err
+	}
+	return pgconn.CommandTag{}, nil
+}
+func (b *batchResultsStub) Query() (pgx.Rows, error) { return nil, errors.New("not implemented") }
+func (b *batchResultsStub) QueryRow() pgx.Row        { return nil }
+func (b *batchResultsStub) Close() error {
+	b.closed = true
+	return b.closeErr
+}
+
+func TestWithBatch_UsesTxFromContext(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	err := (&Client{}).WithBatch(ctx, func(b *pgx.Batch) error {
+		b.Queue("INSERT INTO t (id) VALUES ($1)", 1)
+		b.Queue("INSERT INTO t (id) VALUES ($1)", 2)
+		b.Queue("UPDATE t SET v = 1")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tx.batches) != 1 || tx.batches[0].Len() != 3 {
+		t.Fatalf("expected one batch with 3 queries, got %v", tx.batches)
+	}
+	if tx.batchRes.execs != 3 {
+		t.Fatalf("expected 3 results read, got %d", tx.batchRes.execs)
+	}
+	if !tx.batchRes.closed {
+		t.Fatalf("batch results must be closed")
+	}
+}
+
+func TestWithBatch_StopsOnFirstError(t *testing.T) {
+	t.Parallel()
+
+	boom := &pgconn.PgError{Code: SQLStateUniqueViolation}
+	tx := &txStub{batchRes: &batchResultsStub{errAt: map[int]error{1: boom}}}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	err := (&Client{}).WithBatch(ctx, func(b *pgx.Batch) error {
+		b.Queue("INSERT 1")
+		b.Queue("INSERT 2")
+		b.Queue("INSERT 3")
+		return nil
+	})
+	if !errors.Is(err, boom) || !IsUniqueViolation(err) {
+		t.Fatalf("expected unique violation, got %v", err)
+	}
+	if tx.batchRes.execs != 2 {
+		t.Fatalf("expected iteration to stop after failing query, got %d", tx.batchRes.execs)
+	}
+	if !tx.batchRes.closed {
+		t.Fatalf("batch results must be closed on error")
+	}
+}
+
+func TestWithBatch_CloseError(t *testing.T) {
+	t.Parallel()
+
+	closeErr := errors.New("close failed")
+	tx := &txStub{batchRes: &batchResultsStub{closeErr: closeErr}}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	err := (&Client{}).WithBatch(ctx, func(b *pgx.Batch) error {
+		b.Queue("SELECT 1")
+		return nil
+	})
+	if !errors.Is(err, closeErr) {
+		t.Fatalf("expected close error, got %v", err)
+	}
+}
+
+func TestWithBatch_CallbackErrorSkipsSend(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+	expected := errors.New("build failed")
+
+	err := (&Client{}).WithBatch(ctx, func(b *pgx.Batch) error {
+		b.Queue("SELECT 1")
+		return expected
+	})
+	if !errors.Is(err, expected) {
+		t.Fatalf("expected %v, got %v", expected, err)
+	}
+	if len(tx.batches) != 0 {
+		t.Fatalf("batch must not be sent")
+	}
+}
+
+func TestWithBatch_EmptyBatchSkipsSend(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	if err := (&Client{}).WithBatch(ctx, func(*pgx.Batch) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tx.batches) != 0 {
+		t.Fatalf("empty batch must not be sent")
+	}
+}
+
+func TestWithBatch_NilCallbackAndPool(t *testing.T) {
+	t.Parallel()
+
+	if err := (&Client{}).WithBatch(context.Background(), nil); !errors.Is(err, errNilBatchCallback) {
+		t.Fatalf("expected errNilBatchCallback, got %v", err)
+	}
+
+	var c *Client
+	err := c.WithBatch(context.Background(), func(b *pgx.Batch) error {
+		b.Queue("SELECT 1")
+		return nil
+	})
+	if !errors.Is(err, errNilClientPool) {
+		t.Fatalf("expected errNilClientPool, got %v", err)
+	}
+}
+
+func TestWithBatchTx_UsesRawTxProvider(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), rawRunnerStub{tx: tx})
+
+	err := WithBatchTx(ctx, func(b *pgx.Batch) error {
+		b.Queue("SELECT 1")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tx.batches) != 1 {
+		t.Fatalf("expected batch sent through tx")
+	}
+}
+
+func TestWithBatchTx_RunnerNotTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := ContextWithRunner(context.Background(), poolRunner{})
+	err := WithBatchTx(ctx, func(b *pgx.Batch) error {
+		b.Queue("SELECT 1")
+		return nil
+	})
+	if !errors.Is(err, errRunnerNotTx) {
+		t.Fatalf("expected errRunnerNotTx, got %v", err)
+	}
+}
+
+func TestWithBatchTx_PanicsWithoutRunner(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic without runner in context")
+		}
+	}()
+	_ = WithBatchTx(context.Background(), func(*pgx.Batch) error { return nil })
+}
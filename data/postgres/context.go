@@ -3,18 +3,64 @@ package postgres
 import (
 	"context"
 	"errors"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
 )
 
 var ErrRunnerMissingInContext = errors.New("postgres: no Runner in context (outside transaction?)")
 
 type ctxKeyRunner struct{}
 
+type ctxKeySavepoint struct{}
+
+// savepointScope tracks savepoint nesting for one transaction.
+type savepointScope struct {
+	tx       pgx.Tx
+	name     string
+	depth    int
+	children atomic.Int64
+}
+
+func (s *savepointScope) child() *savepointScope {
+	n := s.children.Add(1)
+	return &savepointScope{
+		tx:    s.tx,
+		name:  s.name + "_" + strconv.FormatInt(n, 10),
+		depth: s.depth + 1,
+	}
+}
+
 // ContextWithRunner stores a Runner in the context.
 func ContextWithRunner(ctx context.Context, r Runner) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return context.WithValue(ctx, ctxKeyRunner{}, r)
+	ctx = context.WithValue(ctx, ctxKeyRunner{}, r)
+	if tx, ok := asTx(r); ok && savepointScopeFrom(ctx, tx) == nil {
+		ctx = context.WithValue(ctx, ctxKeySavepoint{}, &savepointScope{tx: tx, name: "sp"})
+	}
+	return ctx
+}
+
+// SavepointDepth returns the number of active WithSavepoint levels in ctx (0 outside savepoints).
+func SavepointDepth(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	if s, ok := ctx.Value(ctxKeySavepoint{}).(*savepointScope); ok {
+		return s.depth
+	}
+	return 0
+}
+
+func savepointScopeFrom(ctx context.Context, tx pgx.Tx) *savepointScope {
+	s, ok := ctx.Value(ctxKeySavepoint{}).(*savepointScope)
+	if !ok || s.tx != tx {
+		return nil
+	}
+	return s
 }
 
 // RunnerFromContext extracts the Runner from the context.
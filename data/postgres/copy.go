@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	errEmptyCopyTable   = errors.New("postgres: copy table name is empty")
+	errEmptyCopyColumns = errors.New("postgres: copy columns are empty")
+)
+
+type copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// CopyFrom bulk-inserts rows into table using the COPY protocol and returns the number of rows copied.
+// Each row must have one value per column. Inside WithTx the copy joins the transaction from ctx,
+// otherwise it uses the pool. An empty rows slice is a no-op.
+func (c *Client) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, rows [][]any) (int64, error) {
+	if len(table) == 0 {
+		return 0, errEmptyCopyTable
+	}
+	if len(columns) == 0 {
+		return 0, errEmptyCopyColumns
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("postgres: copy row %d has %d values, want %d", i, len(row), len(columns))
+		}
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	if ctx != nil {
+		if r, ok := ctx.Value(ctxKeyRunner{}).(Runner); ok {
+			if tx, ok := asTx(r); ok {
+				return runCopy(ctx, tx, table, columns, rows)
+			}
+		}
+	}
+	if c == nil || c.Pool == nil {
+		return 0, errNilClientPool
+	}
+	return runCopy(ctx, c.Pool, table, columns, rows)
+}
+
+func runCopy(ctx context.Context, dst copier, table pgx.Identifier, columns []string, rows [][]any) (int64, error) {
+	n, err := dst.CopyFrom(ctx, table, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return n, fmt.Errorf("postgres: copy: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,48 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFrom_Integration(t *testing.T) {
+	c := openIntegrationClient(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := c.RunnerFromPool().Exec(ctx, "CREATE TABLE IF NOT EXISTS copy_test (id BIGINT PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	base := time.Now().UnixNano()
+	rows := make([][]any, 0, 100)
+	for i := range 100 {
+		rows = append(rows, []any{base + int64(i), "row"})
+	}
+
+	n, err := c.CopyFrom(ctx, pgx.Identifier{"copy_test"}, []string{"id", "name"}, rows)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), n)
+
+	// Inside WithTx the copy is rolled back with the transaction.
+	txBase := base + 1000
+	err = c.WithTx(ctx, func(txCtx context.Context) error {
+		_, e := c.CopyFrom(txCtx, pgx.Identifier{"copy_test"}, []string{"id", "name"}, [][]any{{txBase, "tx"}})
+		require.NoError(t, e)
+		return context.Canceled
+	})
+	require.Error(t, err)
+
+	var cnt int
+	require.NoError(t, c.RunnerFromPool().QueryRow(ctx, "SELECT count(*) FROM copy_test WHERE id >= $1 AND id < $2", base, base+100).Scan(&cnt))
+	require.Equal(t, 100, cnt)
+	require.NoError(t, c.RunnerFromPool().QueryRow(ctx, "SELECT count(*) FROM copy_test WHERE id = $1", txBase).Scan(&cnt))
+	require.Equal(t, 0, cnt)
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestCopyFrom_UsesTxFromContext(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	table := pgx.Identifier{"public", "events"}
+	columns := []string{"id", "name"}
+	rows := [][]any{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	n, err := (&Client{}).CopyFrom(ctx, table, columns, rows)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 rows copied, got %d", n)
+	}
+	if !reflect.DeepEqual(tx.copyTable, table) || !reflect.DeepEqual(tx.copyColumns, columns) {
+		t.Fatalf("unexpected target: %v %v", tx.copyTable, tx.copyColumns)
+	}
+	if !reflect.DeepEqual(tx.copyRows, rows) {
+		t.Fatalf("rows not forwarded: %v", tx.copyRows)
+	}
+}
+
+func TestCopyFrom_WrapsError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("boom")
+	tx := &txStub{copyErr: cause}
+	ctx := ContextWithRunner(context.Background(), rawRunnerStub{tx: tx})
+
+	_, err := (&Client{}).CopyFrom(ctx, pgx.Identifier{"t"}, []string{"id"}, [][]any{{1}})
+	if !errors.Is(err, cause) {
+		t.Fatalf("expected wrapped cause, got %v", err)
+	}
+}
+
+func TestCopyFrom_Validation(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	ctx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+	c := &Client{}
+
+	if _, err := c.CopyFrom(ctx, nil, []string{"id"}, [][]any{{1}}); !errors.Is(err, errEmptyCopyTable) {
+		t.Fatalf("expected errEmptyCopyTable, got %v", err)
+	}
+	if _, err := c.CopyFrom(ctx, pgx.Identifier{"t"}, nil, [][]any{{1}}); !errors.Is(err, errEmptyCopyColumns) {
+		t.Fatalf("expected errEmptyCopyColumns, got %v", err)
+	}
+	if _, err := c.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"id", "name"}, [][]any{{1, "a"}, {2}}); err == nil {
+		t.Fatalf("expected error for row with wrong arity")
+	}
+	if n, err := c.CopyFrom(ctx, pgx.Identifier{"t"}, []string{"id"}, nil); n != 0 || err != nil {
+		t.Fatalf("expected no-op for empty rows, got (%d, %v)", n, err)
+	}
+	if tx.copyTable != nil {
+		t.Fatalf("invalid or empty input must not reach CopyFrom")
+	}
+}
+
+func TestCopyFrom_NilPool(t *testing.T) {
+	t.Parallel()
+
+	var c *Client
+	if _, err := c.CopyFrom(context.Background(), pgx.Identifier{"t"}, []string{"id"}, [][]any{{1}}); !errors.Is(err, errNilClientPool) {
+		t.Fatalf("expected errNilClientPool, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	listenMinBackoff = 100 * time.Millisecond
+	listenMaxBackoff = 5 * time.Second
+)
+
+var (
+	errEmptyListenChannel = errors.New("postgres: listen channel is empty")
+	errNilListenHandler   = errors.New("postgres: listen handler is nil")
+)
+
+// Listen subscribes to channel via LISTEN on a dedicated pool connection and calls handler
+// for every notification. Lost connections are re-acquired with backoff.
+// Returns nil when ctx is cancelled; SQL errors from LISTEN are returned as is.
+func (c *Client) Listen(ctx context.Context, channel string, handler func(payload string)) error {
+	if strings.TrimSpace(channel) == "" {
+		return errEmptyListenChannel
+	}
+	if handler == nil {
+		return errNilListenHandler
+	}
+	if c == nil || c.Pool == nil {
+		return errNilClientPool
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	backoff := listenMinBackoff
+	for {
+		listened := false
+		err := c.listenOnce(ctx, channel, handler, func() { listened = true })
+		if IsNormalListenErr(ctx, err) {
+			return nil
+		}
+		var pgErr *pgconn.PgError
+		if !listened && errors.As(err, &pgErr) {
+			return err
+		}
+		if listened {
+			backoff = listenMinBackoff
+		}
+
+		if err := sleepCtx(ctx, backoff); err != nil {
+			return nil
+		}
+		backoff *= 2
+		if backoff > listenMaxBackoff {
+			backoff = listenMaxBackoff
+		}
+	}
+}
+
+// IsNormalListenErr reports whether err from a LISTEN loop is a clean shutdown
+// (nil error or cancellation of ctx) rather than a failure.
+func IsNormalListenErr(ctx context.Context, err error) bool {
+	if err == nil {
+		return true
+	}
+	if ctx != nil && ctx.Err() != nil {
+		return true
+	}
+	return errors.Is(err, context.Canceled)
+}
+
+func (c *Client) listenOnce(ctx context.Context, channel string, handler func(string), onListen func()) (err error) {
+	conn, err := c.Pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { releaseListenConn(conn, channel, IsNormalListenErr(ctx, err)) }()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+quoteIdent(channel)); err != nil {
+		return err
+	}
+	onListen()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handler(n.Payload)
+	}
+}
+
+func releaseListenConn(conn *pgxpool.Conn, channel string, healthy bool) {
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), txCleanupTimeout)
+	defer cancel()
+
+	if healthy && !conn.Conn().IsClosed() {
+		if _, err := conn.Exec(cleanupCtx, "UNLISTEN "+quoteIdent(channel)); err == nil {
+			conn.Release()
+			return
+		}
+	}
+	_ = conn.Hijack().Close(cleanupCtx)
+}
@@ -0,0 +1,104 @@
+//go:build integration
+
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen_ReceivesNotifications_Integration(t *testing.T) {
+	c := openIntegrationClient(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	listenCtx, stop := context.WithCancel(ctx)
+	payloads := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Listen(listenCtx, "cache_invalidation", func(p string) { payloads <- p })
+	}()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		_, err := c.RunnerFromPool().Exec(ctx, "SELECT pg_notify('cache_invalidation', 'user:42')")
+		require.NoError(t, err)
+
+		select {
+		case p := <-payloads:
+			require.Equal(t, "user:42", p)
+			stop()
+			select {
+			case err := <-done:
+				require.NoError(t, err)
+			case <-deadline:
+				t.Fatalf("Listen did not return after cancel")
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+		case <-deadline:
+			t.Fatalf("notification not received")
+		}
+	}
+}
+
+func TestListen_ReconnectsAfterTermination_Integration(t *testing.T) {
+	c := openIntegrationClient(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	listenCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	payloads := make(chan string, 16)
+	go func() {
+		_ = c.Listen(listenCtx, "listen_reconnect", func(p string) { payloads <- p })
+	}()
+
+	waitPayload := func(want string) {
+		t.Helper()
+		deadline := time.After(8 * time.Second)
+		for {
+			_, err := c.RunnerFromPool().Exec(ctx, "SELECT pg_notify('listen_reconnect', $1)", want)
+			require.NoError(t, err)
+			select {
+			case p := <-payloads:
+				if p == want {
+					return
+				}
+			case <-time.After(100 * time.Millisecond):
+			case <-deadline:
+				t.Fatalf("payload %q not received", want)
+			}
+		}
+	}
+
+	waitPayload("before")
+
+	_, err := c.RunnerFromPool().Exec(ctx, `
+		SELECT pg_terminate_backend(pid)
+		FROM pg_stat_activity
+		WHERE pid <> pg_backend_pid()
+		  AND query LIKE 'LISTEN%'
+	`)
+	require.NoError(t, err)
+
+	waitPayload("after")
+}
+
+func TestListen_CancelledContextReturnsNil_Integration(t *testing.T) {
+	c := openIntegrationClient(t)
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.Listen(ctx, "cancelled", func(string) {})
+	require.NoError(t, err)
+}
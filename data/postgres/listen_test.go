@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestListen_Validation(t *testing.T) {
+	t.Parallel()
+
+	noop := func(string) {}
+	tests := []struct {
+		name    string
+		c       *Client
+		channel string
+		handler func(string)
+		want    error
+	}{
+		{"empty channel", &Client{}, " ", noop, errEmptyListenChannel},
+		{"nil handler", &Client{}, "events", nil, errNilListenHandler},
+		{"nil client", nil, "events", noop, errNilClientPool},
+		{"nil pool", &Client{}, "events", noop, errNilClientPool},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tc.c.Listen(context.Background(), tc.channel, tc.handler); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestIsNormalListenErr(t *testing.T) {
+	t.Parallel()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if !IsNormalListenErr(context.Background(), nil) {
+		t.Fatalf("nil error must be normal")
+	}
+	if !IsNormalListenErr(cancelled, errors.New("conn closed")) {
+		t.Fatalf("errors after ctx cancellation must be normal")
+	}
+	if !IsNormalListenErr(context.Background(), context.Canceled) {
+		t.Fatalf("context.Canceled must be normal")
+	}
+	if IsNormalListenErr(context.Background(), errors.New("unexpected EOF")) {
+		t.Fatalf("connection loss must not be normal")
+	}
+}
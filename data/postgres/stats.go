@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPoolSaturated is returned by HealthCheck with WithSaturationCheck when every pool
+// connection is acquired and none is idle.
+var ErrPoolSaturated = errors.New("postgres: pool saturated")
+
+// PoolStats is a point-in-time snapshot of the connection pool.
+// It decouples callers (metrics, health endpoints) from the pgxpool.Stat API.
+type PoolStats struct {
+	AcquiredConns     int32
+	IdleConns         int32
+	TotalConns        int32
+	MaxConns          int32
+	ConstructingConns int32
+
+	// AcquireCount is the cumulative number of successful acquires.
+	AcquireCount int64
+	// EmptyAcquireCount is the cumulative number of acquires that had to wait for a connection.
+	EmptyAcquireCount int64
+	// CanceledAcquireCount is the cumulative number of acquires canceled by ctx.
+	CanceledAcquireCount int64
+	// AcquireWait is the cumulative time spent acquiring connections.
+	AcquireWait time.Duration
+}
+
+// Saturated reports whether all connections are acquired and none is idle.
+func (s PoolStats) Saturated() bool {
+	return s.MaxConns > 0 && s.IdleConns == 0 && s.AcquiredConns >= s.MaxConns
+}
+
+// poolStat is the subset of *pgxpool.Stat used by Stats.
+type poolStat interface {
+	AcquiredConns() int32
+	IdleConns() int32
+	TotalConns() int32
+	MaxConns() int32
+	ConstructingConns() int32
+	AcquireCount() int64
+	EmptyAcquireCount() int64
+	CanceledAcquireCount() int64
+	AcquireDuration() time.Duration
+}
+
+func poolStatsFrom(s poolStat) PoolStats {
+	return PoolStats{
+		AcquiredConns:        s.AcquiredConns(),
+		IdleConns:            s.IdleConns(),
+		TotalConns:           s.TotalConns(),
+		MaxConns:             s.MaxConns(),
+		ConstructingConns:    s.ConstructingConns(),
+		AcquireCount:         s.AcquireCount(),
+		EmptyAcquireCount:    s.EmptyAcquireCount(),
+		CanceledAcquireCount: s.CanceledAcquireCount(),
+		AcquireWait:          s.AcquireDuration(),
+	}
+}
+
+// Stats returns a snapshot of the pool. A nil client or pool yields zero stats.
+func (c *Client) Stats() PoolStats {
+	if c == nil || c.Pool == nil {
+		return PoolStats{}
+	}
+	return poolStatsFrom(c.Pool.Stat())
+}
+
+type healthCheckConfig struct {
+	saturation bool
+}
+
+// HealthCheckOption configures HealthCheck.
+type HealthCheckOption func(*healthCheckConfig)
+
+// WithSaturationCheck makes HealthCheck fail with ErrPoolSaturated when the pool has
+// no idle connections and every connection is acquired.
+func WithSaturationCheck() HealthCheckOption {
+	return func(c *healthCheckConfig) { c.saturation = true }
+}
+
+// HealthCheck pings the database through the pool. It fits metrics.Options.Ready/ReadyChecks:
+//
+//	Ready: func(ctx context.Context, _ *http.Request) error { return client.HealthCheck(ctx) }
+func (c *Client) HealthCheck(ctx context.Context, opts ...HealthCheckOption) error {
+	if c == nil || c.Pool == nil {
+		return errNilClientPool
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var cfg healthCheckConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	// A saturated pool would block the ping on Acquire, so check it first.
+	if cfg.saturation {
+		if st := c.Stats(); st.Saturated() {
+			return fmt.Errorf("%w: %d/%d conns acquired", ErrPoolSaturated, st.AcquiredConns, st.MaxConns)
+		}
+	}
+	if err := pingPool(ctx, c.Pool); err != nil {
+		return fmt.Errorf("postgres: health: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type statStub struct {
+	acquired, idle, total, max, constructing int32
+	acquires, emptyAcquires, canceled        int64
+	wait                                     time.Duration
+}
+
+func (s statStub) AcquiredConns() int32           { return s.acquired }
+func (s statStub) IdleConns() int32               { return s.idle }
+func (s statStub) TotalConns() int32              { return s.total }
+func (s statStub) MaxConns() int32                { return s.max }
+func (s statStub) ConstructingConns() int32       { return s.constructing }
+func (s statStub) AcquireCount() int64            { return s.acquires }
+func (s statStub) EmptyAcquireCount() int64       { return s.emptyAcquires }
+func (s statStub) CanceledAcquireCount() int64    { return s.canceled }
+func (s statStub) AcquireDuration() time.Duration { return s.wait }
+
+func TestPoolStatsFrom_MapsAllFields(t *testing.T) {
+	t.Parallel()
+
+	got := poolStatsFrom(statStub{
+		acquired: 3, idle: 2, total: 5, max: 10, constructing: 1,
+		acquires: 42, emptyAcquires: 7, canceled: 2, wait: 150 * time.Millisecond,
+	})
+	want := PoolStats{
+		AcquiredConns: 3, IdleConns: 2, TotalConns: 5, MaxConns: 10, ConstructingConns: 1,
+		AcquireCount: 42, EmptyAcquireCount: 7, CanceledAcquireCount: 2, AcquireWait: 150 * time.Millisecond,
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestPoolStats_Saturated(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		s    PoolStats
+		want bool
+	}{
+		{"zero", PoolStats{}, false},
+		{"idle available", PoolStats{AcquiredConns: 4, IdleConns: 1, MaxConns: 5}, false},
+		{"below max", PoolStats{AcquiredConns: 4, MaxConns: 5}, false},
+		{"all acquired", PoolStats{AcquiredConns: 5, MaxConns: 5}, true},
+	}
+	for _, tc := range cases {
+		if got := tc.s.Saturated(); got != tc.want {
+			t.Fatalf("%s: Saturated() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestStats_NilClient(t *testing.T) {
+	t.Parallel()
+
+	var c *Client
+	if got := c.Stats(); got != (PoolStats{}) {
+		t.Fatalf("expected zero stats, got %+v", got)
+	}
+	if got := (&Client{}).Stats(); got != (PoolStats{}) {
+		t.Fatalf("expected zero stats, got %+v", got)
+	}
+}
+
+func TestHealthCheck_NilPool(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{}
+	if err := c.HealthCheck(context.Background()); !errors.Is(err, errNilClientPool) {
+		t.Fatalf("expected errNilClientPool, got %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -18,8 +20,9 @@ const (
 )
 
 var (
-	errNilTxCallback = errors.New("postgres: tx callback is nil")
-	errNilClientPool = errors.New("postgres: client pool is nil")
+	errNilTxCallback  = errors.New("postgres: tx callback is nil")
+	errNilClientPool  = errors.New("postgres: client pool is nil")
+	errInvalidSetting = errors.New("postgres: invalid SET LOCAL value")
 )
 
 // TxConfig contains optional transaction settings.
@@ -31,6 +34,10 @@ type TxConfig struct {
 	// Local timeouts for current TX (SET LOCAL ...).
 	StatementTimeout         time.Duration // statement timeout
 	IdleInTransactionTimeout time.Duration // idle_in_transaction_session_timeout
+
+	// Local session settings for current TX (SET LOCAL ...), skipped when empty.
+	ApplicationName string // application_name, quoted as a string literal
+	Role            string // SET LOCAL ROLE, quoted as an identifier
 }
 
 // WithTx runs panic-safe read-write transaction with default options.
@@ -66,28 +73,104 @@ func (c *Client) WithTxROExplicit(ctx context.Context, fn func(run Runner) error
 	})
 }
 
+// RetryConfig controls retries of SERIALIZABLE transactions on 40001/40P01.
+// Delay before retry n (from 0) is BaseDelay*Multiplier^n plus random [0, Jitter), capped at MaxDelay.
+type RetryConfig struct {
+	MaxRetries int           // total attempts, default: 3
+	BaseDelay  time.Duration // default: 25ms
+	MaxDelay   time.Duration // default: 1s
+	Multiplier float64       // default: 2
+	Jitter     time.Duration // default: 0
+}
+
+const (
+	defaultRetryAttempts   = 3
+	defaultRetryBaseDelay  = 25 * time.Millisecond
+	defaultRetryMaxDelay   = time.Second
+	defaultRetryMultiplier = 2
+)
+
+func (rc RetryConfig) withDefaults() RetryConfig {
+	if rc.MaxRetries < 1 {
+		rc.MaxRetries = defaultRetryAttempts
+	}
+	if rc.BaseDelay <= 0 {
+		rc.BaseDelay = defaultRetryBaseDelay
+	}
+	if rc.MaxDelay <= 0 {
+		rc.MaxDelay = defaultRetryMaxDelay
+	}
+	if rc.Multiplier < 1 {
+		rc.Multiplier = defaultRetryMultiplier
+	}
+	if rc.Jitter < 0 {
+		rc.Jitter = 0
+	}
+	return rc
+}
+
+func (rc RetryConfig) delay(retry int) time.Duration {
+	d := float64(rc.BaseDelay) * math.Pow(rc.Multiplier, float64(retry))
+	if rc.Jitter > 0 {
+		d += float64(rand.Int63n(int64(rc.Jitter)))
+	}
+	if d > float64(rc.MaxDelay) {
+		return rc.MaxDelay
+	}
+	return time.Duration(d)
+}
+
 // WithSerializable runs SERIALIZABLE tx with retries for 40001/40P01 and ctx awareness.
+// Retries use a fixed 25-75ms jitter; see WithSerializableOpts for exponential backoff.
 func (c *Client) WithSerializable(ctx context.Context, maxRetries int, fn func(ctx context.Context) error) error {
-	if maxRetries < 1 {
-		maxRetries = 3
+	return c.WithSerializableOpts(ctx, RetryConfig{
+		MaxRetries: maxRetries,
+		BaseDelay:  defaultRetryBaseDelay,
+		Multiplier: 1,
+		Jitter:     50 * time.Millisecond,
+	}, fn)
+}
+
+// WithSerializableOpts runs SERIALIZABLE tx and retries 40001/40P01 with backoff from rc.
+// Waiting between attempts stops early when ctx is done.
+func (c *Client) WithSerializableOpts(ctx context.Context, rc RetryConfig, fn func(ctx context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	return retryTx(ctx, rc, func() error {
+		return c.WithTxOpts(ctx, TxConfig{Iso: pgx.Serializable}, fn)
+	}, sleepCtx)
+}
+
+func retryTx(ctx context.Context, rc RetryConfig, attempt func() error, sleep func(context.Context, time.Duration) error) error {
+	rc = rc.withDefaults()
 	var last error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		last = c.WithTxOpts(ctx, TxConfig{Iso: pgx.Serializable}, fn)
+	for i := 0; i < rc.MaxRetries; i++ {
+		last = attempt()
 		if !isRetriableTxError(last) {
 			return last
 		}
-		// Small jitter between retries, stop if context is done.
-		d := time.Duration(25+rand.Intn(50)) * time.Millisecond
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(d):
+		if i == rc.MaxRetries-1 {
+			break
+		}
+		if err := sleep(ctx, rc.delay(i)); err != nil {
+			return err
 		}
 	}
 	return last
 }
 
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 // Convenience helper: SERIALIZABLE + ReadOnly + optional DEFERRABLE.
 func (c *Client) WithSerializableRO(ctx context.Context, deferrable bool, fn func(ctx context.Context) error) error {
 	return c.WithTxOpts(ctx, TxConfig{
@@ -128,6 +211,9 @@ func (c *Client) WithTxOpts(ctx context.Context, cfg TxConfig, fn func(ctx conte
 	if cfg.ReadOnly {
 		opts.AccessMode = pgx.ReadOnly
 	}
+	if err := cfg.validateLocals(); err != nil {
+		return err
+	}
 
 	tx, err := c.Pool.BeginTx(ctx, opts)
 	if err != nil {
@@ -150,6 +236,31 @@ func (c *Client) WithTxOpts(ctx context.Context, cfg TxConfig, fn func(ctx conte
 		err = tx.Commit(ctx)
 	}()
 
+	if err := applyTxConfig(ctx, tx, cfg); err != nil {
+		return err
+	}
+
+	run := txRunner{tx: tx}
+	txCtx := ContextWithRunner(ctx, run)
+	err = fn(txCtx)
+	return err
+}
+
+func (cfg TxConfig) validateLocals() error {
+	if strings.ContainsRune(cfg.ApplicationName, 0) {
+		return fmt.Errorf("%w: application_name", errInvalidSetting)
+	}
+	if strings.ContainsRune(cfg.Role, 0) {
+		return fmt.Errorf("%w: role", errInvalidSetting)
+	}
+	return nil
+}
+
+func applyTxConfig(ctx context.Context, tx pgx.Tx, cfg TxConfig) error {
+	if err := cfg.validateLocals(); err != nil {
+		return err
+	}
+
 	// DEFERRABLE is set via a dedicated command in pgx/v5.
 	if cfg.Deferrable {
 		if cfg.Iso != pgx.Serializable {
@@ -177,10 +288,30 @@ func (c *Client) WithTxOpts(ctx context.Context, cfg TxConfig, fn func(ctx conte
 		}
 	}
 
-	run := txRunner{tx: tx}
-	txCtx := ContextWithRunner(ctx, run)
-	err = fn(txCtx)
-	return err
+	// SET does not accept bind parameters, so values are quoted explicitly.
+	if cfg.ApplicationName != "" {
+		if _, e := tx.Exec(ctx, "SET LOCAL application_name = "+quoteLiteral(cfg.ApplicationName)); e != nil {
+			return e
+		}
+	}
+	if cfg.Role != "" {
+		if _, e := tx.Exec(ctx, "SET LOCAL ROLE "+quoteIdent(cfg.Role)); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+func quoteLiteral(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if strings.Contains(s, `\`) {
+		return "E'" + strings.ReplaceAll(s, `\`, `\\`) + "'"
+	}
+	return "'" + s + "'"
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
 }
 
 // WithSavepoint creates SAVEPOINT when already in tx, otherwise starts regular tx.
@@ -192,11 +323,16 @@ func (c *Client) WithSavepoint(ctx context.Context, fn func(ctx context.Context)
 	// Already in transaction?
 	if r, ok := ctx.Value(ctxKeyRunner{}).(Runner); ok {
 		if tx, ok := asTx(r); ok {
-			sp := fmt.Sprintf("sp_%d", time.Now().UnixNano())
+			parent := savepointScopeFrom(ctx, tx)
+			if parent == nil {
+				parent = &savepointScope{tx: tx, name: "sp"}
+			}
+			scope := parent.child()
+			sp := scope.name
 			if _, err := tx.Exec(ctx, "SAVEPOINT "+sp); err != nil {
 				return err
 			}
-			spCtx := ContextWithRunner(ctx, txRunner{tx: tx})
+			spCtx := context.WithValue(ContextWithRunner(ctx, txRunner{tx: tx}), ctxKeySavepoint{}, scope)
 			if err := fn(spCtx); err != nil {
 				rbErr := execWithTimeout(tx, "ROLLBACK TO SAVEPOINT "+sp)
 				releaseErr := execWithTimeout(tx, "RELEASE SAVEPOINT "+sp)
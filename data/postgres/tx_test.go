@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -162,6 +163,13 @@ func TestAsTx_FromRawTxProvider(t *testing.T) {
 type txStub struct {
 	execs       []string
 	errByPrefix map[string]error
+	batches     []*pgx.Batch
+	batchRes    *batchResultsStub
+
+	copyTable   pgx.Identifier
+	copyColumns []string
+	copyRows    [][]any
+	copyErr     error
 }
 
 func (t *txStub) Begin(context.Context) (pgx.Tx, error) { return nil, errors.New("not implemented") }
@@ -178,11 +186,28 @@ func (t *txStub) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTa
 }
 func (t *txStub) Query(context.Context, string, ...any) (pgx.Rows, error) { return nil, nil }
 func (t *txStub) QueryRow(context.Context, string, ...any) pgx.Row        { return nil }
-func (t *txStub) CopyFrom(context.Context, pgx.Identifier, []string, pgx.CopyFromSource) (int64, error) {
-	return 0, errors.New("not implemented")
+func (t *txStub) CopyFrom(_ context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	t.copyTable, t.copyColumns = table, columns
+	for src.Next() {
+		vals, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		t.copyRows = append(t.copyRows, vals)
+	}
+	if t.copyErr != nil {
+		return 0, t.copyErr
+	}
+	return int64(len(t.copyRows)), src.Err()
+}
+func (t *txStub) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	t.batches = append(t.batches, b)
+	if t.batchRes == nil {
+		t.batchRes = &batchResultsStub{}
+	}
+	return t.batchRes
 }
-func (t *txStub) SendBatch(context.Context, *pgx.Batch) pgx.BatchResults { return nil }
-func (t *txStub) LargeObjects() pgx.LargeObjects                         { return pgx.LargeObjects{} }
+func (t *txStub) LargeObjects() pgx.LargeObjects { return pgx.LargeObjects{} }
 func (t *txStub) Prepare(context.Context, string, string) (*pgconn.StatementDescription, error) {
 	return nil, errors.New("not implemented")
 }
@@ -199,3 +224,319 @@ func (r rawRunnerStub) Query(context.Context, string, ...any) (pgx.Rows, error)
 	return nil, nil
 }
 func (r rawRunnerStub) QueryRow(context.Context, string, ...any) pgx.Row { return nil }
+
+func TestRetryTx_ExponentialBackoffWithCap(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	var delays []time.Duration
+	sleep := func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	err := retryTx(context.Background(), RetryConfig{
+		MaxRetries: 5,
+		BaseDelay:  10 * time.Millisecond,
+		MaxDelay:   50 * time.Millisecond,
+		Multiplier: 2,
+	}, func() error {
+		attempts++
+		return &pgconn.PgError{Code: sqlStateSerializationFailure}
+	}, sleep)
+
+	if !isSerializationFailure(err) {
+		t.Fatalf("expected last serialization failure, got %v", err)
+	}
+	if attempts != 5 {
+		t.Fatalf("expected 5 attempts, got %d", attempts)
+	}
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("expected %d sleeps, got %v", len(want), delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("delay %d: want %v, got %v", i, want[i], delays[i])
+		}
+	}
+}
+
+func TestRetryTx_StopsOnSuccessOrNonRetriable(t *testing.T) {
+	t.Parallel()
+
+	noSleep := func(context.Context, time.Duration) error { return nil }
+
+	attempts := 0
+	err := retryTx(context.Background(), RetryConfig{MaxRetries: 5}, func() error {
+		attempts++
+		if attempts < 3 {
+			return &pgconn.PgError{Code: sqlStateDeadlockDetected}
+		}
+		return nil
+	}, noSleep)
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success on attempt 3, got err=%v attempts=%d", err, attempts)
+	}
+
+	attempts = 0
+	unique := &pgconn.PgError{Code: SQLStateUniqueViolation}
+	err = retryTx(context.Background(), RetryConfig{MaxRetries: 5}, func() error {
+		attempts++
+		return unique
+	}, noSleep)
+	if !errors.Is(err, unique) || attempts != 1 {
+		t.Fatalf("expected single non-retriable attempt, got err=%v attempts=%d", err, attempts)
+	}
+}
+
+func TestRetryTx_DefaultsToThreeAttempts(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	var delays []time.Duration
+	_ = retryTx(context.Background(), RetryConfig{}, func() error {
+		attempts++
+		return &pgconn.PgError{Code: sqlStateSerializationFailure}
+	}, func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	})
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+	if len(delays) != 2 || delays[0] != 25*time.Millisecond || delays[1] != 50*time.Millisecond {
+		t.Fatalf("unexpected default delays: %v", delays)
+	}
+}
+
+func TestRetryTx_ContextCancelledBetweenAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	attempts := 0
+	err := retryTx(ctx, RetryConfig{MaxRetries: 5}, func() error {
+		attempts++
+		return &pgconn.PgError{Code: sqlStateSerializationFailure}
+	}, sleepCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt before cancellation, got %d", attempts)
+	}
+}
+
+func TestRetryConfig_JitterBounded(t *testing.T) {
+	t.Parallel()
+
+	rc := RetryConfig{BaseDelay: 25 * time.Millisecond, Multiplier: 1, Jitter: 50 * time.Millisecond}.withDefaults()
+	for i := 0; i < 100; i++ {
+		d := rc.delay(i % 3)
+		if d < 25*time.Millisecond || d >= 75*time.Millisecond {
+			t.Fatalf("delay out of [25ms, 75ms): %v", d)
+		}
+	}
+}
+
+func TestWithSerializableOpts_NilClientPool(t *testing.T) {
+	t.Parallel()
+
+	var c *Client
+	err := c.WithSerializableOpts(context.Background(), RetryConfig{}, func(context.Context) error { return nil })
+	if !errors.Is(err, errNilClientPool) {
+		t.Fatalf("expected errNilClientPool, got %v", err)
+	}
+}
+
+func TestApplyTxConfig_EmitsSetLocal(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	err := applyTxConfig(context.Background(), tx, TxConfig{
+		StatementTimeout: 2 * time.Second,
+		ApplicationName:  "payments-api",
+		Role:             "payments_rw",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"SET LOCAL statement_timeout = 2000",
+		"SET LOCAL application_name = 'payments-api'",
+		`SET LOCAL ROLE "payments_rw"`,
+	}
+	if len(tx.execs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, tx.execs)
+	}
+	for i := range want {
+		if tx.execs[i] != want[i] {
+			t.Fatalf("statement %d: want %q, got %q", i, want[i], tx.execs[i])
+		}
+	}
+}
+
+func TestApplyTxConfig_EmptyValuesSkipped(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	if err := applyTxConfig(context.Background(), tx, TxConfig{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tx.execs) != 0 {
+		t.Fatalf("expected no statements, got %v", tx.execs)
+	}
+}
+
+func TestApplyTxConfig_QuotesMaliciousValues(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	err := applyTxConfig(context.Background(), tx, TxConfig{
+		ApplicationName: `x'; DROP TABLE users; --\`,
+		Role:            `admin"; DROP TABLE users; --`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`SET LOCAL application_name = E'x''; DROP TABLE users; --\\'`,
+		`SET LOCAL ROLE "admin""; DROP TABLE users; --"`,
+	}
+	for i := range want {
+		if tx.execs[i] != want[i] {
+			t.Fatalf("statement %d: want %q, got %q", i, want[i], tx.execs[i])
+		}
+	}
+}
+
+func TestApplyTxConfig_RejectsNULByte(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []TxConfig{
+		{ApplicationName: "app\x00name"},
+		{Role: "role\x00"},
+	} {
+		tx := &txStub{}
+		if err := applyTxConfig(context.Background(), tx, cfg); !errors.Is(err, errInvalidSetting) {
+			t.Fatalf("expected errInvalidSetting, got %v", err)
+		}
+		if len(tx.execs) != 0 {
+			t.Fatalf("expected no statements, got %v", tx.execs)
+		}
+	}
+}
+
+func TestApplyTxConfig_StopsOnExecError(t *testing.T) {
+	t.Parallel()
+
+	denied := &pgconn.PgError{Code: "42501"}
+	tx := &txStub{errByPrefix: map[string]error{"SET LOCAL ROLE": denied}}
+	err := applyTxConfig(context.Background(), tx, TxConfig{ApplicationName: "app", Role: "nobody"})
+	if !errors.Is(err, denied) {
+		t.Fatalf("expected role error, got %v", err)
+	}
+}
+
+func TestWithSavepoint_SiblingAndNestedNames(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	c := &Client{}
+	txCtx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	var depths []int
+	record := func(ctx context.Context) error {
+		depths = append(depths, SavepointDepth(ctx))
+		return nil
+	}
+
+	if err := c.WithSavepoint(txCtx, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := c.WithSavepoint(txCtx, func(ctx context.Context) error {
+		if err := record(ctx); err != nil {
+			return err
+		}
+		if err := c.WithSavepoint(ctx, record); err != nil {
+			return err
+		}
+		return c.WithSavepoint(ctx, record)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var created []string
+	for _, q := range tx.execs {
+		if name, ok := strings.CutPrefix(q, "SAVEPOINT "); ok {
+			created = append(created, name)
+		}
+	}
+	want := []string{"sp_1", "sp_2", "sp_2_1", "sp_2_2"}
+	if len(created) != len(want) {
+		t.Fatalf("expected savepoints %v, got %v", want, created)
+	}
+	seen := map[string]bool{}
+	for i := range want {
+		if created[i] != want[i] {
+			t.Fatalf("savepoint %d: want %q, got %q", i, want[i], created[i])
+		}
+		if seen[created[i]] {
+			t.Fatalf("duplicate savepoint name %q", created[i])
+		}
+		seen[created[i]] = true
+	}
+
+	wantDepths := []int{1, 1, 2, 2}
+	for i := range wantDepths {
+		if depths[i] != wantDepths[i] {
+			t.Fatalf("depth %d: want %d, got %d", i, wantDepths[i], depths[i])
+		}
+	}
+	if SavepointDepth(txCtx) != 0 {
+		t.Fatalf("expected depth 0 outside savepoints, got %d", SavepointDepth(txCtx))
+	}
+}
+
+func TestWithSavepoint_NestedRollbackTargetsOwnSavepoint(t *testing.T) {
+	t.Parallel()
+
+	tx := &txStub{}
+	c := &Client{}
+	txCtx := ContextWithRunner(context.Background(), txRunner{tx: tx})
+
+	boom := errors.New("boom")
+	err := c.WithSavepoint(txCtx, func(ctx context.Context) error {
+		_ = c.WithSavepoint(ctx, func(context.Context) error { return boom })
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"SAVEPOINT sp_1",
+		"SAVEPOINT sp_1_1",
+		"ROLLBACK TO SAVEPOINT sp_1_1",
+		"RELEASE SAVEPOINT sp_1_1",
+		"RELEASE SAVEPOINT sp_1",
+	}
+	if len(tx.execs) != len(want) {
+		t.Fatalf("expected %v, got %v", want, tx.execs)
+	}
+	for i := range want {
+		if tx.execs[i] != want[i] {
+			t.Fatalf("statement %d: want %q, got %q", i, want[i], tx.execs[i])
+		}
+	}
+}
+
+func TestSavepointDepth_NilAndEmptyContext(t *testing.T) {
+	t.Parallel()
+
+	if SavepointDepth(nil) != 0 || SavepointDepth(context.Background()) != 0 {
+		t.Fatalf("expected depth 0")
+	}
+}
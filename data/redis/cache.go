@@ -0,0 +1,179 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultCacheJitter spreads expiry by ±10% of the ttl.
+const DefaultCacheJitter = 0.1
+
+var (
+	ErrCacheKey    = errors.New("redis: cache key is required")
+	ErrCacheTTL    = errors.New("redis: cache ttl must be at least 1ms")
+	ErrCacheLoader = errors.New("redis: cache loader is required")
+)
+
+// cacheClient is the part of redis.UniversalClient used by Cache.
+type cacheClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+}
+
+type cacheConfig struct {
+	prefix  string
+	jitter  float64
+	onError func(op, key string, err error)
+}
+
+type CacheOption func(*cacheConfig)
+
+// WithCacheKeyPrefix prepends prefix to every key (e.g. "wallet:balance:").
+func WithCacheKeyPrefix(prefix string) CacheOption {
+	return func(c *cacheConfig) { c.prefix = prefix }
+}
+
+// WithCacheJitter sets the ttl spread as a fraction in [0, 1): each write uses a random ttl
+// in [ttl*(1-f), ttl*(1+f)]. 0 disables jitter.
+func WithCacheJitter(f float64) CacheOption {
+	return func(c *cacheConfig) {
+		if f >= 0 && f < 1 {
+			c.jitter = f
+		}
+	}
+}
+
+// WithCacheErrorHook is called for Redis and decode errors that the cache hides from the
+// caller. op is "get", "decode", "encode" or "set".
+func WithCacheErrorHook(fn func(op, key string, err error)) CacheOption {
+	return func(c *cacheConfig) { c.onError = fn }
+}
+
+// Cache stores values of type T as JSON. Concurrent misses for the same key in this process
+// share one loader call. Safe for concurrent use.
+type Cache[T any] struct {
+	rdb   cacheClient
+	cfg   cacheConfig
+	group singleflight.Group
+	rand  func() float64
+}
+
+// NewCache wraps a client returned by NewRedisClient.
+func NewCache[T any](rdb redis.UniversalClient, opts ...CacheOption) *Cache[T] {
+	c := &Cache[T]{rdb: rdb, cfg: cacheConfig{jitter: DefaultCacheJitter}, rand: rand.Float64}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c.cfg)
+		}
+	}
+	return c
+}
+
+// GetOrLoad returns the cached value for key or calls loader, stores its result for a
+// jittered ttl and returns it. Loader errors are returned and never cached.
+// Redis is best effort: read failures and undecodable entries count as misses, and a failed
+// write still returns the loaded value (see WithCacheErrorHook). The loader runs with the ctx
+// of the caller that triggered it; callers waiting on the same key share its result or error.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, ttl time.Duration, loader func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if c == nil || c.rdb == nil {
+		return zero, errNilClient
+	}
+	if strings.TrimSpace(key) == "" {
+		return zero, ErrCacheKey
+	}
+	if ttl < time.Millisecond {
+		return zero, ErrCacheTTL
+	}
+	if loader == nil {
+		return zero, ErrCacheLoader
+	}
+
+	full := c.cfg.prefix + key
+	if v, ok := c.get(ctx, full); ok {
+		return v, nil
+	}
+
+	v, err, _ := c.group.Do(full, func() (any, error) {
+		// Another caller may have filled the key while this one waited on GET.
+		if v, ok := c.get(ctx, full); ok {
+			return v, nil
+		}
+		v, err := loader(ctx)
+		if err != nil {
+			return zero, err
+		}
+		c.set(ctx, full, v, ttl)
+		return v, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// Delete removes key from Redis.
+func (c *Cache[T]) Delete(ctx context.Context, key string) error {
+	if c == nil || c.rdb == nil {
+		return errNilClient
+	}
+	if strings.TrimSpace(key) == "" {
+		return ErrCacheKey
+	}
+	if err := c.rdb.Del(ctx, c.cfg.prefix+key).Err(); err != nil {
+		return fmt.Errorf("redis: cache delete: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache[T]) get(ctx context.Context, key string) (T, bool) {
+	var v T
+	b, err := c.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.report("get", key, err)
+		}
+		return v, false
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		c.report("decode", key, err)
+		var zero T
+		return zero, false
+	}
+	return v, true
+}
+
+func (c *Cache[T]) set(ctx context.Context, key string, v T, ttl time.Duration) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		c.report("encode", key, err)
+		return
+	}
+	if err := c.rdb.Set(ctx, key, b, jitterTTL(ttl, c.cfg.jitter, c.rand)).Err(); err != nil {
+		c.report("set", key, err)
+	}
+}
+
+func (c *Cache[T]) report(op, key string, err error) {
+	if c.cfg.onError != nil {
+		c.cfg.onError(op, key, err)
+	}
+}
+
+// jitterTTL returns a ttl uniformly in [ttl*(1-f), ttl*(1+f)], never below 1ms.
+func jitterTTL(ttl time.Duration, f float64, rnd func() float64) time.Duration {
+	if f <= 0 {
+		return ttl
+	}
+	delta := time.Duration(float64(ttl) * f * (2*rnd() - 1))
+	return max(ttl+delta, time.Millisecond)
+}
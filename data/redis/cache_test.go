@@ -0,0 +1,234 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// memCacheClient keeps GET/SET/DEL in memory and records the last SET ttl.
+type memCacheClient struct {
+	mu      sync.Mutex
+	vals    map[string][]byte
+	lastTTL time.Duration
+	sets    int
+	getErr  error
+	setErr  error
+}
+
+func newMemCacheClient() *memCacheClient {
+	return &memCacheClient{vals: map[string][]byte{}}
+}
+
+func (c *memCacheClient) Get(_ context.Context, key string) *goredis.StringCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.getErr != nil {
+		return goredis.NewStringResult("", c.getErr)
+	}
+	v, ok := c.vals[key]
+	if !ok {
+		return goredis.NewStringResult("", goredis.Nil)
+	}
+	return goredis.NewStringResult(string(v), nil)
+}
+
+func (c *memCacheClient) Set(_ context.Context, key string, value any, ttl time.Duration) *goredis.StatusCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.setErr != nil {
+		return goredis.NewStatusResult("", c.setErr)
+	}
+	c.vals[key] = value.([]byte)
+	c.lastTTL = ttl
+	c.sets++
+	return goredis.NewStatusResult("OK", nil)
+}
+
+func (c *memCacheClient) Del(_ context.Context, keys ...string) *goredis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	for _, k := range keys {
+		if _, ok := c.vals[k]; ok {
+			delete(c.vals, k)
+			n++
+		}
+	}
+	return goredis.NewIntResult(n, nil)
+}
+
+type cachedBalance struct {
+	Account string `json:"account"`
+	Amount  int64  `json:"amount"`
+}
+
+func newTestCache[T any](rdb cacheClient, opts ...CacheOption) *Cache[T] {
+	c := NewCache[T](nil, opts...)
+	c.rdb = rdb
+	return c
+}
+
+func TestCache_ConcurrentMissesLoadOnce(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemCacheClient()
+	cache := newTestCache[cachedBalance](rdb)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(context.Context) (cachedBalance, error) {
+		calls.Add(1)
+		<-release
+		return cachedBalance{Account: "acc-1", Amount: 42}, nil
+	}
+
+	const n = 16
+	var wg sync.WaitGroup
+	results := make(chan cachedBalance, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := cache.GetOrLoad(ctx, "balance:acc-1", time.Minute, loader)
+			if err != nil {
+				t.Errorf("GetOrLoad: %v", err)
+				return
+			}
+			results <- v
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // let every goroutine reach singleflight
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 loader call, got %d", got)
+	}
+	for v := range results {
+		if v.Account != "acc-1" || v.Amount != 42 {
+			t.Fatalf("unexpected value %+v", v)
+		}
+	}
+	if rdb.sets != 1 {
+		t.Fatalf("expected 1 SET, got %d", rdb.sets)
+	}
+
+	// A hit decodes the stored JSON without calling the loader.
+	v, err := cache.GetOrLoad(ctx, "balance:acc-1", time.Minute, func(context.Context) (cachedBalance, error) {
+		t.Fatal("loader must not run on a hit")
+		return cachedBalance{}, nil
+	})
+	if err != nil || v.Amount != 42 {
+		t.Fatalf("hit: %+v, %v", v, err)
+	}
+}
+
+func TestCache_LoaderErrorNotCached(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemCacheClient()
+	cache := newTestCache[int](rdb)
+
+	boom := errors.New("db down")
+	if _, err := cache.GetOrLoad(ctx, "k", time.Minute, func(context.Context) (int, error) { return 0, boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected loader error, got %v", err)
+	}
+	if rdb.sets != 0 {
+		t.Fatalf("loader error must not be written, sets=%d", rdb.sets)
+	}
+
+	calls := 0
+	v, err := cache.GetOrLoad(ctx, "k", time.Minute, func(context.Context) (int, error) { calls++; return 7, nil })
+	if err != nil || v != 7 || calls != 1 {
+		t.Fatalf("retry after error: v=%d err=%v calls=%d", v, err, calls)
+	}
+}
+
+func TestCache_RedisErrorsAreMisses(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemCacheClient()
+	rdb.getErr = errors.New("connection refused")
+	rdb.setErr = rdb.getErr
+
+	var ops []string
+	cache := newTestCache[string](rdb, WithCacheErrorHook(func(op, _ string, _ error) { ops = append(ops, op) }))
+	v, err := cache.GetOrLoad(ctx, "k", time.Minute, func(context.Context) (string, error) { return "fresh", nil })
+	if err != nil || v != "fresh" {
+		t.Fatalf("Redis outage must fall through to loader: %q, %v", v, err)
+	}
+	if len(ops) != 3 || ops[0] != "get" || ops[2] != "set" {
+		t.Fatalf("unexpected hook calls %v", ops)
+	}
+
+	rdb = newMemCacheClient()
+	rdb.vals["k"] = []byte("{not json")
+	cache = newTestCache[string](rdb)
+	if v, err := cache.GetOrLoad(ctx, "k", time.Minute, func(context.Context) (string, error) { return "fresh", nil }); err != nil || v != "fresh" {
+		t.Fatalf("undecodable entry must be a miss: %q, %v", v, err)
+	}
+}
+
+func TestCache_PrefixDeleteAndValidation(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemCacheClient()
+	cache := newTestCache[int](rdb, WithCacheKeyPrefix("wallet:"))
+	load := func(context.Context) (int, error) { return 1, nil }
+
+	if _, err := cache.GetOrLoad(ctx, "k", time.Minute, load); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rdb.vals["wallet:k"]; !ok {
+		t.Fatalf("expected prefixed key, have %v", rdb.vals)
+	}
+	if err := cache.Delete(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if len(rdb.vals) != 0 {
+		t.Fatalf("Delete left %v", rdb.vals)
+	}
+
+	if _, err := cache.GetOrLoad(ctx, " ", time.Minute, load); !errors.Is(err, ErrCacheKey) {
+		t.Fatalf("expected ErrCacheKey, got %v", err)
+	}
+	if _, err := cache.GetOrLoad(ctx, "k", 0, load); !errors.Is(err, ErrCacheTTL) {
+		t.Fatalf("expected ErrCacheTTL, got %v", err)
+	}
+	if _, err := cache.GetOrLoad(ctx, "k", time.Minute, nil); !errors.Is(err, ErrCacheLoader) {
+		t.Fatalf("expected ErrCacheLoader, got %v", err)
+	}
+	if _, err := NewCache[int](nil).GetOrLoad(ctx, "k", time.Minute, load); !errors.Is(err, errNilClient) {
+		t.Fatalf("expected errNilClient, got %v", err)
+	}
+}
+
+func TestJitterTTL_WithinBounds(t *testing.T) {
+	ttl := 10 * time.Second
+	lo, hi := 9*time.Second, 11*time.Second
+
+	if got := jitterTTL(ttl, 0.1, func() float64 { return 0 }); got != lo {
+		t.Fatalf("rnd=0: got %v, want %v", got, lo)
+	}
+	if got := jitterTTL(ttl, 0.1, func() float64 { return 0.999999 }); got < lo || got > hi {
+		t.Fatalf("rnd≈1: got %v", got)
+	}
+	if got := jitterTTL(ttl, 0, func() float64 { return 0.9 }); got != ttl {
+		t.Fatalf("jitter disabled: got %v", got)
+	}
+	if got := jitterTTL(time.Millisecond, 0.5, func() float64 { return 0 }); got != time.Millisecond {
+		t.Fatalf("ttl must not drop below 1ms, got %v", got)
+	}
+
+	rdb := newMemCacheClient()
+	cache := newTestCache[int](rdb)
+	for i := 0; i < 200; i++ {
+		cache.set(context.Background(), "k", i, ttl)
+		if rdb.lastTTL < lo || rdb.lastTTL > hi {
+			t.Fatalf("written ttl %v outside [%v, %v]", rdb.lastTTL, lo, hi)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package redis
 
 import (
 	"context"
-	"crypto/tls"
 	"strings"
 	"time"
 
@@ -23,6 +22,10 @@ func NewRedisClient(ctx context.Context, cfg Config) (redis.UniversalClient, err
 	if err := validateConfig(cfg, mode, addrs); err != nil {
 		return nil, err
 	}
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	opt := &redis.UniversalOptions{
 		Addrs:        addrs,
@@ -32,15 +35,16 @@ func NewRedisClient(ctx context.Context, cfg Config) (redis.UniversalClient, err
 		Password:     cfg.Password,
 		PoolSize:     cfg.PoolSize,
 		MinIdleConns: cfg.MinIdleConns,
+		PoolTimeout:  cfg.PoolTimeout,
 		DialTimeout:  cfg.DialTimeout,
 		ReadTimeout:  cfg.ReadTimeout,
 		WriteTimeout: cfg.WriteTimeout,
-	}
 
-	if cfg.TLSEnabled {
-		opt.TLSConfig = &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		}
+		MaxRetries:      cfg.MaxRetries,
+		MinRetryBackoff: cfg.MinRetryBackoff,
+		MaxRetryBackoff: cfg.MaxRetryBackoff,
+
+		TLSConfig: tlsConfig,
 	}
 
 	rdb := NewUniversal(opt)
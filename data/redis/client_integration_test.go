@@ -40,6 +40,37 @@ func TestNewRedisClient_Single_Integration(t *testing.T) {
 	require.Equal(t, "ok", v)
 }
 
+func TestLocker_Integration(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c, err := redispkg.NewRedisClient(ctx, redispkg.Config{Mode: redispkg.ModeSingle, Addr: integrationRedisAddr()})
+	require.NoError(t, err)
+	defer func() {
+		_ = c.Close()
+	}()
+
+	locker := redispkg.NewLocker(c)
+	key := fmt.Sprintf("go-lib:data:redis:it:lock:%d", time.Now().UnixNano())
+
+	lock, err := locker.Acquire(ctx, key, 5*time.Second)
+	require.NoError(t, err)
+	_, err = locker.Acquire(ctx, key, 5*time.Second)
+	require.ErrorIs(t, err, redispkg.ErrNotAcquired)
+
+	require.NoError(t, lock.Refresh(ctx, 10*time.Second))
+	ttl, err := c.PTTL(ctx, key).Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, 5*time.Second)
+
+	require.NoError(t, lock.Release(ctx))
+	require.ErrorIs(t, lock.Release(ctx), redispkg.ErrLockNotHeld)
+
+	again, err := locker.Acquire(ctx, key, 5*time.Second)
+	require.NoError(t, err)
+	require.NoError(t, again.Release(ctx))
+}
+
 func TestNewRedisClient_Sentinel_Integration(t *testing.T) {
 	addrs, ok := csvEnv("REDIS_TEST_SENTINEL_ADDRS")
 	master := strings.TrimSpace(os.Getenv("REDIS_TEST_SENTINEL_MASTER"))
@@ -276,3 +276,107 @@ func TestNewRedisClient_Validate_MasterNameOnlyForSentinel(t *testing.T) {
 		t.Fatalf("NewUniversal must not be called on invalid config")
 	}
 }
+
+func TestNewRedisClient_PoolAndRetryOptionsApplied(t *testing.T) {
+	var captured *goredis.UniversalOptions
+
+	restore := stubNewUniversal(t, func(opt *goredis.UniversalOptions) goredis.UniversalClient {
+		captured = opt
+		return goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	})
+	defer restore()
+
+	cfg := Config{
+		Mode:            ModeSingle,
+		Addr:            "127.0.0.1:6379",
+		DialTimeout:     50 * time.Millisecond,
+		ReadTimeout:     200 * time.Millisecond,
+		WriteTimeout:    300 * time.Millisecond,
+		PoolSize:        64,
+		MinIdleConns:    8,
+		PoolTimeout:     time.Second,
+		MaxRetries:      5,
+		MinRetryBackoff: 10 * time.Millisecond,
+		MaxRetryBackoff: 500 * time.Millisecond,
+	}
+
+	_, err := NewRedisClient(context.Background(), cfg)
+	if err == nil {
+		t.Fatalf("expected ping error, got nil")
+	}
+	if captured == nil {
+		t.Fatalf("NewUniversal was not called")
+	}
+	if captured.PoolSize != 64 || captured.MinIdleConns != 8 || captured.PoolTimeout != time.Second {
+		t.Fatalf("unexpected pool options: size=%d minIdle=%d timeout=%v", captured.PoolSize, captured.MinIdleConns, captured.PoolTimeout)
+	}
+	if captured.ReadTimeout != 200*time.Millisecond || captured.WriteTimeout != 300*time.Millisecond {
+		t.Fatalf("unexpected io timeouts: read=%v write=%v", captured.ReadTimeout, captured.WriteTimeout)
+	}
+	if captured.MaxRetries != 5 || captured.MinRetryBackoff != 10*time.Millisecond || captured.MaxRetryBackoff != 500*time.Millisecond {
+		t.Fatalf("unexpected retry options: retries=%d min=%v max=%v", captured.MaxRetries, captured.MinRetryBackoff, captured.MaxRetryBackoff)
+	}
+}
+
+func TestNewRedisClient_ZeroTuningKeepsDefaults(t *testing.T) {
+	var captured *goredis.UniversalOptions
+
+	restore := stubNewUniversal(t, func(opt *goredis.UniversalOptions) goredis.UniversalClient {
+		captured = opt
+		return goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	})
+	defer restore()
+
+	_, _ = NewRedisClient(context.Background(), Config{Mode: ModeSingle, Addr: "127.0.0.1:6379", DialTimeout: 50 * time.Millisecond})
+	if captured == nil {
+		t.Fatalf("NewUniversal was not called")
+	}
+	if captured.PoolSize != 0 || captured.MinIdleConns != 0 || captured.MaxRetries != 0 ||
+		captured.MinRetryBackoff != 0 || captured.MaxRetryBackoff != 0 || captured.PoolTimeout != 0 {
+		t.Fatalf("zero config must leave go-redis defaults, got %+v", captured)
+	}
+}
+
+func TestNewRedisClient_Validate_Tuning(t *testing.T) {
+	called := false
+	restore := stubNewUniversal(t, func(opt *goredis.UniversalOptions) goredis.UniversalClient {
+		called = true
+		return goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	})
+	defer restore()
+
+	cases := []struct {
+		name string
+		cfg  Config
+		want error
+	}{
+		{"negative pool size", Config{PoolSize: -1}, errInvalidPoolSize},
+		{"negative min idle", Config{MinIdleConns: -1}, errInvalidMinIdleConns},
+		{"min idle above pool size", Config{PoolSize: 4, MinIdleConns: 5}, errInvalidMinIdleConns},
+		{"negative dial timeout", Config{DialTimeout: -time.Second}, errInvalidTimeout},
+		{"negative pool timeout", Config{PoolTimeout: -time.Second}, errInvalidTimeout},
+		{"read timeout below -2", Config{ReadTimeout: -3}, errInvalidTimeout},
+		{"max retries below -1", Config{MaxRetries: -2}, errInvalidMaxRetries},
+		{"backoff below -1", Config{MinRetryBackoff: -2}, errInvalidRetryBackoff},
+		{"min backoff above max", Config{MinRetryBackoff: time.Second, MaxRetryBackoff: time.Millisecond}, errInvalidRetryBackoff},
+	}
+	for _, tc := range cases {
+		tc.cfg.Mode = ModeSingle
+		tc.cfg.Addr = "127.0.0.1:6379"
+		_, err := NewRedisClient(context.Background(), tc.cfg)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if called {
+		t.Fatalf("NewUniversal must not be called on invalid config")
+	}
+
+	_, _ = NewRedisClient(context.Background(), Config{
+		Mode: ModeSingle, Addr: "127.0.0.1:6379", DialTimeout: 50 * time.Millisecond,
+		MaxRetries: -1, MinRetryBackoff: -1, MaxRetryBackoff: -1, ReadTimeout: -1, WriteTimeout: -2,
+	})
+	if !called {
+		t.Fatalf("go-redis sentinel values (-1/-2) must pass validation")
+	}
+}
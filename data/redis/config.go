@@ -27,7 +27,16 @@ type Config struct {
 	WriteTimeout time.Duration
 	PoolSize     int
 	MinIdleConns int
-	TLSEnabled   bool
+	PoolTimeout  time.Duration
+
+	TLSEnabled        bool
+	TLSClientCertPath string
+	TLSClientKeyPath  string
+	TLSCACertPath     string
+
+	MaxRetries      int
+	MinRetryBackoff time.Duration
+	MaxRetryBackoff time.Duration
 }
 
 var (
@@ -39,6 +48,14 @@ var (
 	errClusterModeAddrCount = errors.New("redis: cluster mode requires at least two addresses")
 	errClusterDBUnsupported = errors.New("redis: db must be 0 in cluster mode")
 	errInvalidDB            = errors.New("redis: db must be >= 0")
+	errInvalidPoolSize      = errors.New("redis: pool size must be >= 0")
+	errInvalidMinIdleConns  = errors.New("redis: min idle conns must be >= 0 and <= pool size")
+	errInvalidTimeout       = errors.New("redis: invalid timeout")
+	errInvalidMaxRetries    = errors.New("redis: max retries must be >= -1")
+	errInvalidRetryBackoff  = errors.New("redis: invalid retry backoff")
+	errTLSNotEnabled        = errors.New("redis: tls files require TLSEnabled")
+	errTLSCertKeyPair       = errors.New("redis: tls client cert and key must be set together")
+	errTLSInvalidCACert     = errors.New("redis: tls ca cert contains no valid certificates")
 )
 
 func normalizeMode(v string) Mode {
@@ -69,6 +86,9 @@ func validateConfig(cfg Config, mode Mode, addrs []string) error {
 	if cfg.DB < 0 {
 		return errInvalidDB
 	}
+	if err := validateTuning(cfg); err != nil {
+		return err
+	}
 	if len(addrs) == 0 {
 		return errAddressRequired
 	}
@@ -102,3 +122,28 @@ func validateConfig(cfg Config, mode Mode, addrs []string) error {
 		return errUnsupportedMode
 	}
 }
+
+func validateTuning(cfg Config) error {
+	if cfg.PoolSize < 0 {
+		return errInvalidPoolSize
+	}
+	if cfg.MinIdleConns < 0 || (cfg.PoolSize > 0 && cfg.MinIdleConns > cfg.PoolSize) {
+		return errInvalidMinIdleConns
+	}
+	if cfg.DialTimeout < 0 || cfg.PoolTimeout < 0 {
+		return errInvalidTimeout
+	}
+	if cfg.ReadTimeout < -2 || cfg.WriteTimeout < -2 {
+		return errInvalidTimeout
+	}
+	if cfg.MaxRetries < -1 {
+		return errInvalidMaxRetries
+	}
+	if cfg.MinRetryBackoff < -1 || cfg.MaxRetryBackoff < -1 {
+		return errInvalidRetryBackoff
+	}
+	if cfg.MinRetryBackoff > 0 && cfg.MaxRetryBackoff > 0 && cfg.MinRetryBackoff > cfg.MaxRetryBackoff {
+		return errInvalidRetryBackoff
+	}
+	return nil
+}
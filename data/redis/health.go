@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const DefaultHealthCheckTimeout = time.Second
+
+var errNilClient = errors.New("redis: client is nil")
+
+func HealthCheck(ctx context.Context, rdb redis.UniversalClient) error {
+	if rdb == nil {
+		return errNilClient
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+		defer cancel()
+	}
+
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("redis: ping: %w", ctxErr)
+		}
+		return fmt.Errorf("redis: ping: %w", err)
+	}
+	return nil
+}
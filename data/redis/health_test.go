@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func TestHealthCheck_UnreachableFailsPromptly(t *testing.T) {
+	rdb := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer func() { _ = rdb.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := HealthCheck(ctx, rdb)
+	if err == nil {
+		t.Fatalf("expected ping error, got nil")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("health check took too long: %v", d)
+	}
+}
+
+func TestHealthCheck_CanceledContext(t *testing.T) {
+	rdb := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	defer func() { _ = rdb.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := HealthCheck(ctx, rdb); err == nil {
+		t.Fatalf("expected error for canceled context")
+	}
+}
+
+func TestHealthCheck_NilClient(t *testing.T) {
+	if err := HealthCheck(context.Background(), nil); !errors.Is(err, errNilClient) {
+		t.Fatalf("expected errNilClient, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	ErrNotAcquired = errors.New("redis: lock not acquired")
+	ErrLockNotHeld = errors.New("redis: lock not held")
+	ErrLockKey     = errors.New("redis: lock key is required")
+	ErrLockTTL     = errors.New("redis: lock ttl must be at least 1ms")
+)
+
+const (
+	releaseLua = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+	refreshLua = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+)
+
+var (
+	releaseScript = redis.NewScript(releaseLua)
+	refreshScript = redis.NewScript(refreshLua)
+)
+
+// lockClient is the part of redis.UniversalClient used by Locker.
+type lockClient interface {
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+	redis.Scripter
+}
+
+// Locker is a mutex on a single Redis deployment: SET NX PX with a random token, released and
+// refreshed by Lua scripts that only touch the key while it still holds that token.
+// It is not multi-master Redlock; a failover can lose a lock.
+type Locker struct {
+	rdb lockClient
+}
+
+// NewLocker wraps a client returned by NewRedisClient.
+func NewLocker(rdb redis.UniversalClient) *Locker {
+	return &Locker{rdb: rdb}
+}
+
+// Lock is a held lock. Copies share the token, so any copy can Release or Refresh it.
+type Lock struct {
+	rdb   lockClient
+	key   string
+	token string
+}
+
+// Acquire takes key for ttl (millisecond precision). It returns ErrNotAcquired if the key
+// is held; it does not wait or retry.
+func (l *Locker) Acquire(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	if l == nil || l.rdb == nil {
+		return Lock{}, errNilClient
+	}
+	if strings.TrimSpace(key) == "" {
+		return Lock{}, ErrLockKey
+	}
+	if ttl < time.Millisecond {
+		return Lock{}, ErrLockTTL
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return Lock{}, err
+	}
+	ok, err := l.rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return Lock{}, fmt.Errorf("redis: lock %q: %w", key, err)
+	}
+	if !ok {
+		return Lock{}, ErrNotAcquired
+	}
+	return Lock{rdb: l.rdb, key: key, token: token}, nil
+}
+
+func (k Lock) Key() string   { return k.key }
+func (k Lock) Token() string { return k.token }
+
+// Release deletes the key if it still holds this lock's token. It returns ErrLockNotHeld
+// if the lock expired or was taken by someone else.
+func (k Lock) Release(ctx context.Context) error {
+	if k.rdb == nil {
+		return ErrLockNotHeld
+	}
+	n, err := releaseScript.Run(ctx, k.rdb, []string{k.key}, k.token).Int64()
+	if err != nil {
+		return fmt.Errorf("redis: unlock %q: %w", k.key, err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Refresh resets the key's ttl if it still holds this lock's token; otherwise ErrLockNotHeld.
+func (k Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	if k.rdb == nil {
+		return ErrLockNotHeld
+	}
+	if ttl < time.Millisecond {
+		return ErrLockTTL
+	}
+	n, err := refreshScript.Run(ctx, k.rdb, []string{k.key}, k.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("redis: refresh lock %q: %w", k.key, err)
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("redis: lock token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
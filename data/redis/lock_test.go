@@ -0,0 +1,216 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// memLockClient emulates SET NX PX and the two lock scripts in memory.
+type memLockClient struct {
+	mu   sync.Mutex
+	now  time.Time
+	vals map[string]string
+	exps map[string]time.Time
+	err  error
+}
+
+func newMemLockClient() *memLockClient {
+	return &memLockClient{now: time.Unix(1_000, 0), vals: map[string]string{}, exps: map[string]time.Time{}}
+}
+
+func (c *memLockClient) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *memLockClient) get(key string) (string, bool) {
+	if exp, ok := c.exps[key]; ok && !c.now.Before(exp) {
+		delete(c.vals, key)
+		delete(c.exps, key)
+	}
+	v, ok := c.vals[key]
+	return v, ok
+}
+
+func (c *memLockClient) SetNX(_ context.Context, key string, value any, ttl time.Duration) *goredis.BoolCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return goredis.NewBoolResult(false, c.err)
+	}
+	if _, ok := c.get(key); ok {
+		return goredis.NewBoolResult(false, nil)
+	}
+	c.vals[key] = value.(string)
+	c.exps[key] = c.now.Add(ttl)
+	return goredis.NewBoolResult(true, nil)
+}
+
+func (c *memLockClient) Eval(_ context.Context, script string, keys []string, args ...any) *goredis.Cmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return goredis.NewCmdResult(nil, c.err)
+	}
+	v, ok := c.get(keys[0])
+	if !ok || v != args[0].(string) {
+		return goredis.NewCmdResult(int64(0), nil)
+	}
+	switch script {
+	case releaseLua:
+		delete(c.vals, keys[0])
+		delete(c.exps, keys[0])
+	case refreshLua:
+		c.exps[keys[0]] = c.now.Add(time.Duration(args[1].(int64)) * time.Millisecond)
+	default:
+		return goredis.NewCmdResult(nil, errors.New("unknown script"))
+	}
+	return goredis.NewCmdResult(int64(1), nil)
+}
+
+func (c *memLockClient) EvalSha(ctx context.Context, sha string, keys []string, args ...any) *goredis.Cmd {
+	for _, src := range []string{releaseLua, refreshLua} {
+		if h := sha1.Sum([]byte(src)); hex.EncodeToString(h[:]) == sha {
+			return c.Eval(ctx, src, keys, args...)
+		}
+	}
+	return goredis.NewCmdResult(nil, errors.New("unknown sha"))
+}
+
+func (c *memLockClient) EvalRO(ctx context.Context, script string, keys []string, args ...any) *goredis.Cmd {
+	return c.Eval(ctx, script, keys, args...)
+}
+
+func (c *memLockClient) EvalShaRO(ctx context.Context, sha string, keys []string, args ...any) *goredis.Cmd {
+	return c.EvalSha(ctx, sha, keys, args...)
+}
+
+func (c *memLockClient) ScriptExists(_ context.Context, hashes ...string) *goredis.BoolSliceCmd {
+	return goredis.NewBoolSliceResult(make([]bool, len(hashes)), nil)
+}
+
+func (c *memLockClient) ScriptLoad(_ context.Context, script string) *goredis.StringCmd {
+	h := sha1.Sum([]byte(script))
+	return goredis.NewStringResult(hex.EncodeToString(h[:]), nil)
+}
+
+func TestLocker_AcquireContendRelease(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemLockClient()
+	locker := &Locker{rdb: rdb}
+
+	lock, err := locker.Acquire(ctx, "payments:42", time.Second)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if lock.Key() != "payments:42" || len(lock.Token()) != 32 {
+		t.Fatalf("unexpected lock %q token %q", lock.Key(), lock.Token())
+	}
+
+	if _, err := locker.Acquire(ctx, "payments:42", time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("contended Acquire: expected ErrNotAcquired, got %v", err)
+	}
+	if _, err := locker.Acquire(ctx, "payments:43", time.Second); err != nil {
+		t.Fatalf("other key must be free: %v", err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if err := lock.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("second Release: expected ErrLockNotHeld, got %v", err)
+	}
+
+	again, err := locker.Acquire(ctx, "payments:42", time.Second)
+	if err != nil {
+		t.Fatalf("Acquire after release: %v", err)
+	}
+	if again.Token() == lock.Token() {
+		t.Fatal("tokens must be unique per acquisition")
+	}
+}
+
+func TestLock_ReleaseDoesNotDeleteOtherHolder(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemLockClient()
+	locker := &Locker{rdb: rdb}
+
+	stale, err := locker.Acquire(ctx, "job", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	rdb.advance(100 * time.Millisecond) // stale expired
+
+	current, err := locker.Acquire(ctx, "job", time.Second)
+	if err != nil {
+		t.Fatalf("Acquire after expiry: %v", err)
+	}
+	if err := stale.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("stale Release: expected ErrLockNotHeld, got %v", err)
+	}
+	if err := stale.Refresh(ctx, time.Second); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("stale Refresh: expected ErrLockNotHeld, got %v", err)
+	}
+	if _, err := locker.Acquire(ctx, "job", time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("current holder must keep the key, got %v", err)
+	}
+	if err := current.Release(ctx); err != nil {
+		t.Fatalf("current Release: %v", err)
+	}
+}
+
+func TestLock_Refresh(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemLockClient()
+	locker := &Locker{rdb: rdb}
+
+	lock, err := locker.Acquire(ctx, "job", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	rdb.advance(80 * time.Millisecond)
+	if err := lock.Refresh(ctx, 100*time.Millisecond); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	rdb.advance(80 * time.Millisecond) // 160ms after acquire, 80ms after refresh
+	if _, err := locker.Acquire(ctx, "job", time.Second); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("refreshed lock must still be held, got %v", err)
+	}
+	if err := lock.Refresh(ctx, 0); !errors.Is(err, ErrLockTTL) {
+		t.Fatalf("expected ErrLockTTL, got %v", err)
+	}
+}
+
+func TestLocker_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	if _, err := NewLocker(nil).Acquire(ctx, "k", time.Second); !errors.Is(err, errNilClient) {
+		t.Fatalf("expected errNilClient, got %v", err)
+	}
+
+	locker := &Locker{rdb: newMemLockClient()}
+	if _, err := locker.Acquire(ctx, " ", time.Second); !errors.Is(err, ErrLockKey) {
+		t.Fatalf("expected ErrLockKey, got %v", err)
+	}
+	if _, err := locker.Acquire(ctx, "k", time.Microsecond); !errors.Is(err, ErrLockTTL) {
+		t.Fatalf("expected ErrLockTTL, got %v", err)
+	}
+
+	down := newMemLockClient()
+	down.err = errors.New("connection refused")
+	if _, err := (&Locker{rdb: down}).Acquire(ctx, "k", time.Second); !errors.Is(err, down.err) || errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("expected wrapped transport error, got %v", err)
+	}
+
+	if err := (Lock{}).Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("zero Lock Release: expected ErrLockNotHeld, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	certPath := strings.TrimSpace(cfg.TLSClientCertPath)
+	keyPath := strings.TrimSpace(cfg.TLSClientKeyPath)
+	caPath := strings.TrimSpace(cfg.TLSCACertPath)
+
+	if !cfg.TLSEnabled {
+		if certPath != "" || keyPath != "" || caPath != "" {
+			return nil, errTLSNotEnabled
+		}
+		return nil, nil
+	}
+	if (certPath == "") != (keyPath == "") {
+		return nil, errTLSCertKeyPair
+	}
+
+	tc := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certPath != "" {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("redis: load tls client cert: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	if caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("redis: read tls ca cert: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errTLSInvalidCACert
+		}
+		tc.RootCAs = pool
+	}
+
+	return tc, nil
+}
@@ -0,0 +1,144 @@
+package redis
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func writeTestCert(t *testing.T) (certPath, keyPath string, certDER []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "redis-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certDER, err = x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create cert: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	dir := t.TempDir()
+	certPath = filepath.Join(dir, "client.crt")
+	keyPath = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certPath, keyPath, certDER
+}
+
+func TestNewRedisClient_MutualTLSAndUsernameApplied(t *testing.T) {
+	certPath, keyPath, certDER := writeTestCert(t)
+
+	var captured *goredis.UniversalOptions
+	restore := stubNewUniversal(t, func(opt *goredis.UniversalOptions) goredis.UniversalClient {
+		captured = opt
+		return goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	})
+	defer restore()
+
+	_, err := NewRedisClient(context.Background(), Config{
+		Mode:              ModeSingle,
+		Addr:              "example:6379",
+		Username:          "svc-payments",
+		Password:          "secret",
+		TLSEnabled:        true,
+		TLSClientCertPath: certPath,
+		TLSClientKeyPath:  keyPath,
+		TLSCACertPath:     certPath,
+		DialTimeout:       50 * time.Millisecond,
+	})
+	if err == nil {
+		t.Fatalf("expected ping error, got nil")
+	}
+	if captured == nil {
+		t.Fatalf("NewUniversal was not called")
+	}
+	if captured.Username != "svc-payments" {
+		t.Fatalf("expected Username to be mapped, got %q", captured.Username)
+	}
+	tc := captured.TLSConfig
+	if tc == nil {
+		t.Fatalf("expected TLSConfig")
+	}
+	if tc.MinVersion == 0 {
+		t.Fatalf("expected MinVersion to be kept")
+	}
+	if len(tc.Certificates) != 1 || len(tc.Certificates[0].Certificate) == 0 ||
+		!bytes.Equal(tc.Certificates[0].Certificate[0], certDER) {
+		t.Fatalf("expected loaded client certificate, got %+v", tc.Certificates)
+	}
+	if tc.RootCAs == nil {
+		t.Fatalf("expected RootCAs from TLSCACertPath")
+	}
+}
+
+func TestNewRedisClient_Validate_TLSFiles(t *testing.T) {
+	certPath, keyPath, _ := writeTestCert(t)
+	garbage := filepath.Join(t.TempDir(), "garbage.pem")
+	if err := os.WriteFile(garbage, []byte("not a cert"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	called := false
+	restore := stubNewUniversal(t, func(opt *goredis.UniversalOptions) goredis.UniversalClient {
+		called = true
+		return goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1"})
+	})
+	defer restore()
+
+	cases := []struct {
+		name string
+		cfg  Config
+		want error
+	}{
+		{"files without tls", Config{TLSCACertPath: certPath}, errTLSNotEnabled},
+		{"cert without key", Config{TLSEnabled: true, TLSClientCertPath: certPath}, errTLSCertKeyPair},
+		{"key without cert", Config{TLSEnabled: true, TLSClientKeyPath: keyPath}, errTLSCertKeyPair},
+		{"missing cert file", Config{TLSEnabled: true, TLSClientCertPath: missing, TLSClientKeyPath: keyPath}, os.ErrNotExist},
+		{"missing ca file", Config{TLSEnabled: true, TLSCACertPath: missing}, os.ErrNotExist},
+		{"invalid ca file", Config{TLSEnabled: true, TLSCACertPath: garbage}, errTLSInvalidCACert},
+	}
+	for _, tc := range cases {
+		tc.cfg.Mode = ModeSingle
+		tc.cfg.Addr = "127.0.0.1:6379"
+		_, err := NewRedisClient(context.Background(), tc.cfg)
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if called {
+		t.Fatalf("NewUniversal must not be called on invalid tls config")
+	}
+}
@@ -174,6 +174,59 @@ func TestBaseEvent_WithMeta_CopyOnWrite(t *testing.T) {
 	}
 }
 
+func TestNewBaseEventWithID(t *testing.T) {
+	id := uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+	e, err := domain.NewBaseEventWithID(" payment.completed ", "payment-service", id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.ID != id || e.Name != "payment.completed" || e.SchemaVersion != 1 {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	_, err = domain.NewBaseEventWithID("payment.completed", "payment-service", uuid.Nil)
+	if !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrInvalidEventID) {
+		t.Fatalf("expected invalid id, got %v", err)
+	}
+
+	_, err = domain.NewBaseEventWithID("", "payment-service", id)
+	if !errors.Is(err, domain.ErrInvalidEventName) {
+		t.Fatalf("expected invalid name, got %v", err)
+	}
+}
+
+func TestDeterministicEventID(t *testing.T) {
+	ns := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+
+	a := domain.DeterministicEventID(ns, "payment.completed", "tx-1")
+	b := domain.DeterministicEventID(ns, "payment.completed", "tx-1")
+	if a != b {
+		t.Fatalf("same parts must yield same id: %s != %s", a, b)
+	}
+	if a == uuid.Nil || a.Version() != 5 {
+		t.Fatalf("expected UUIDv5, got %s", a)
+	}
+
+	distinct := []uuid.UUID{
+		a,
+		domain.DeterministicEventID(ns, "payment.completed", "tx-2"),
+		domain.DeterministicEventID(ns, "payment.completedtx-1"),
+		domain.DeterministicEventID(ns, "payment.complete", "dtx-1"),
+		domain.DeterministicEventID(ns, "payment.completed", "tx-1", ""),
+		domain.DeterministicEventID(uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7"), "payment.completed", "tx-1"),
+	}
+	seen := make(map[uuid.UUID]int, len(distinct))
+	for i, id := range distinct {
+		if j, ok := seen[id]; ok {
+			t.Fatalf("ids %d and %d collide: %s", j, i, id)
+		}
+		seen[id] = i
+	}
+}
+
 func TestMustBaseEvent(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		e := domain.MustBaseEvent("user.created", "user-service")
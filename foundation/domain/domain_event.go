@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrInvalidEventPayload = errors.New("invalid event payload")
+
+// DomainEvent pairs BaseEvent metadata with a typed protobuf payload.
+type DomainEvent[T proto.Message] struct {
+	BaseEvent
+	Payload T
+}
+
+// NewDomainEvent creates BaseEvent via NewBaseEvent and attaches payload.
+func NewDomainEvent[T proto.Message](name, producer string, payload T) (DomainEvent[T], error) {
+	base, err := NewBaseEvent(name, producer)
+	if err != nil {
+		return DomainEvent[T]{}, err
+	}
+	return WrapDomainEvent(base, payload)
+}
+
+// WrapDomainEvent attaches payload to an existing base event.
+// The result is validated with DefaultEventLimits.
+func WrapDomainEvent[T proto.Message](base BaseEvent, payload T) (DomainEvent[T], error) {
+	e := DomainEvent[T]{BaseEvent: base, Payload: payload}
+	if err := e.ValidateWithLimits(DefaultEventLimits); err != nil {
+		return DomainEvent[T]{}, err
+	}
+	return e, nil
+}
+
+// Validate checks base event invariants and that payload is set.
+func (e DomainEvent[T]) Validate() error {
+	if err := e.BaseEvent.Validate(); err != nil {
+		return err
+	}
+	return e.validatePayload()
+}
+
+// ValidateWithLimits checks base event limits and that payload is set.
+func (e DomainEvent[T]) ValidateWithLimits(limits EventLimits) error {
+	if err := e.BaseEvent.ValidateWithLimits(limits); err != nil {
+		return err
+	}
+	return e.validatePayload()
+}
+
+// PayloadBytes returns the protobuf wire encoding of the payload.
+func (e DomainEvent[T]) PayloadBytes() ([]byte, error) {
+	if err := e.validatePayload(); err != nil {
+		return nil, err
+	}
+	b, err := proto.Marshal(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w: %w", ErrInvalidEvent, ErrInvalidEventPayload, err)
+	}
+	return b, nil
+}
+
+type domainEventJSON struct {
+	EventEnvelope
+	Payload []byte `json:"payload"`
+}
+
+// MarshalJSON encodes the envelope fields plus "payload" (base64 protobuf bytes).
+func (e DomainEvent[T]) MarshalJSON() ([]byte, error) {
+	env, err := e.Envelope()
+	if err != nil {
+		return nil, err
+	}
+	b, err := e.PayloadBytes()
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	return json.Marshal(domainEventJSON{EventEnvelope: env, Payload: b})
+}
+
+// UnmarshalJSON decodes the MarshalJSON form and validates the result.
+func (e *DomainEvent[T]) UnmarshalJSON(data []byte) error {
+	var raw domainEventJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	base, err := raw.EventEnvelope.Event()
+	if err != nil {
+		return err
+	}
+	payload, ok := newPayload[T]()
+	if !ok {
+		return fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventPayload)
+	}
+	if err := proto.Unmarshal(raw.Payload, payload); err != nil {
+		return fmt.Errorf("%w: %w: %w", ErrInvalidEvent, ErrInvalidEventPayload, err)
+	}
+	*e = DomainEvent[T]{BaseEvent: base, Payload: payload}
+	return nil
+}
+
+func (e DomainEvent[T]) validatePayload() error {
+	if isNilPayload(e.Payload) {
+		return fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventPayload)
+	}
+	return nil
+}
+
+func newPayload[T proto.Message]() (T, bool) {
+	var zero T
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Pointer {
+		return zero, false
+	}
+	p, ok := reflect.New(t.Elem()).Interface().(T)
+	return p, ok
+}
+
+func isNilPayload(m proto.Message) bool {
+	if m == nil {
+		return true
+	}
+	v := reflect.ValueOf(m)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
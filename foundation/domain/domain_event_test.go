@@ -0,0 +1,127 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vortex-fintech/go-lib/foundation/domain"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewDomainEvent_OK(t *testing.T) {
+	e, err := domain.NewDomainEvent("payment.completed", "payment-service", wrapperspb.String("tx-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Name != "payment.completed" || e.Producer != "payment-service" {
+		t.Fatalf("unexpected base event: %+v", e.BaseEvent)
+	}
+	if e.Payload.GetValue() != "tx-1" {
+		t.Fatalf("unexpected payload: %v", e.Payload)
+	}
+
+	var buf domain.EventBuffer
+	if err := buf.RecordStrict(e); err != nil {
+		t.Fatalf("RecordStrict: %v", err)
+	}
+}
+
+func TestNewDomainEvent_BaseValidationPropagates(t *testing.T) {
+	_, err := domain.NewDomainEvent("", "payment-service", wrapperspb.String("tx-1"))
+	if !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrInvalidEventName) {
+		t.Fatalf("expected invalid name, got %v", err)
+	}
+
+	_, err = domain.NewDomainEvent(strings.Repeat("n", 129), "payment-service", wrapperspb.String("tx-1"))
+	if !errors.Is(err, domain.ErrInvalidEventNameTooLong) {
+		t.Fatalf("expected name too long, got %v", err)
+	}
+}
+
+func TestWrapDomainEvent_BaseValidationPropagates(t *testing.T) {
+	base := envelopeEvent()
+	base.SchemaVersion = 0
+
+	_, err := domain.WrapDomainEvent(base, wrapperspb.Int64(10))
+	if !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrInvalidEventSchema) {
+		t.Fatalf("expected invalid schema, got %v", err)
+	}
+}
+
+func TestWrapDomainEvent_NilPayload(t *testing.T) {
+	_, err := domain.WrapDomainEvent[*wrapperspb.StringValue](envelopeEvent(), nil)
+	if !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrInvalidEventPayload) {
+		t.Fatalf("expected invalid payload, got %v", err)
+	}
+
+	var buf domain.EventBuffer
+	err = buf.RecordStrict(domain.DomainEvent[*wrapperspb.StringValue]{BaseEvent: envelopeEvent()})
+	if !errors.Is(err, domain.ErrInvalidEventPayload) {
+		t.Fatalf("expected RecordStrict to reject nil payload, got %v", err)
+	}
+}
+
+func TestDomainEvent_EnvelopeAndPayloadBytes(t *testing.T) {
+	e, err := domain.WrapDomainEvent(envelopeEvent(), wrapperspb.String("tx-1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env, err := e.Envelope()
+	if err != nil {
+		t.Fatalf("Envelope: %v", err)
+	}
+	want, _ := envelopeEvent().Envelope()
+	if env.ID != want.ID || env.OccurredAt != want.OccurredAt || env.Name != want.Name {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+
+	b, err := e.PayloadBytes()
+	if err != nil {
+		t.Fatalf("PayloadBytes: %v", err)
+	}
+	var got wrapperspb.StringValue
+	if err := proto.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal payload: %v", err)
+	}
+	if got.GetValue() != "tx-1" {
+		t.Fatalf("unexpected payload: %q", got.GetValue())
+	}
+}
+
+func TestDomainEvent_JSONRoundTrip(t *testing.T) {
+	e, err := domain.WrapDomainEvent(envelopeEvent(), wrapperspb.Int64(42))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("unmarshal map: %v", err)
+	}
+	if _, ok := fields["payload"]; !ok || fields["name"] != "payment.completed" {
+		t.Fatalf("unexpected json: %s", data)
+	}
+
+	var back domain.DomainEvent[*wrapperspb.Int64Value]
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if back.ID != e.ID || !back.At.Equal(e.At) || back.Payload.GetValue() != 42 {
+		t.Fatalf("round trip mismatch: %+v", back)
+	}
+}
+
+func TestDomainEvent_MarshalJSON_NilPayload(t *testing.T) {
+	e := domain.DomainEvent[*wrapperspb.StringValue]{BaseEvent: envelopeEvent()}
+	if _, err := json.Marshal(e); !errors.Is(err, domain.ErrInvalidEventPayload) {
+		t.Fatalf("expected invalid payload, got %v", err)
+	}
+}
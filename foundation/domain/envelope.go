@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidEventCausation = errors.New("invalid event causation id")
+
+// EventEnvelope is the canonical wire form of BaseEvent.
+type EventEnvelope struct {
+	Name          string            `json:"name"`
+	ID            string            `json:"id"`
+	OccurredAt    string            `json:"occurred_at"`
+	SchemaVersion int32             `json:"schema_version"`
+	Producer      string            `json:"producer"`
+	TraceID       string            `json:"trace_id,omitempty"`
+	CorrelationID string            `json:"correlation_id,omitempty"`
+	CausationID   string            `json:"causation_id,omitempty"`
+	Meta          map[string]string `json:"meta,omitempty"`
+}
+
+// Envelope validates the event with DefaultEventLimits and maps it to EventEnvelope.
+// OccurredAt is RFC3339 (nanosecond precision) in UTC.
+func (e BaseEvent) Envelope() (EventEnvelope, error) {
+	if err := e.ValidateWithLimits(DefaultEventLimits); err != nil {
+		return EventEnvelope{}, err
+	}
+
+	env := EventEnvelope{
+		Name:          e.Name,
+		ID:            e.ID.String(),
+		OccurredAt:    e.At.Format(time.RFC3339Nano),
+		SchemaVersion: e.SchemaVersion,
+		Producer:      e.Producer,
+		TraceID:       e.TraceID,
+		CorrelationID: e.CorrelationID,
+	}
+	if e.CausationID != uuid.Nil {
+		env.CausationID = e.CausationID.String()
+	}
+	if len(e.Meta) > 0 {
+		env.Meta = maps.Clone(e.Meta)
+	}
+	return env, nil
+}
+
+// Event reconstructs BaseEvent and validates it with DefaultEventLimits.
+// OccurredAt must carry a zero UTC offset.
+func (env EventEnvelope) Event() (BaseEvent, error) {
+	at, err := time.Parse(time.RFC3339Nano, env.OccurredAt)
+	if err != nil {
+		return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventTime)
+	}
+	if _, offset := at.Zone(); offset != 0 {
+		return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventTime)
+	}
+
+	id, err := uuid.Parse(env.ID)
+	if err != nil {
+		return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventID)
+	}
+
+	var causation uuid.UUID
+	if env.CausationID != "" {
+		if causation, err = uuid.Parse(env.CausationID); err != nil {
+			return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventCausation)
+		}
+	}
+
+	e := BaseEvent{
+		Name:          env.Name,
+		At:            at.UTC(),
+		ID:            id,
+		TraceID:       env.TraceID,
+		CorrelationID: env.CorrelationID,
+		CausationID:   causation,
+		SchemaVersion: env.SchemaVersion,
+		Producer:      env.Producer,
+	}
+	if len(env.Meta) > 0 {
+		e.Meta = maps.Clone(env.Meta)
+	}
+	if err := e.ValidateWithLimits(DefaultEventLimits); err != nil {
+		return BaseEvent{}, err
+	}
+	return e, nil
+}
+
+// ParseEnvelope decodes a JSON envelope and returns the validated BaseEvent.
+func ParseEnvelope(data []byte) (BaseEvent, error) {
+	var env EventEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+	return env.Event()
+}
@@ -0,0 +1,185 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vortex-fintech/go-lib/foundation/domain"
+)
+
+func envelopeEvent() domain.BaseEvent {
+	return domain.BaseEvent{
+		Name:          "payment.completed",
+		At:            time.Date(2025, 12, 13, 1, 2, 3, 456000000, time.UTC),
+		ID:            uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7"),
+		SchemaVersion: 2,
+		Producer:      "payment-service",
+	}.WithTrace("trace-1", "corr-1").
+		WithCausation(uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")).
+		WithMeta("tx_id", "tx-1").
+		WithMeta("amount", "10.00")
+}
+
+func TestBaseEvent_Envelope_Fields(t *testing.T) {
+	env, err := envelopeEvent().Envelope()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.OccurredAt != "2025-12-13T01:02:03.456Z" {
+		t.Fatalf("unexpected occurred_at: %q", env.OccurredAt)
+	}
+	if env.ID != "7c9e6679-7425-40de-944b-e07fc1f90ae7" || env.CausationID != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Fatalf("unexpected ids: %+v", env)
+	}
+	if env.SchemaVersion != 2 || env.Producer != "payment-service" || env.TraceID != "trace-1" || env.CorrelationID != "corr-1" {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+	if env.Meta["tx_id"] != "tx-1" || env.Meta["amount"] != "10.00" {
+		t.Fatalf("unexpected meta: %+v", env.Meta)
+	}
+}
+
+func TestBaseEvent_Envelope_InvalidEvent(t *testing.T) {
+	e := envelopeEvent()
+	e.At = e.At.In(time.FixedZone("MSK", 3*3600))
+
+	if _, err := e.Envelope(); !errors.Is(err, domain.ErrInvalidEventTime) {
+		t.Fatalf("expected ErrInvalidEventTime, got %v", err)
+	}
+}
+
+func TestBaseEvent_JSONRoundTrip(t *testing.T) {
+	in := envelopeEvent()
+
+	env, err := in.Envelope()
+	if err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	out, err := domain.ParseEnvelope(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !out.At.Equal(in.At) || out.At.Location() != time.UTC {
+		t.Fatalf("time mismatch or non-UTC location: %v", out.At)
+	}
+	if out.ID != in.ID || out.CausationID != in.CausationID || out.SchemaVersion != in.SchemaVersion {
+		t.Fatalf("id/schema mismatch: %+v", out)
+	}
+	if out.Name != in.Name || out.Producer != in.Producer || out.TraceID != in.TraceID || out.CorrelationID != in.CorrelationID {
+		t.Fatalf("field mismatch: %+v", out)
+	}
+	if len(out.Meta) != 2 || out.Meta["tx_id"] != "tx-1" || out.Meta["amount"] != "10.00" {
+		t.Fatalf("meta not preserved: %+v", out.Meta)
+	}
+}
+
+func TestBaseEvent_JSONRoundTrip_NoOptionalFields(t *testing.T) {
+	env, err := domain.MustBaseEvent("user.created", "user-service").Envelope()
+	if err != nil {
+		t.Fatalf("envelope: %v", err)
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, k := range []string{"causation_id", "trace_id", "correlation_id", "meta"} {
+		if _, ok := raw[k]; ok {
+			t.Fatalf("expected %q to be omitted", k)
+		}
+	}
+
+	out, err := domain.ParseEnvelope(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if out.CausationID != uuid.Nil || out.Meta != nil {
+		t.Fatalf("expected empty optional fields, got %+v", out)
+	}
+}
+
+func TestBaseEvent_EmbeddedKeepsDefaultJSON(t *testing.T) {
+	type walletCreated struct {
+		domain.BaseEvent
+		WalletID string
+	}
+	in := walletCreated{BaseEvent: envelopeEvent(), WalletID: "w-1"}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out walletCreated
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.WalletID != "w-1" || out.ID != in.ID {
+		t.Fatalf("embedding type lost fields: %s", data)
+	}
+}
+
+func TestParseEnvelope_EnforcesUTC(t *testing.T) {
+	env, err := envelopeEvent().Envelope()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	env.OccurredAt = "2025-12-13T04:02:03+03:00"
+	if _, err := env.Event(); !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrInvalidEventTime) {
+		t.Fatalf("expected non-UTC offset rejected, got %v", err)
+	}
+
+	env.OccurredAt = "2025-12-13T01:02:03+00:00"
+	e, err := env.Event()
+	if err != nil {
+		t.Fatalf("zero offset must be accepted: %v", err)
+	}
+	if e.At.Location() != time.UTC {
+		t.Fatalf("expected time.UTC location, got %v", e.At.Location())
+	}
+}
+
+func TestParseEnvelope_Invalid(t *testing.T) {
+	valid, err := envelopeEvent().Envelope()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*domain.EventEnvelope)
+		want   error
+	}{
+		{"bad time", func(e *domain.EventEnvelope) { e.OccurredAt = "yesterday" }, domain.ErrInvalidEventTime},
+		{"bad id", func(e *domain.EventEnvelope) { e.ID = "nope" }, domain.ErrInvalidEventID},
+		{"nil id", func(e *domain.EventEnvelope) { e.ID = uuid.Nil.String() }, domain.ErrInvalidEventID},
+		{"bad causation", func(e *domain.EventEnvelope) { e.CausationID = "nope" }, domain.ErrInvalidEventCausation},
+		{"empty name", func(e *domain.EventEnvelope) { e.Name = " " }, domain.ErrInvalidEventName},
+		{"zero schema", func(e *domain.EventEnvelope) { e.SchemaVersion = 0 }, domain.ErrInvalidEventSchema},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			env := valid
+			tc.mutate(&env)
+			data, _ := json.Marshal(env)
+			if _, err := domain.ParseEnvelope(data); !errors.Is(err, tc.want) || !errors.Is(err, domain.ErrInvalidEvent) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	if _, err := domain.ParseEnvelope([]byte("{")); !errors.Is(err, domain.ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent for malformed JSON, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"maps"
@@ -112,6 +113,35 @@ func NewBaseEvent(name, producer string) (BaseEvent, error) {
 	}, nil
 }
 
+// NewBaseEventWithID is NewBaseEvent with a caller-provided id (e.g. DeterministicEventID).
+func NewBaseEventWithID(name, producer string, id uuid.UUID) (BaseEvent, error) {
+	if id == uuid.Nil {
+		return BaseEvent{}, fmt.Errorf("%w: %w", ErrInvalidEvent, ErrInvalidEventID)
+	}
+	e, err := NewBaseEvent(name, producer)
+	if err != nil {
+		return BaseEvent{}, err
+	}
+	e.ID = id
+	return e, nil
+}
+
+// DeterministicEventID derives a UUIDv5 from namespace and business key parts,
+// so retries of the same operation reproduce the same event id.
+// Parts are length-prefixed: ("ab", "c") and ("a", "bc") yield different ids.
+func DeterministicEventID(namespace uuid.UUID, parts ...string) uuid.UUID {
+	n := 0
+	for _, p := range parts {
+		n += binary.MaxVarintLen64 + len(p)
+	}
+	buf := make([]byte, 0, n)
+	for _, p := range parts {
+		buf = binary.AppendUvarint(buf, uint64(len(p)))
+		buf = append(buf, p...)
+	}
+	return uuid.NewSHA1(namespace, buf)
+}
+
 // MustBaseEvent panics on constructor error.
 func MustBaseEvent(name, producer string) BaseEvent {
 	e, err := NewBaseEvent(name, producer)
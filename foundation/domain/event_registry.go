@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var ErrUnknownEventName = errors.New("unknown event name")
+
+// EventNameRegistry is an opt-in allowlist of event names, checked by BaseEvent.ValidateAgainst.
+// A name is known if it was registered exactly or matches the pattern of its domain
+// (the part before the first '.', e.g. "pii" for "pii.address.created").
+// It is safe for concurrent use.
+type EventNameRegistry struct {
+	mu       sync.RWMutex
+	names    map[string]struct{}
+	patterns map[string]*regexp.Regexp
+}
+
+// NewEventNameRegistry creates a registry with the given names; blank names are skipped.
+func NewEventNameRegistry(names ...string) *EventNameRegistry {
+	r := &EventNameRegistry{
+		names:    make(map[string]struct{}, len(names)),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	r.Register(names...)
+	return r
+}
+
+// Register adds exact names; blank names are skipped.
+func (r *EventNameRegistry) Register(names ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			r.names[n] = struct{}{}
+		}
+	}
+}
+
+// RegisterPattern allows every name of domain that fully matches pattern
+// (it is anchored, so "address\.(created|deleted)" does not match "address.created.v2").
+// The pattern applies to the whole name including the domain prefix. A second call for the
+// same domain replaces the pattern.
+func (r *EventNameRegistry) RegisterPattern(domain, pattern string) error {
+	domain = strings.TrimSpace(domain)
+	if domain == "" || strings.Contains(domain, ".") {
+		return fmt.Errorf("event name registry: invalid domain %q", domain)
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return fmt.Errorf("event name registry: domain %q: %w", domain, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns[domain] = re
+	return nil
+}
+
+// Known reports whether name (trimmed) is registered or matches its domain pattern.
+// A nil registry knows no names.
+func (r *EventNameRegistry) Known(name string) bool {
+	if r == nil {
+		return false
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.names[name]; ok {
+		return true
+	}
+	domain, _, ok := strings.Cut(name, ".")
+	if !ok {
+		return false
+	}
+	re, ok := r.patterns[domain]
+	return ok && re.MatchString(name)
+}
+
+// ValidateAgainst runs ValidateWithLimits(DefaultEventLimits) and then checks the name
+// against registry. Unknown names (and a nil registry) fail with ErrInvalidEvent wrapping
+// ErrUnknownEventName.
+func (e BaseEvent) ValidateAgainst(registry *EventNameRegistry) error {
+	if err := e.ValidateWithLimits(DefaultEventLimits); err != nil {
+		return err
+	}
+	if !registry.Known(e.Name) {
+		return fmt.Errorf("%w: %w: %q", ErrInvalidEvent, ErrUnknownEventName, strings.TrimSpace(e.Name))
+	}
+	return nil
+}
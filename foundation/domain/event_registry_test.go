@@ -0,0 +1,96 @@
+package domain_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/vortex-fintech/go-lib/foundation/domain"
+)
+
+func TestBaseEvent_ValidateAgainst_RegisteredName(t *testing.T) {
+	reg := domain.NewEventNameRegistry("pii.address.created", " payments.captured ")
+	e := domain.MustBaseEvent("pii.address.created", "pii-service")
+
+	if err := e.ValidateAgainst(reg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := domain.MustBaseEvent("payments.captured", "payments").ValidateAgainst(reg); err != nil {
+		t.Fatalf("trimmed registration must match: %v", err)
+	}
+}
+
+func TestBaseEvent_ValidateAgainst_UnknownName(t *testing.T) {
+	reg := domain.NewEventNameRegistry("pii.address.created")
+	e := domain.MustBaseEvent("pii.adress.created", "pii-service") // typo
+
+	err := e.ValidateAgainst(reg)
+	if !errors.Is(err, domain.ErrInvalidEvent) || !errors.Is(err, domain.ErrUnknownEventName) {
+		t.Fatalf("expected ErrInvalidEvent + ErrUnknownEventName, got %v", err)
+	}
+
+	if err := e.ValidateAgainst(nil); !errors.Is(err, domain.ErrUnknownEventName) {
+		t.Fatalf("nil registry must fail closed, got %v", err)
+	}
+
+	// Base invariants are checked first; Validate itself ignores the registry.
+	invalid := domain.BaseEvent{Name: "pii.adress.created"}
+	if err := invalid.ValidateAgainst(reg); errors.Is(err, domain.ErrUnknownEventName) || !errors.Is(err, domain.ErrInvalidEventProducer) {
+		t.Fatalf("expected producer error before name check, got %v", err)
+	}
+	if err := e.Validate(); err != nil {
+		t.Fatalf("Validate must not consult a registry: %v", err)
+	}
+}
+
+func TestEventNameRegistry_DomainPattern(t *testing.T) {
+	reg := domain.NewEventNameRegistry()
+	if err := reg.RegisterPattern("pii", `pii\.address\.(created|updated|deleted)`); err != nil {
+		t.Fatalf("RegisterPattern: %v", err)
+	}
+
+	cases := map[string]bool{
+		"pii.address.created":      true,
+		"pii.address.deleted":      true,
+		"pii.address.created.v2":   false, // anchored
+		"pii.adress.created":       false,
+		"payments.address.created": false, // other domain
+		"pii":                      false,
+		"":                         false,
+	}
+	for name, want := range cases {
+		if got := reg.Known(name); got != want {
+			t.Fatalf("Known(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if err := reg.RegisterPattern("pii", `(`); err == nil {
+		t.Fatal("expected error for invalid regex")
+	}
+	if err := reg.RegisterPattern("pii.address", `.*`); err == nil {
+		t.Fatal("expected error for dotted domain")
+	}
+	if err := reg.RegisterPattern(" ", `.*`); err == nil {
+		t.Fatal("expected error for blank domain")
+	}
+}
+
+func TestEventNameRegistry_ConcurrentUse(t *testing.T) {
+	reg := domain.NewEventNameRegistry("a.b")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			reg.Register("c.d")
+		}()
+		go func() {
+			defer wg.Done()
+			_ = reg.Known("a.b")
+		}()
+	}
+	wg.Wait()
+	if !reg.Known("c.d") {
+		t.Fatal("expected c.d to be registered")
+	}
+}
@@ -12,6 +12,7 @@ import (
 // - ErrorResponse / *ErrorResponse (direct passthrough)
 // - context.Canceled / context.DeadlineExceeded
 // - InvariantError (DomainInvariant/StateInvariant/TransitionInvariant)
+// - domainutil revision errors (see RevisionErrorToStatus)
 func ToErrorResponse(err error) ErrorResponse {
 	if err == nil {
 		return Internal().WithReason("unexpected_error")
@@ -34,6 +35,10 @@ func ToErrorResponse(err error) ErrorResponse {
 		return *ep
 	}
 
+	if resp, ok := revisionErrorResponse(err); ok {
+		return resp
+	}
+
 	var ie InvariantError
 	if !errors.As(err, &ie) {
 		return Internal().WithReason("unexpected_error")
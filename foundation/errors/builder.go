@@ -0,0 +1,53 @@
+package errors
+
+type DomainErrorBuilder struct {
+	violations []FieldViolation
+	seen       map[string]struct{}
+}
+
+func (b *DomainErrorBuilder) Add(field, reason string) *DomainErrorBuilder {
+	if b == nil {
+		return nil
+	}
+	if _, ok := b.seen[field]; ok {
+		return b
+	}
+	if b.seen == nil {
+		b.seen = map[string]struct{}{}
+	}
+	b.seen[field] = struct{}{}
+	b.violations = append(b.violations, FieldViolation{Field: field, Reason: reason})
+	return b
+}
+
+func (b *DomainErrorBuilder) AddIf(cond bool, field, reason string) *DomainErrorBuilder {
+	if !cond {
+		return b
+	}
+	return b.Add(field, reason)
+}
+
+func (b *DomainErrorBuilder) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.violations)
+}
+
+func (b *DomainErrorBuilder) Violations() []FieldViolation {
+	if b == nil || len(b.violations) == 0 {
+		return nil
+	}
+	return append([]FieldViolation(nil), b.violations...)
+}
+
+func (b *DomainErrorBuilder) Build() error {
+	if b.Len() == 0 {
+		return nil
+	}
+	details := make(map[string]string, len(b.violations))
+	for _, v := range b.violations {
+		details[v.Field] = v.Reason
+	}
+	return ValidationViolations(b.Violations()).WithDetails(details)
+}
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestDomainErrorBuilder_DedupeKeepsFirstReason(t *testing.T) {
+	var b DomainErrorBuilder
+	b.Add("email", "required").
+		Add("amount", "must_be_positive").
+		Add("email", "invalid_email")
+
+	v := b.Violations()
+	if len(v) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", v)
+	}
+	if v[0].Field != "email" || v[0].Reason != "required" {
+		t.Fatalf("expected first reason kept for email, got %+v", v[0])
+	}
+}
+
+func TestDomainErrorBuilder_PreservesInsertionOrder(t *testing.T) {
+	var b DomainErrorBuilder
+	fields := []string{"z", "a", "m", "b", "y"}
+	for _, f := range fields {
+		b.Add(f, "invalid")
+	}
+	b.Add("a", "dup")
+
+	for run := 0; run < 10; run++ {
+		v := b.Violations()
+		if len(v) != len(fields) {
+			t.Fatalf("expected %d violations, got %d", len(fields), len(v))
+		}
+		for i, f := range fields {
+			if v[i].Field != f {
+				t.Fatalf("position %d: want %q, got %q", i, f, v[i].Field)
+			}
+		}
+	}
+}
+
+func TestDomainErrorBuilder_AddIf(t *testing.T) {
+	var b DomainErrorBuilder
+	b.AddIf(false, "email", "required").
+		AddIf(true, "name", "required")
+
+	v := b.Violations()
+	if len(v) != 1 || v[0].Field != "name" {
+		t.Fatalf("expected only name violation, got %+v", v)
+	}
+}
+
+func TestDomainErrorBuilder_Build(t *testing.T) {
+	var empty DomainErrorBuilder
+	if err := empty.Build(); err != nil {
+		t.Fatalf("expected nil for empty builder, got %v", err)
+	}
+
+	var b DomainErrorBuilder
+	err := b.Add("email", "required").Add("amount", "must_be_positive").Build()
+	er := ToErrorResponse(err)
+	if er.Code != codes.InvalidArgument || er.Reason != "validation_failed" {
+		t.Fatalf("unexpected response: %+v", er)
+	}
+	if len(er.Violations) != 2 || er.Violations[0].Field != "email" || er.Violations[1].Field != "amount" {
+		t.Fatalf("unexpected violations order: %+v", er.Violations)
+	}
+	if er.Details["amount"] != "must_be_positive" {
+		t.Fatalf("expected details for amount, got %+v", er.Details)
+	}
+}
+
+func TestDomainErrorBuilder_ViolationsIsCopy(t *testing.T) {
+	var b DomainErrorBuilder
+	b.Add("email", "required")
+
+	v := b.Violations()
+	v[0].Reason = "mutated"
+	if b.Violations()[0].Reason != "required" {
+		t.Fatalf("Violations must return a copy")
+	}
+}
+
+func TestDomainErrorBuilder_NilSafe(t *testing.T) {
+	var b *DomainErrorBuilder
+	if b.Add("x", "y").AddIf(true, "a", "b").Len() != 0 {
+		t.Fatalf("nil builder must stay empty")
+	}
+	if b.Build() != nil || b.Violations() != nil {
+		t.Fatalf("nil builder must build nil")
+	}
+}
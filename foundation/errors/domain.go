@@ -63,6 +63,10 @@ func DomainInvariant(field, reason string) error {
 	return InvariantError{Kind: KindDomain, Field: field, Reason: reason}
 }
 
+func WrapDomainInvariant(base error, field, reason string) error {
+	return InvariantError{Kind: KindDomain, Base: base, Field: field, Reason: reason}
+}
+
 func StateInvariant(base error, field, reason string) error {
 	return InvariantError{Kind: KindState, Base: base, Field: field, Reason: reason}
 }
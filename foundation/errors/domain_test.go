@@ -116,3 +116,47 @@ func TestToErrorResponseFromWrappedDomain(t *testing.T) {
 		t.Fatalf("wrapped domain adaptation mismatch: %+v", out)
 	}
 }
+
+func TestWrapDomainInvariant_UnwrapsCause(t *testing.T) {
+	cause := errors.New("strconv.ParseInt: invalid syntax")
+	de := WrapDomainInvariant(cause, "amount", "not_a_number")
+
+	if de.Error() != DomainInvariant("amount", "not_a_number").Error() {
+		t.Fatalf("Error() must not include the cause, got %q", de.Error())
+	}
+	if !errors.Is(de, cause) {
+		t.Fatalf("errors.Is must find the wrapped cause")
+	}
+	if !IsInvariant(de) {
+		t.Fatalf("IsInvariant should return true")
+	}
+
+	wrapped := fmt.Errorf("parse request: %w", de)
+	if !errors.Is(wrapped, cause) {
+		t.Fatalf("errors.Is must find the cause through outer wrapping")
+	}
+	var ie InvariantError
+	if !errors.As(wrapped, &ie) || ie.Kind != KindDomain || ie.Field != "amount" {
+		t.Fatalf("errors.As must recover the domain invariant, got %+v", ie)
+	}
+}
+
+func TestWrapDomainInvariant_ToErrorResponse(t *testing.T) {
+	er := ToErrorResponse(WrapDomainInvariant(errors.New("bad"), "email", "invalid_email"))
+	if er.Code != codes.InvalidArgument || er.Details["email"] != "invalid_email" {
+		t.Fatalf("domain adaptation mismatch: %+v", er)
+	}
+	if len(er.Violations) != 1 || er.Violations[0].Field != "email" {
+		t.Fatalf("expected single email violation, got %+v", er.Violations)
+	}
+}
+
+func TestWrapDomainInvariant_NilCause(t *testing.T) {
+	de := WrapDomainInvariant(nil, "email", "invalid_email")
+	if de.Error() != "email: invalid_email" {
+		t.Fatalf("unexpected string: %s", de.Error())
+	}
+	if errors.Unwrap(de) != nil {
+		t.Fatalf("expected nil unwrap")
+	}
+}
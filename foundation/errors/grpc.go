@@ -12,6 +12,13 @@ import (
 const violationReasonMetadataPrefix = "_errors.violation_reason."
 
 func (e ErrorResponse) ToGRPC() error {
+	return e.ToGRPCStatus().Err()
+}
+
+func (e ErrorResponse) ToGRPCStatus() *status.Status {
+	if e.Code == codes.OK && len(e.Violations) > 0 {
+		e.Code = codes.InvalidArgument
+	}
 	st := status.New(e.Code, e.Message)
 
 	metadata := cloneDetails(e.Details)
@@ -57,7 +64,7 @@ func (e ErrorResponse) ToGRPC() error {
 		}
 	}
 
-	return st.Err()
+	return st
 }
 
 func FromGRPC(err error) ErrorResponse {
@@ -127,3 +127,76 @@ func TestToGRPCAndFromGRPC_PreservesViolationReason(t *testing.T) {
 		t.Fatalf("internal violation metadata must not leak to details: %+v", back.Details)
 	}
 }
+
+func TestToGRPCStatus_ValidationFieldViolations(t *testing.T) {
+	e := ValidationViolations([]FieldViolation{
+		{Field: "email", Reason: "invalid_email"},
+		{Field: "amount", Reason: "must_be_positive", Description: "amount must be > 0"},
+	})
+
+	st := e.ToGRPCStatus()
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+
+	var br *errdetails.BadRequest
+	for _, d := range st.Details() {
+		if x, ok := d.(*errdetails.BadRequest); ok {
+			br = x
+		}
+	}
+	if br == nil {
+		t.Fatalf("expected BadRequest detail")
+	}
+	want := []struct{ field, desc string }{
+		{"email", "invalid_email"},
+		{"amount", "amount must be > 0"},
+	}
+	if len(br.GetFieldViolations()) != len(want) {
+		t.Fatalf("expected %d violations, got %d", len(want), len(br.GetFieldViolations()))
+	}
+	for i, fv := range br.GetFieldViolations() {
+		if fv.GetField() != want[i].field || fv.GetDescription() != want[i].desc {
+			t.Fatalf("violation %d: got %s=%q", i, fv.GetField(), fv.GetDescription())
+		}
+	}
+}
+
+func TestToGRPCStatus_FromInvariant(t *testing.T) {
+	st := ToErrorResponse(DomainInvariant("email", "invalid_email")).ToGRPCStatus()
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+
+	st = ToErrorResponse(StateInvariant(nil, "status", "closed")).ToGRPCStatus()
+	if st.Code() != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", st.Code())
+	}
+
+	st = ToErrorResponse(status.Error(codes.Aborted, "boom")).ToGRPCStatus()
+	if st.Code() != codes.Internal {
+		t.Fatalf("expected Internal, got %v", st.Code())
+	}
+}
+
+func TestToGRPCStatus_MissingCodeWithViolations(t *testing.T) {
+	e := ErrorResponse{Message: "bad", Violations: []FieldViolation{{Field: "name", Reason: "required"}}}
+
+	st := e.ToGRPCStatus()
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st.Code())
+	}
+	if e.ToGRPC() == nil {
+		t.Fatalf("ToGRPC must not return nil for violations")
+	}
+}
+
+func TestToGRPCStatus_MatchesToGRPC(t *testing.T) {
+	e := Conflict("reference", "r-1").WithDomain("payments")
+
+	a, _ := status.FromError(e.ToGRPC())
+	b := e.ToGRPCStatus()
+	if a.Code() != b.Code() || a.Message() != b.Message() || len(a.Details()) != len(b.Details()) {
+		t.Fatalf("ToGRPC and ToGRPCStatus differ: %v vs %v", a, b)
+	}
+}
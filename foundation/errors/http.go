@@ -49,25 +49,22 @@ func HTTPStatus(code codes.Code) int {
 	}
 }
 
+// HTTPStatus returns the HTTP status for e.Code (see HTTPStatus).
+func (e ErrorResponse) HTTPStatus() int {
+	return HTTPStatus(e.Code)
+}
+
+// ToHTTP writes e as a JSON body (see MarshalJSON) with the mapped HTTP status.
 func (e ErrorResponse) ToHTTP(w http.ResponseWriter) {
-	status := HTTPStatus(e.Code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(struct {
-		Code       string            `json:"code"`
-		Reason     Reason            `json:"reason,omitempty"`
-		Domain     string            `json:"domain,omitempty"`
-		Message    string            `json:"message"`
-		Details    map[string]string `json:"details,omitempty"`
-		Violations []FieldViolation  `json:"violations,omitempty"`
-	}{
-		Code:       e.Code.String(),
-		Reason:     e.Reason,
-		Domain:     e.Domain,
-		Message:    e.Message,
-		Details:    e.Details,
-		Violations: e.Violations,
-	})
+	w.WriteHeader(e.HTTPStatus())
+	_ = json.NewEncoder(w).Encode(e)
+}
+
+// WriteError converts err with ToErrorResponse and writes it with ToHTTP.
+// Errors that are not ErrorResponse become a generic internal error, so their text never reaches the client.
+func WriteError(w http.ResponseWriter, err error) {
+	ToErrorResponse(err).ToHTTP(w)
 }
 
 // ToHTTPWithRetry sets Retry-After (seconds) and writes the error body.
@@ -2,6 +2,8 @@ package errors
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -97,3 +99,82 @@ func TestHTTPWithRetryHeader_NegativeClampedToZero(t *testing.T) {
 		t.Fatalf("body missing clamped retry_after_ms")
 	}
 }
+
+func TestWriteError_ValidationJSON(t *testing.T) {
+	e := ValidationFields(map[string]string{"email": "invalid_email", "amount": "must_be_positive"})
+	rec := httptest.NewRecorder()
+	WriteError(rec, e)
+
+	if rec.Code != 400 || e.HTTPStatus() != 400 {
+		t.Fatalf("status mismatch: recorder %d, method %d", rec.Code, e.HTTPStatus())
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	want := `{"code":"InvalidArgument","reason":"validation_failed","message":"Invalid argument",` +
+		`"details":{"amount":"must_be_positive","email":"invalid_email"},` +
+		`"fields":{"amount":"must_be_positive","email":"invalid_email"},` +
+		`"violations":[{"field":"amount","reason":"must_be_positive"},{"field":"email","reason":"invalid_email"}]}` + "\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("unexpected body:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestErrorResponse_JSONFieldsFromViolations(t *testing.T) {
+	e := InvalidArgument().WithViolations([]FieldViolation{
+		{Field: "email", Reason: "invalid_email"},
+		{Field: "email", Reason: "too_long"},
+		{Field: "amount", Reason: "must_be_positive"},
+	})
+	b, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !bytes.Contains(b, []byte(`"fields":{"amount":"must_be_positive","email":"invalid_email"}`)) {
+		t.Fatalf("unexpected fields in %s", b)
+	}
+
+	var out ErrorResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(out.Violations) != 3 || len(out.Details) != 0 {
+		t.Fatalf("fields must not leak into the decoded response: %+v", out)
+	}
+}
+
+func TestWriteError_InternalJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteError(rec, errors.New("pq: connection refused to 10.0.0.5"))
+
+	if rec.Code != 500 {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	want := `{"code":"Internal","reason":"unexpected_error","message":"Internal error"}` + "\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("unexpected body:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestErrorResponse_JSONRoundTrip(t *testing.T) {
+	in := NotFound().WithDomain("payments").WithDetail("payment_id", "p-1")
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(b) != in.ToString() {
+		t.Fatalf("MarshalJSON and ToString differ: %s vs %s", b, in.ToString())
+	}
+
+	var out ErrorResponse
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.Code != codes.NotFound || out.Reason != "not_found" || out.Domain != "payments" || out.Details["payment_id"] != "p-1" {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+
+	if err := json.Unmarshal([]byte(`{"code":"Teapot","message":"x"}`), &out); err == nil {
+		t.Fatal("expected error for unknown code")
+	}
+}
@@ -2,6 +2,8 @@ package errors
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 
 	"google.golang.org/grpc/codes"
 )
@@ -76,23 +78,79 @@ func (e ErrorResponse) WithViolations(v []FieldViolation) ErrorResponse {
 	return e
 }
 
-func (e ErrorResponse) ToString() string {
-	type out struct {
-		Code       string            `json:"code"`
-		Reason     Reason            `json:"reason,omitempty"`
-		Domain     string            `json:"domain,omitempty"`
-		Message    string            `json:"message"`
-		Details    map[string]string `json:"details,omitempty"`
-		Violations []FieldViolation  `json:"violations,omitempty"`
-	}
-	b, _ := json.Marshal(out{
+// errorJSON is the wire shape of ErrorResponse. Keep field names and order stable:
+// REST gateways and clients depend on them.
+type errorJSON struct {
+	Code       string            `json:"code"`
+	Reason     Reason            `json:"reason,omitempty"`
+	Domain     string            `json:"domain,omitempty"`
+	Message    string            `json:"message"`
+	Details    map[string]string `json:"details,omitempty"`
+	Fields     map[string]string `json:"fields,omitempty"`
+	Violations []FieldViolation  `json:"violations,omitempty"`
+}
+
+// MarshalJSON encodes the code by name (e.g. "InvalidArgument") instead of its number
+// and adds "fields", the violations keyed by field.
+func (e ErrorResponse) MarshalJSON() ([]byte, error) {
+	return json.Marshal(errorJSON{
 		Code:       e.Code.String(),
 		Reason:     e.Reason,
 		Domain:     e.Domain,
 		Message:    e.Message,
 		Details:    e.Details,
+		Fields:     violationFields(e.Violations),
 		Violations: e.Violations,
 	})
+}
+
+// violationFields maps each violated field to its reason; the first violation per field wins.
+func violationFields(v []FieldViolation) map[string]string {
+	if len(v) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(v))
+	for _, fv := range v {
+		if _, ok := out[fv.Field]; !ok {
+			out[fv.Field] = fv.Reason
+		}
+	}
+	return out
+}
+
+// UnmarshalJSON accepts the MarshalJSON shape; an unknown code name is an error.
+// "fields" is derived from "violations" and is not read back.
+func (e *ErrorResponse) UnmarshalJSON(b []byte) error {
+	var in errorJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	code, ok := codeFromName(in.Code)
+	if !ok {
+		return fmt.Errorf("errors: unknown code %q", in.Code)
+	}
+	*e = ErrorResponse{
+		Code:       code,
+		Reason:     in.Reason,
+		Domain:     in.Domain,
+		Message:    in.Message,
+		Details:    in.Details,
+		Violations: in.Violations,
+	}
+	return nil
+}
+
+func codeFromName(name string) (codes.Code, bool) {
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		if c.String() == name {
+			return c, true
+		}
+	}
+	return 0, false
+}
+
+func (e ErrorResponse) ToString() string {
+	b, _ := e.MarshalJSON()
 	return string(b)
 }
 
@@ -106,6 +164,8 @@ func ViolationsFromMap(m map[string]string) []FieldViolation {
 	for f, r := range m {
 		out = append(out, FieldViolation{Field: f, Reason: r})
 	}
+	// Sorted so the JSON body does not depend on map iteration order.
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
 	return out
 }
 
@@ -0,0 +1,67 @@
+package errors
+
+import (
+	"errors"
+	"strconv"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/vortex-fintech/go-lib/foundation/domainutil"
+)
+
+// Detail keys attached to revision errors.
+const (
+	DetailCurrentRevision  = "current_revision"
+	DetailExpectedRevision = "expected_revision"
+)
+
+// Field and reason used by FromRevisionConflict.
+const (
+	RevisionInvariantField = "revision"
+	RevisionConflictReason = "revision_conflict"
+)
+
+// FromRevisionConflict wraps a domainutil.RevisionConflictError (also when wrapped) into a
+// TransitionInvariant with RevisionInvariantField and RevisionConflictReason; err stays the Base,
+// so errors.Is(_, domainutil.ErrRevisionConflict) and ToErrorResponse (Aborted) keep working.
+// nil, other errors and errors that already are an InvariantError are returned unchanged.
+func FromRevisionConflict(err error) error {
+	if err == nil || IsInvariant(err) {
+		return err
+	}
+	var conflict *domainutil.RevisionConflictError
+	if !errors.As(err, &conflict) || conflict == nil {
+		return err
+	}
+	return TransitionInvariant(err, RevisionInvariantField, RevisionConflictReason)
+}
+
+// RevisionErrorToStatus maps domainutil.RequireRevision errors to gRPC status:
+// RevisionConflictError -> Aborted, InvalidExpectedRevisionError -> InvalidArgument.
+// Revisions are attached as ErrorInfo metadata. Returns nil for other errors.
+func RevisionErrorToStatus(err error) *status.Status {
+	resp, ok := revisionErrorResponse(err)
+	if !ok {
+		return nil
+	}
+	return resp.ToGRPCStatus()
+}
+
+func revisionErrorResponse(err error) (ErrorResponse, bool) {
+	var conflict *domainutil.RevisionConflictError
+	if errors.As(err, &conflict) && conflict != nil {
+		return Aborted().
+			WithReason("revision_conflict").
+			WithDetail(DetailCurrentRevision, strconv.FormatInt(conflict.Current, 10)).
+			WithDetail(DetailExpectedRevision, strconv.FormatInt(conflict.Expected, 10)), true
+	}
+
+	var invalid *domainutil.InvalidExpectedRevisionError
+	if errors.As(err, &invalid) && invalid != nil {
+		return InvalidArgument().
+			WithReason("invalid_expected_revision").
+			WithDetail(DetailExpectedRevision, strconv.FormatInt(invalid.Expected, 10)), true
+	}
+
+	return ErrorResponse{}, false
+}
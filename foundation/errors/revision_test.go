@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/vortex-fintech/go-lib/foundation/domainutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func revisionErrorInfo(t *testing.T, st *status.Status) *errdetails.ErrorInfo {
+	t.Helper()
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			return ei
+		}
+	}
+	t.Fatalf("ErrorInfo not found in %v", st.Details())
+	return nil
+}
+
+func TestRevisionErrorToStatus_Conflict(t *testing.T) {
+	err := fmt.Errorf("update wallet: %w", domainutil.RequireRevision(5, 4))
+
+	st := RevisionErrorToStatus(err)
+	if st == nil || st.Code() != codes.Aborted {
+		t.Fatalf("expected Aborted, got %v", st)
+	}
+	ei := revisionErrorInfo(t, st)
+	if ei.GetReason() != "revision_conflict" {
+		t.Fatalf("unexpected reason: %q", ei.GetReason())
+	}
+	md := ei.GetMetadata()
+	if md[DetailCurrentRevision] != "5" || md[DetailExpectedRevision] != "4" {
+		t.Fatalf("unexpected metadata: %v", md)
+	}
+}
+
+func TestRevisionErrorToStatus_InvalidExpected(t *testing.T) {
+	st := RevisionErrorToStatus(domainutil.RequireRevision(5, -1))
+	if st == nil || st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", st)
+	}
+	ei := revisionErrorInfo(t, st)
+	if ei.GetReason() != "invalid_expected_revision" {
+		t.Fatalf("unexpected reason: %q", ei.GetReason())
+	}
+	md := ei.GetMetadata()
+	if md[DetailExpectedRevision] != "-1" {
+		t.Fatalf("unexpected metadata: %v", md)
+	}
+	if _, ok := md[DetailCurrentRevision]; ok {
+		t.Fatalf("current revision must not be set: %v", md)
+	}
+}
+
+func TestRevisionErrorToStatus_OtherErrors(t *testing.T) {
+	if st := RevisionErrorToStatus(nil); st != nil {
+		t.Fatalf("expected nil for nil error, got %v", st)
+	}
+	if st := RevisionErrorToStatus(io.EOF); st != nil {
+		t.Fatalf("expected nil for unrelated error, got %v", st)
+	}
+	if st := RevisionErrorToStatus(domainutil.RequireRevision(5, 5)); st != nil {
+		t.Fatalf("expected nil for matching revision, got %v", st)
+	}
+}
+
+func TestToErrorResponse_RevisionErrors(t *testing.T) {
+	resp := ToErrorResponse(domainutil.RequireRevision(3, 2))
+	if resp.Code != codes.Aborted || resp.Details[DetailCurrentRevision] != "3" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	resp = ToErrorResponse(domainutil.RequireRevision(3, -2))
+	if resp.Code != codes.InvalidArgument || resp.Details[DetailExpectedRevision] != "-2" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestFromRevisionConflict(t *testing.T) {
+	base := fmt.Errorf("update wallet: %w", domainutil.RequireRevision(5, 4))
+
+	err := FromRevisionConflict(base)
+	if !IsInvariant(err) {
+		t.Fatalf("expected invariant, got %T %v", err, err)
+	}
+	var ie InvariantError
+	if !errors.As(err, &ie) || ie.Kind != KindTransition || ie.Field != RevisionInvariantField || ie.Reason != RevisionConflictReason {
+		t.Fatalf("unexpected invariant: %+v", ie)
+	}
+	if ie.Base != base {
+		t.Fatalf("base not preserved: %v", ie.Base)
+	}
+	if !errors.Is(err, domainutil.ErrRevisionConflict) {
+		t.Fatal("errors.Is must reach domainutil.ErrRevisionConflict")
+	}
+	var conflict *domainutil.RevisionConflictError
+	if !errors.As(err, &conflict) || conflict.Current != 5 || conflict.Expected != 4 {
+		t.Fatalf("errors.As must reach RevisionConflictError, got %+v", conflict)
+	}
+	if got := err.Error(); got != "transition: update wallet: revision conflict: current=5 expected=4: revision_conflict" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+
+	resp := ToErrorResponse(err)
+	if resp.Code != codes.Aborted || resp.Reason != "revision_conflict" || resp.Details[DetailCurrentRevision] != "5" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestFromRevisionConflict_PassThrough(t *testing.T) {
+	if err := FromRevisionConflict(nil); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := FromRevisionConflict(io.EOF); err != io.EOF {
+		t.Fatalf("expected unrelated error unchanged, got %v", err)
+	}
+	invalid := domainutil.RequireRevision(5, -1)
+	if err := FromRevisionConflict(invalid); err != invalid || IsInvariant(err) {
+		t.Fatalf("expected invalid expected revision unchanged, got %v", err)
+	}
+	already := TransitionInvariant(domainutil.RequireRevision(5, 4), "wallet", "custom")
+	if err := FromRevisionConflict(already); err != already {
+		t.Fatalf("expected existing invariant unchanged, got %v", err)
+	}
+}
@@ -20,74 +20,91 @@ func CanonicalizeStrict(s string, p CanonicalPolicy) (string, error) {
 	if p.MaxRunes <= 0 {
 		return "", ErrInvalidText
 	}
-
-	const maxUTF8BytesPerRune = 4
-	q, r := len(s)/maxUTF8BytesPerRune, len(s)%maxUTF8BytesPerRune
-	if q > p.MaxRunes || (q == p.MaxRunes && r > 0) {
+	if exceedsRuneBudget(len(s), p.MaxRunes) {
 		return "", ErrInvalidText
 	}
 
-	var b strings.Builder
+	c := canonicalizer{p: p}
 	if capBytes := p.MaxRunes * 4; capBytes > 0 && capBytes < len(s) {
-		b.Grow(capBytes)
+		c.b.Grow(capBytes)
 	} else if len(s) < p.MaxRunes*4 {
-		b.Grow(len(s))
+		c.b.Grow(len(s))
 	}
 
-	outRunes := 0
-	prevSpace := false
-
 	for i := 0; i < len(s); {
 		r, size := utf8.DecodeRuneInString(s[i:])
-		if r == utf8.RuneError && size == 1 {
-			return "", ErrInvalidText
-		}
 		i += size
-
-		isNewline := r == '\n' || r == '\r' || r == '\u0085' || r == '\u2028' || r == '\u2029'
-		if isNewline {
-			if !p.AllowNewlines {
-				return "", ErrInvalidText
-			}
-			b.WriteRune('\n')
-			outRunes++
-			if outRunes > p.MaxRunes {
-				return "", ErrInvalidText
-			}
-			prevSpace = false
-			continue
+		if err := c.add(r, size); err != nil {
+			return "", err
 		}
+	}
 
-		if unicode.IsControl(r) {
-			return "", ErrInvalidText
-		}
-		if !p.AllowFormatCF && unicode.In(r, unicode.Cf) {
-			return "", ErrInvalidText
-		}
+	return c.finish()
+}
+
+// exceedsRuneBudget reports whether n input bytes cannot fit into maxRunes runes
+// (n > maxRunes*4, computed without overflow).
+func exceedsRuneBudget(n, maxRunes int) bool {
+	const maxUTF8BytesPerRune = 4
+	q, r := n/maxUTF8BytesPerRune, n%maxUTF8BytesPerRune
+	return q > maxRunes || (q == maxRunes && r > 0)
+}
 
-		if unicode.IsSpace(r) {
-			if !prevSpace {
-				b.WriteByte(' ')
-				outRunes++
-				if outRunes > p.MaxRunes {
-					return "", ErrInvalidText
-				}
-				prevSpace = true
-			}
-			continue
+// canonicalizer applies CanonicalizeStrict rune by rune, so string and stream
+// inputs share the same rules.
+type canonicalizer struct {
+	p         CanonicalPolicy
+	b         strings.Builder
+	outRunes  int
+	prevSpace bool
+}
+
+func (c *canonicalizer) add(r rune, size int) error {
+	if r == utf8.RuneError && size == 1 {
+		return ErrInvalidText
+	}
+
+	isNewline := r == '\n' || r == '\r' || r == '\u0085' || r == '\u2028' || r == '\u2029'
+	if isNewline {
+		if !c.p.AllowNewlines {
+			return ErrInvalidText
 		}
+		c.prevSpace = false
+		return c.write('\n')
+	}
+
+	if unicode.IsControl(r) {
+		return ErrInvalidText
+	}
+	if !c.p.AllowFormatCF && unicode.In(r, unicode.Cf) {
+		return ErrInvalidText
+	}
 
-		prevSpace = false
-		b.WriteRune(r)
-		outRunes++
-		if outRunes > p.MaxRunes {
-			return "", ErrInvalidText
+	if unicode.IsSpace(r) {
+		if c.prevSpace {
+			return nil
 		}
+		c.prevSpace = true
+		return c.write(' ')
+	}
+
+	c.prevSpace = false
+	return c.write(r)
+}
+
+func (c *canonicalizer) write(r rune) error {
+	c.b.WriteRune(r)
+	c.outRunes++
+	if c.outRunes > c.p.MaxRunes {
+		return ErrInvalidText
 	}
+	return nil
+}
 
-	out := strings.TrimSpace(b.String())
+func (c *canonicalizer) finish() (string, error) {
+	out := strings.TrimSpace(c.b.String())
 	if out == "" {
-		if p.AllowEmpty {
+		if c.p.AllowEmpty {
 			return "", nil
 		}
 		return "", ErrInvalidText
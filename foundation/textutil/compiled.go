@@ -0,0 +1,247 @@
+package textutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// CompiledPolicy is a validated TextPolicy with precomputed lookup tables.
+// It is immutable and safe for concurrent use.
+type CompiledPolicy struct {
+	policy  TextPolicy
+	canon   CanonicalPolicy
+	charset *compiledCharset
+}
+
+type compiledCharset struct {
+	cs    AllowedCharset
+	ascii [utf8.RuneSelf]bool
+	extra map[rune]struct{}
+}
+
+// CompilePolicy validates p and precomputes its charset tables.
+// AllowedCharset is copied, so later changes to it do not affect the result.
+func CompilePolicy(p TextPolicy) (*CompiledPolicy, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &CompiledPolicy{
+		policy: p,
+		canon: CanonicalPolicy{
+			MaxRunes:      p.MaxRunes,
+			AllowEmpty:    p.AllowEmpty,
+			AllowFormatCF: false,
+			AllowNewlines: p.AllowNewlines,
+		},
+	}
+	if p.AllowedCharset != nil {
+		c.charset = compileCharset(*p.AllowedCharset)
+	}
+	return c, nil
+}
+
+func compileCharset(cs AllowedCharset) *compiledCharset {
+	cs.AllowedScripts = slices.Clone(cs.AllowedScripts)
+	out := &compiledCharset{cs: cs}
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		out.ascii[r] = isRuneAllowed(r, &out.cs)
+	}
+	for _, r := range cs.ExtraAllowed {
+		if r >= utf8.RuneSelf {
+			if out.extra == nil {
+				out.extra = make(map[rune]struct{})
+			}
+			out.extra[r] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Normalize is NormalizeText with the compiled policy.
+func (c *CompiledPolicy) Normalize(s string) (string, error) {
+	// Apply NFKC normalization first if requested
+	if c.policy.NormalizeNFKC {
+		s = norm.NFKC.String(s)
+	}
+
+	out, err := CanonicalizeStrict(s, c.canon)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.check(out); err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// NormalizeReader is NormalizeReader with the compiled policy.
+func (c *CompiledPolicy) NormalizeReader(r io.Reader) (string, error) {
+	if c.policy.NormalizeNFKC {
+		r = norm.NFKC.Reader(r)
+	}
+	br := bufio.NewReader(r)
+
+	cz := canonicalizer{p: c.canon}
+	read := 0
+	for {
+		ch, size, err := br.ReadRune()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("textutil: read input: %w", err)
+		}
+		read += size
+		if exceedsRuneBudget(read, c.canon.MaxRunes) {
+			return "", ErrInvalidText
+		}
+		if err := cz.add(ch, size); err != nil {
+			return "", err
+		}
+	}
+
+	out, err := cz.finish()
+	if err != nil {
+		return "", err
+	}
+	if err := c.check(out); err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+func (c *CompiledPolicy) check(out string) error {
+	p := c.policy
+	runes := utf8.RuneCountInString(out)
+	if runes < p.MinRunes {
+		return ErrInvalidText
+	}
+	if p.MaxBytes > 0 && len(out) > p.MaxBytes {
+		return ErrInvalidText
+	}
+
+	// Validate charset if specified
+	if c.charset != nil {
+		if err := c.charset.validate(out); err != nil {
+			return err
+		}
+	}
+
+	if p.Pattern != nil && !p.Pattern.MatchString(out) {
+		return ErrInvalidText
+	}
+
+	return nil
+}
+
+func (c *compiledCharset) validate(s string) error {
+	for _, r := range s {
+		if !c.allowed(r) {
+			return ErrInvalidText
+		}
+	}
+
+	// Check for mixed scripts if required
+	if c.cs.DisallowMixedScripts && len(c.cs.AllowedScripts) > 0 {
+		if err := checkMixedScripts(s, c.cs.AllowedScripts); err != nil {
+			return err
+		}
+	}
+
+	if c.cs.RejectConfusables && isLatinSpoof(s) {
+		return ErrInvalidText
+	}
+
+	return nil
+}
+
+// allowed matches isRuneAllowed using the precomputed tables.
+func (c *compiledCharset) allowed(r rune) bool {
+	if r >= 0 && r < utf8.RuneSelf {
+		return c.ascii[r]
+	}
+
+	if c.cs.AllowLetters && unicode.IsLetter(r) {
+		if len(c.cs.AllowedScripts) > 0 {
+			for _, script := range c.cs.AllowedScripts {
+				if unicode.Is(script, r) {
+					return true
+				}
+			}
+			return false
+		}
+		return true
+	}
+
+	if c.cs.AllowDigits && unicode.IsDigit(r) {
+		return true
+	}
+
+	_, ok := c.extra[r]
+	return ok
+}
+
+// matches reports whether c was compiled from the current contents of p.
+// The cache key holds the AllowedCharset pointer, so its fields are compared too.
+func (c *CompiledPolicy) matches(p TextPolicy) bool {
+	if p.AllowedCharset == nil {
+		return c.charset == nil
+	}
+	if c.charset == nil {
+		return false
+	}
+	a, b := &c.charset.cs, p.AllowedCharset
+	return a.AllowLetters == b.AllowLetters &&
+		a.AllowDigits == b.AllowDigits &&
+		a.AllowSpace == b.AllowSpace &&
+		a.ExtraAllowed == b.ExtraAllowed &&
+		a.DisallowMixedScripts == b.DisallowMixedScripts &&
+		a.RejectConfusables == b.RejectConfusables &&
+		slices.Equal(a.AllowedScripts, b.AllowedScripts)
+}
+
+// maxCachedPolicies bounds the NormalizeText cache; policies are expected to be
+// a small set of package-level values.
+const maxCachedPolicies = 128
+
+var (
+	policyCache    sync.Map // TextPolicy -> *CompiledPolicy
+	policyCacheLen atomic.Int64
+)
+
+func cachedPolicy(p TextPolicy) (*CompiledPolicy, error) {
+	v, found := policyCache.Load(p)
+	if found {
+		if c := v.(*CompiledPolicy); c.matches(p) {
+			return c, nil
+		}
+	}
+
+	c, err := CompilePolicy(p)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case found:
+		policyCache.Store(p, c)
+	case policyCacheLen.Load() < maxCachedPolicies:
+		if _, loaded := policyCache.LoadOrStore(p, c); !loaded {
+			policyCacheLen.Add(1)
+		}
+	}
+	return c, nil
+}
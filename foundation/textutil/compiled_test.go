@@ -0,0 +1,153 @@
+package textutil
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+var compiledTestPolicy = TextPolicy{
+	MinRunes:      1,
+	MaxRunes:      64,
+	MaxBytes:      256,
+	AllowNewlines: true,
+	AllowedCharset: &AllowedCharset{
+		AllowLetters:         true,
+		AllowDigits:          true,
+		AllowSpace:           true,
+		ExtraAllowed:         "'.-№",
+		AllowedScripts:       []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic},
+		DisallowMixedScripts: true,
+	},
+	Pattern: regexp.MustCompile(`^[^-]`),
+}
+
+var compiledTestInputs = []string{
+	"  Ana   Maria  ",
+	"O'Neil-Smith",
+	"Иван Петров",
+	"Ivan Петров",
+	"Дом №5",
+	"line1\nline2",
+	"-leading dash",
+	"tab\there",
+	"emoji 🙂",
+	"Ελένη",
+	"",
+	"   ",
+	strings.Repeat("a", 65),
+	"bad\xffutf8",
+}
+
+func TestCompiledPolicy_MatchesUncompiledNormalize(t *testing.T) {
+	c, err := CompilePolicy(compiledTestPolicy)
+	if err != nil {
+		t.Fatalf("CompilePolicy: %v", err)
+	}
+
+	for _, in := range compiledTestInputs {
+		got, gotErr := c.Normalize(in)
+		want, wantErr := referenceNormalize(in, compiledTestPolicy)
+		if got != want || !errors.Is(gotErr, wantErr) || (gotErr == nil) != (wantErr == nil) {
+			t.Fatalf("input %q: compiled = (%q, %v), reference = (%q, %v)", in, got, gotErr, want, wantErr)
+		}
+
+		got, gotErr = c.NormalizeReader(strings.NewReader(in))
+		if got != want || (gotErr == nil) != (wantErr == nil) {
+			t.Fatalf("input %q: compiled reader = (%q, %v), reference = (%q, %v)", in, got, gotErr, want, wantErr)
+		}
+	}
+}
+
+func TestCompilePolicy_InvalidPolicy(t *testing.T) {
+	for _, p := range []TextPolicy{
+		{MaxRunes: 0},
+		{MinRunes: 5, MaxRunes: 3},
+		{MinRunes: 1, MaxRunes: 3, AllowEmpty: true},
+		{MaxRunes: 3},
+		{MinRunes: 1, MaxRunes: 3, MaxBytes: -1},
+	} {
+		c, err := CompilePolicy(p)
+		if !errors.Is(err, ErrInvalidPolicy) || c != nil {
+			t.Fatalf("policy %+v: expected ErrInvalidPolicy, got (%v, %v)", p, c, err)
+		}
+	}
+}
+
+func TestCompilePolicy_CopiesCharset(t *testing.T) {
+	cs := &AllowedCharset{AllowLetters: true}
+	c, err := CompilePolicy(TextPolicy{MinRunes: 1, MaxRunes: 16, AllowedCharset: cs})
+	if err != nil {
+		t.Fatalf("CompilePolicy: %v", err)
+	}
+
+	cs.AllowDigits = true
+	if _, err := c.Normalize("abc1"); !errors.Is(err, ErrInvalidText) {
+		t.Fatalf("compiled policy must not see later charset changes, got %v", err)
+	}
+}
+
+func TestNormalizeText_CacheSeesCharsetChanges(t *testing.T) {
+	cs := &AllowedCharset{AllowLetters: true}
+	p := TextPolicy{MinRunes: 1, MaxRunes: 16, AllowedCharset: cs}
+
+	if _, err := NormalizeText("abc1", p); !errors.Is(err, ErrInvalidText) {
+		t.Fatalf("expected ErrInvalidText, got %v", err)
+	}
+	cs.AllowDigits = true
+	if out, err := NormalizeText("abc1", p); err != nil || out != "abc1" {
+		t.Fatalf("expected recompiled policy, got (%q, %v)", out, err)
+	}
+}
+
+func BenchmarkNormalizeText(b *testing.B) {
+	for b.Loop() {
+		_, _ = NormalizeText("  Ana   Maria O'Neil-Smith  ", compiledTestPolicy)
+	}
+}
+
+func BenchmarkCompiledPolicy_Normalize(b *testing.B) {
+	c, err := CompilePolicy(compiledTestPolicy)
+	if err != nil {
+		b.Fatalf("CompilePolicy: %v", err)
+	}
+	for b.Loop() {
+		_, _ = c.Normalize("  Ana   Maria O'Neil-Smith  ")
+	}
+}
+
+// referenceNormalize is the pre-compilation NormalizeText algorithm.
+func referenceNormalize(s string, p TextPolicy) (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	out, err := CanonicalizeStrict(s, CanonicalPolicy{
+		MaxRunes:      p.MaxRunes,
+		AllowEmpty:    p.AllowEmpty,
+		AllowNewlines: p.AllowNewlines,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len([]rune(out)) < p.MinRunes || (p.MaxBytes > 0 && len(out) > p.MaxBytes) {
+		return "", ErrInvalidText
+	}
+	if cs := p.AllowedCharset; cs != nil {
+		for _, r := range out {
+			if !isRuneAllowed(r, cs) {
+				return "", ErrInvalidText
+			}
+		}
+		if cs.DisallowMixedScripts && len(cs.AllowedScripts) > 0 {
+			if err := checkMixedScripts(out, cs.AllowedScripts); err != nil {
+				return "", err
+			}
+		}
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(out) {
+		return "", ErrInvalidText
+	}
+	return out, nil
+}
@@ -0,0 +1,37 @@
+package textutil
+
+import "unicode"
+
+// latinConfusables maps Cyrillic and Greek letters to the Latin letter they render
+// as in common fonts (subset of Unicode TR39 confusables.txt).
+var latinConfusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j', 'ӏ': 'l',
+	'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'ԝ': 'w', 'х': 'x', 'у': 'y',
+	'А': 'A', 'В': 'B', 'С': 'C', 'Е': 'E', 'Н': 'H', 'І': 'I', 'Ј': 'J', 'К': 'K',
+	'М': 'M', 'О': 'O', 'Р': 'P', 'Ѕ': 'S', 'Т': 'T', 'Х': 'X', 'У': 'Y', 'Ү': 'Y',
+	'Ԛ': 'Q', 'Ԝ': 'W', 'Ӏ': 'l',
+
+	// Greek
+	'α': 'a', 'ι': 'i', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'υ': 'u',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// isLatinSpoof reports whether s contains non-Latin letters that all have a Latin
+// look-alike, i.e. its confusable skeleton is a Latin-only string different from s.
+// This covers mixed-script spoofs ("Jоhn" with Cyrillic о) and whole-script ones
+// ("рау" written in Cyrillic), which DisallowMixedScripts does not catch.
+func isLatinSpoof(s string) bool {
+	spoofed := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) || unicode.Is(unicode.Latin, r) {
+			continue
+		}
+		if _, ok := latinConfusables[r]; !ok {
+			return false
+		}
+		spoofed = true
+	}
+	return spoofed
+}
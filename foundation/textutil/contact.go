@@ -0,0 +1,111 @@
+package textutil
+
+import "strings"
+
+// Limits from RFC 5321 (email) and ITU-T E.164 (phone).
+const (
+	maxEmailBytes      = 254
+	maxEmailLocalBytes = 64
+	maxEmailLabelBytes = 63
+	minPhoneE164Digits = 7
+	maxPhoneE164Digits = 15
+)
+
+// emailSpecials are the atext symbols (RFC 5322) allowed in the local part but not in the domain.
+const emailSpecials = "!#$%&'*+/=?^_`{|}~"
+
+var (
+	emailPolicy = mustCompilePolicy(TextPolicy{
+		MinRunes:      3,
+		MaxRunes:      maxEmailBytes,
+		MaxBytes:      maxEmailBytes,
+		NormalizeNFKC: true,
+		AllowedCharset: &AllowedCharset{
+			AllowLetters: true,
+			AllowDigits:  true,
+			ExtraAllowed: emailSpecials + ".-@",
+		},
+	})
+	phonePolicy = mustCompilePolicy(TextPolicy{
+		MinRunes:      1,
+		MaxRunes:      32,
+		NormalizeNFKC: true,
+		AllowedCharset: &AllowedCharset{
+			AllowDigits:  true,
+			AllowSpace:   true,
+			ExtraAllowed: "+-",
+		},
+	})
+)
+
+func mustCompilePolicy(p TextPolicy) *CompiledPolicy {
+	c, err := CompilePolicy(p)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// NormalizeEmail validates an address of the form local@domain and returns it with the
+// domain lowercased; the local part keeps its case. Input is NFKC-normalized and trimmed.
+// Spaces, control characters, quoted local parts and IP-literal domains are rejected.
+// The domain needs at least one dot. Returns ErrInvalidText on failure.
+func NormalizeEmail(s string) (string, error) {
+	out, err := emailPolicy.Normalize(s)
+	if err != nil {
+		return "", err
+	}
+
+	at := strings.IndexByte(out, '@')
+	if at <= 0 || at != strings.LastIndexByte(out, '@') {
+		return "", ErrInvalidText
+	}
+	local, domain := out[:at], strings.ToLower(out[at+1:])
+	if len(local) > maxEmailLocalBytes || !validDotAtom(local) || !validEmailDomain(domain) {
+		return "", ErrInvalidText
+	}
+	return local + "@" + domain, nil
+}
+
+// NormalizePhoneE164 returns a phone number as "+" followed by 7 to 15 ASCII digits.
+// Input is NFKC-normalized first, so full-width digits and "＋" are accepted; spaces and
+// dashes are removed. Returns ErrInvalidText on failure.
+func NormalizePhoneE164(s string) (string, error) {
+	out, err := phonePolicy.Normalize(s)
+	if err != nil {
+		return "", err
+	}
+
+	out = strings.NewReplacer(" ", "", "-", "").Replace(out)
+	body, ok := strings.CutPrefix(out, "+")
+	if !ok || len(body) < minPhoneE164Digits || len(body) > maxPhoneE164Digits {
+		return "", ErrInvalidText
+	}
+	// AllowDigits accepts any Unicode digit; E.164 needs ASCII, and country codes never start with 0.
+	if strings.Trim(body, "0123456789") != "" || body[0] == '0' {
+		return "", ErrInvalidText
+	}
+	return out, nil
+}
+
+// validDotAtom reports whether local is a dot-atom (RFC 5322): no leading, trailing or double dots.
+func validDotAtom(local string) bool {
+	return local != "" && local[0] != '.' && local[len(local)-1] != '.' && !strings.Contains(local, "..")
+}
+
+// validEmailDomain checks LDH labels separated by dots; letters may be non-ASCII (IDN).
+func validEmailDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" || len(l) > maxEmailLabelBytes || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		if strings.ContainsAny(l, emailSpecials) {
+			return false
+		}
+	}
+	return true
+}
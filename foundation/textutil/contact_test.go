@@ -0,0 +1,103 @@
+package textutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	valid := []struct{ in, want string }{
+		{"user@example.com", "user@example.com"},
+		{"  John.Doe@Example.COM  ", "John.Doe@example.com"},
+		{"o'neil+tag@sub.example.co.uk", "o'neil+tag@sub.example.co.uk"},
+		{"ｕｓｅｒ＠ｅｘａｍｐｌｅ．ｃｏｍ", "user@example.com"}, // full-width → NFKC
+		{"пользователь@пример.рф", "пользователь@пример.рф"},
+		{"a@b-c.io", "a@b-c.io"},
+		{strings.Repeat("a", 64) + "@example.com", strings.Repeat("a", 64) + "@example.com"},
+	}
+	for _, tc := range valid {
+		got, err := NormalizeEmail(tc.in)
+		if err != nil {
+			t.Fatalf("NormalizeEmail(%q): unexpected error %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Fatalf("NormalizeEmail(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"plainaddress",
+		"@example.com",
+		"user@",
+		"user@@example.com",
+		"a@b@example.com",
+		"user@localhost",
+		"user name@example.com",
+		"user@exa mple.com",
+		"user\x00@example.com",
+		"user​@example.com", // zero-width space (Cf)
+		"user@example.com\n",
+		".user@example.com",
+		"user.@example.com",
+		"us..er@example.com",
+		"user@example..com",
+		"user@-example.com",
+		"user@example-.com",
+		"user@exa_mple.com",
+		"user@[127.0.0.1]",
+		`"quoted"@example.com`,
+		strings.Repeat("a", 65) + "@example.com",
+		"user@" + strings.Repeat("a", 64) + ".com",
+		"user@" + strings.Repeat("abcdefghi.", 25) + "com", // > 254 bytes
+		"\xff@example.com",
+	}
+	for _, in := range invalid {
+		if got, err := NormalizeEmail(in); !errors.Is(err, ErrInvalidText) {
+			t.Fatalf("NormalizeEmail(%q) = %q, %v; want ErrInvalidText", in, got, err)
+		}
+	}
+}
+
+func TestNormalizePhoneE164(t *testing.T) {
+	valid := []struct{ in, want string }{
+		{"+14155552671", "+14155552671"},
+		{"+7 999 123-45-67", "+79991234567"},
+		{"  +44 20 7946 0958 ", "+442079460958"},
+		{"＋７ ９９９ １２３－４５－６７", "+79991234567"}, // full-width digits, plus and dash
+		{"+2901234", "+2901234"},
+		{"+123456789012345", "+123456789012345"},
+	}
+	for _, tc := range valid {
+		got, err := NormalizePhoneE164(tc.in)
+		if err != nil {
+			t.Fatalf("NormalizePhoneE164(%q): unexpected error %v", tc.in, err)
+		}
+		if got != tc.want {
+			t.Fatalf("NormalizePhoneE164(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"14155552671",       // no leading +
+		"8 999 123-45-67",   // national format
+		"+0123456789",       // country code cannot start with 0
+		"+123456",           // too short
+		"+1234567890123456", // too long
+		"+1 (415) 555-2671", // parentheses are not stripped
+		"+1.415.555.2671",   // dots are not stripped
+		"+1415555267a",      // letter
+		"+1415+5552671",     // second plus
+		"+٧٩٩٩١٢٣٤٥٦٧",      // Arabic-Indic digits are digits, but not ASCII after NFKC
+		"+1415\t5552671",    // control character
+		"+1415​5552671",     // zero-width space
+		"+" + strings.Repeat("1", 40),
+	}
+	for _, in := range invalid {
+		if got, err := NormalizePhoneE164(in); !errors.Is(err, ErrInvalidText) {
+			t.Fatalf("NormalizePhoneE164(%q) = %q, %v; want ErrInvalidText", in, got, err)
+		}
+	}
+}
@@ -2,11 +2,9 @@ package textutil
 
 import (
 	"errors"
+	"io"
 	"regexp"
 	"unicode"
-	"unicode/utf8"
-
-	"golang.org/x/text/unicode/norm"
 )
 
 var ErrInvalidPolicy = errors.New("invalid policy")
@@ -34,6 +32,10 @@ type AllowedCharset struct {
 
 	AllowedScripts       []*unicode.RangeTable
 	DisallowMixedScripts bool
+
+	// RejectConfusables rejects text whose non-Latin letters all have Latin
+	// look-alikes (e.g. Cyrillic "рау" for "pay"). Off by default.
+	RejectConfusables bool
 }
 
 func (p TextPolicy) Validate() error {
@@ -80,63 +82,25 @@ func ValidatePoliciesWithLimits(items ...PolicyWithLimit) error {
 }
 
 // NormalizeText validates and canonicalizes text according to the policy.
+// Compiled policies are cached, see CompilePolicy.
 func NormalizeText(s string, p TextPolicy) (string, error) {
-	if err := p.Validate(); err != nil {
-		return "", err
-	}
-
-	// Apply NFKC normalization first if requested
-	if p.NormalizeNFKC {
-		s = norm.NFKC.String(s)
-	}
-
-	out, err := CanonicalizeStrict(s, CanonicalPolicy{
-		MaxRunes:      p.MaxRunes,
-		AllowEmpty:    p.AllowEmpty,
-		AllowFormatCF: false,
-		AllowNewlines: p.AllowNewlines,
-	})
+	c, err := cachedPolicy(p)
 	if err != nil {
 		return "", err
 	}
-
-	runes := utf8.RuneCountInString(out)
-	if runes < p.MinRunes {
-		return "", ErrInvalidText
-	}
-	if p.MaxBytes > 0 && len(out) > p.MaxBytes {
-		return "", ErrInvalidText
-	}
-
-	// Validate charset if specified
-	if p.AllowedCharset != nil {
-		if err := validateCharset(out, p.AllowedCharset); err != nil {
-			return "", err
-		}
-	}
-
-	if p.Pattern != nil && !p.Pattern.MatchString(out) {
-		return "", ErrInvalidText
-	}
-
-	return out, nil
+	return c.Normalize(s)
 }
 
-func validateCharset(s string, cs *AllowedCharset) error {
-	for _, r := range s {
-		if !isRuneAllowed(r, cs) {
-			return ErrInvalidText
-		}
-	}
-
-	// Check for mixed scripts if required
-	if cs.DisallowMixedScripts && len(cs.AllowedScripts) > 0 {
-		if err := checkMixedScripts(s, cs.AllowedScripts); err != nil {
-			return err
-		}
+// NormalizeReader is NormalizeText for streamed input. It reads r incrementally and
+// fails with ErrInvalidText as soon as the (normalized) input exceeds the MaxRunes
+// budget, without reading the rest. Results and errors match NormalizeText on the
+// same input; read errors are returned wrapped.
+func NormalizeReader(r io.Reader, p TextPolicy) (string, error) {
+	c, err := cachedPolicy(p)
+	if err != nil {
+		return "", err
 	}
-
-	return nil
+	return c.NormalizeReader(r)
 }
 
 func isRuneAllowed(r rune, cs *AllowedCharset) bool {
@@ -2,8 +2,11 @@ package textutil
 
 import (
 	"errors"
+	"io"
 	"regexp"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"unicode"
 )
 
@@ -175,6 +178,44 @@ func TestNormalizeText_AllowedCharset_DisallowMixedScripts(t *testing.T) {
 	}
 }
 
+func TestNormalizeText_AllowedCharset_RejectConfusables(t *testing.T) {
+	cs := AllowedCharset{
+		AllowLetters:         true,
+		AllowSpace:           true,
+		AllowedScripts:       []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic},
+		DisallowMixedScripts: true,
+	}
+	policy := TextPolicy{MinRunes: 1, MaxRunes: 100, AllowedCharset: &cs}
+
+	// "рау" is all Cyrillic (U+0440 U+0430 U+0443) and renders like Latin "pay".
+	const spoof = "\u0440\u0430\u0443"
+	if _, err := NormalizeText(spoof, policy); err != nil {
+		t.Fatalf("single-script confusable must pass mixed-script check, got %v", err)
+	}
+
+	strict := cs
+	strict.RejectConfusables = true
+	policy.AllowedCharset = &strict
+
+	if _, err := NormalizeText(spoof, policy); !errors.Is(err, ErrInvalidText) {
+		t.Fatalf("expected ErrInvalidText for confusable, got %v", err)
+	}
+	for _, ok := range []string{"pay", "Привет", "Hello World"} {
+		if _, err := NormalizeText(ok, policy); err != nil {
+			t.Fatalf("unexpected error for %q: %v", ok, err)
+		}
+	}
+
+	// Without AllowedScripts the mixed-script check is skipped; confusables still apply.
+	loose := TextPolicy{MinRunes: 1, MaxRunes: 100, AllowedCharset: &AllowedCharset{
+		AllowLetters:      true,
+		RejectConfusables: true,
+	}}
+	if _, err := NormalizeText("J\u043ehn", loose); !errors.Is(err, ErrInvalidText) {
+		t.Fatalf("expected ErrInvalidText for mixed confusable, got %v", err)
+	}
+}
+
 func TestNormalizeText_EnforcesMinRunesAndBytes(t *testing.T) {
 	_, err := NormalizeText("ab", TextPolicy{MinRunes: 3, MaxRunes: 8, AllowEmpty: false})
 	if !errors.Is(err, ErrInvalidText) {
@@ -272,3 +313,86 @@ func TestValidatePoliciesWithLimits(t *testing.T) {
 		}
 	})
 }
+
+func TestNormalizeReader_MatchesNormalizeText(t *testing.T) {
+	base := TextPolicy{MinRunes: 1, MaxRunes: 16}
+	multiline := base
+	multiline.AllowNewlines = true
+	nfkc := base
+	nfkc.NormalizeNFKC = true
+	empty := TextPolicy{MaxRunes: 16, AllowEmpty: true}
+	bytesLimited := base
+	bytesLimited.MaxBytes = 4
+	latin := base
+	latin.AllowedCharset = &AllowedCharset{
+		AllowLetters:         true,
+		AllowSpace:           true,
+		AllowedScripts:       []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic},
+		DisallowMixedScripts: true,
+	}
+	pattern := base
+	pattern.Pattern = regexp.MustCompile(`^[a-z]+$`)
+
+	cases := []struct {
+		in string
+		p  TextPolicy
+	}{
+		{"  Ana   Maria  ", base},
+		{"line1\r\nline2", multiline},
+		{"line1\nline2", base},
+		{"Ｈｅｌｌｏ", nfkc},
+		{"   ", empty},
+		{"   ", base},
+		{"exactly-16-runes", base},
+		{"exactly-17-runes!", base},
+		{"абвгд", bytesLimited},
+		{"abcd", bytesLimited},
+		{"John Smith", latin},
+		{"Jоhn", latin},
+		{"abc", pattern},
+		{"ABC", pattern},
+		{"bad\x00byte", base},
+		{"bad\xffutf8", base},
+		{"zero\u200bwidth", base},
+		{"ok", TextPolicy{MaxRunes: 0}},
+	}
+
+	for _, tc := range cases {
+		want, wantErr := NormalizeText(tc.in, tc.p)
+		got, gotErr := NormalizeReader(strings.NewReader(tc.in), tc.p)
+		if got != want || !errors.Is(gotErr, wantErr) || (gotErr == nil) != (wantErr == nil) {
+			t.Fatalf("input %q: NormalizeReader = (%q, %v), NormalizeText = (%q, %v)", tc.in, got, gotErr, want, wantErr)
+		}
+	}
+}
+
+type endlessReader struct{ n int }
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	r.n += len(p)
+	return len(p), nil
+}
+
+func TestNormalizeReader_RejectsOversizedStreamEarly(t *testing.T) {
+	for _, nfkc := range []bool{false, true} {
+		src := &endlessReader{}
+		_, err := NormalizeReader(src, TextPolicy{MinRunes: 1, MaxRunes: 64, NormalizeNFKC: nfkc})
+		if !errors.Is(err, ErrInvalidText) {
+			t.Fatalf("nfkc=%v: expected ErrInvalidText, got %v", nfkc, err)
+		}
+		if src.n > 64*1024 {
+			t.Fatalf("nfkc=%v: read %d bytes before rejecting", nfkc, src.n)
+		}
+	}
+}
+
+func TestNormalizeReader_ReadError(t *testing.T) {
+	boom := errors.New("boom")
+	_, err := NormalizeReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(boom)), TextPolicy{MinRunes: 1, MaxRunes: 16})
+	if !errors.Is(err, boom) || errors.Is(err, ErrInvalidText) {
+		t.Fatalf("expected wrapped read error, got %v", err)
+	}
+}
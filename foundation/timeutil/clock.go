@@ -119,6 +119,99 @@ func (c *FrozenClock) Advance(d time.Duration) {
 	c.mu.Unlock()
 }
 
+// SteppableClock moves only when Advance is called. Unlike FrozenClock, Sleep
+// blocks until the clock is advanced past the deadline, so tests can drive
+// code that waits in another goroutine and measure elapsed time via Since.
+type SteppableClock struct {
+	mu       sync.Mutex
+	t        time.Time // always UTC
+	sleepers []*stepSleeper
+}
+
+type stepSleeper struct {
+	until time.Time
+	done  chan struct{}
+}
+
+func NewSteppableClock(start time.Time) *SteppableClock {
+	return &SteppableClock{t: start.UTC()}
+}
+
+func (c *SteppableClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *SteppableClock) Since(t time.Time) time.Duration { return c.Now().Sub(t) }
+
+// Sleep blocks until Advance moves the clock by at least d or ctx is done.
+func (c *SteppableClock) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			return nil
+		}
+	}
+
+	c.mu.Lock()
+	s := &stepSleeper{until: c.t.Add(d), done: make(chan struct{})}
+	c.sleepers = append(c.sleepers, s)
+	c.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		c.removeSleeperLocked(s)
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Advance moves time forward by d and wakes sleepers whose deadline is reached.
+// Non-positive d is a no-op: the clock never goes backwards.
+func (c *SteppableClock) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+
+	kept := c.sleepers[:0]
+	for _, s := range c.sleepers {
+		if c.t.Before(s.until) {
+			kept = append(kept, s)
+			continue
+		}
+		close(s.done)
+	}
+	clear(c.sleepers[len(kept):])
+	c.sleepers = kept
+}
+
+// Sleepers returns the number of goroutines blocked in Sleep.
+// Tests use it to wait until the code under test is parked before Advance.
+func (c *SteppableClock) Sleepers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sleepers)
+}
+
+func (c *SteppableClock) removeSleeperLocked(s *stepSleeper) {
+	for i, x := range c.sleepers {
+		if x == s {
+			c.sleepers = append(c.sleepers[:i], c.sleepers[i+1:]...)
+			return
+		}
+	}
+}
+
 // ===== Global helpers (thread-safe) =====
 
 var (
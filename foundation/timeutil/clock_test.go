@@ -165,3 +165,87 @@ func TestMonotonic_ResolvesBackwardsTime(t *testing.T) {
 		t.Fatalf("expected now (t2) when prev is zero, got %v", got)
 	}
 }
+
+func TestSteppableClock_AdvanceAndSince(t *testing.T) {
+	start := time.Date(2025, 12, 13, 1, 2, 3, 0, time.FixedZone("UTC+3", 3*60*60))
+	c := timeutil.NewSteppableClock(start)
+
+	t0 := c.Now()
+	if t0.Location() != time.UTC || !t0.Equal(start) {
+		t.Fatalf("expected UTC start, got %v", t0)
+	}
+	if got := c.Since(t0); got != 0 {
+		t.Fatalf("expected 0 elapsed, got %v", got)
+	}
+
+	c.Advance(1500 * time.Millisecond)
+	if got := c.Since(t0); got != 1500*time.Millisecond {
+		t.Fatalf("expected 1.5s elapsed, got %v", got)
+	}
+
+	c.Advance(-time.Hour)
+	c.Advance(0)
+	if got := c.Since(t0); got != 1500*time.Millisecond {
+		t.Fatalf("non-positive Advance must not move the clock, got %v", got)
+	}
+}
+
+func TestSteppableClock_SleepWaitsForAdvance(t *testing.T) {
+	c := timeutil.NewSteppableClock(time.Date(2025, 12, 13, 0, 0, 0, 0, time.UTC))
+	t0 := c.Now()
+
+	done := make(chan time.Duration, 1)
+	go func() {
+		if err := c.Sleep(context.Background(), 10*time.Second); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		done <- c.Since(t0)
+	}()
+
+	for c.Sleepers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Advance(9 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("Sleep returned before deadline")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	c.Advance(time.Second)
+	select {
+	case elapsed := <-done:
+		if elapsed != 10*time.Second {
+			t.Fatalf("expected 10s elapsed, got %v", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Sleep did not return after Advance")
+	}
+	if n := c.Sleepers(); n != 0 {
+		t.Fatalf("expected no sleepers, got %d", n)
+	}
+}
+
+func TestSteppableClock_SleepCancelled(t *testing.T) {
+	c := timeutil.NewSteppableClock(time.Date(2025, 12, 13, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- c.Sleep(ctx, time.Minute) }()
+
+	for c.Sleepers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-errCh; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := c.Sleepers(); n != 0 {
+		t.Fatalf("cancelled sleeper must be removed, got %d", n)
+	}
+	if err := c.Sleep(context.Background(), 0); err != nil {
+		t.Fatalf("expected nil for non-positive sleep, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package franzgo
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	kgo "github.com/twmb/franz-go/pkg/kgo"
@@ -10,6 +11,7 @@ import (
 
 type Client struct {
 	*kgo.Client
+	cfg Config
 }
 
 type Config struct {
@@ -19,6 +21,11 @@ type Config struct {
 	DisableAutoCommit  bool
 	AutoCommitMarks    bool
 	AutoCommitInterval time.Duration
+	DeadLetterTopic    string
+	ShouldDeadLetter   func(msg *Message, err error) bool
+
+	// TransactionalID enables Kafka transactions (see TransactionalProducer).
+	TransactionalID string
 }
 
 func DefaultConfig() Config {
@@ -37,6 +44,14 @@ func NewClient(cfg Config) (*Client, error) {
 		}
 	}
 
+	if cfg.ShouldDeadLetter != nil && cfg.DeadLetterTopic == "" {
+		return nil, errors.New("should dead letter requires dead letter topic")
+	}
+
+	if cfg.TransactionalID != "" && strings.TrimSpace(cfg.TransactionalID) == "" {
+		return nil, errors.New("transactional id must not be blank")
+	}
+
 	if len(cfg.SeedBrokers) == 0 {
 		cfg.SeedBrokers = []string{"localhost:9092"}
 	}
@@ -50,6 +65,10 @@ func NewClient(cfg Config) (*Client, error) {
 		kgo.AllowAutoTopicCreation(),
 	}
 
+	if cfg.TransactionalID != "" {
+		opts = append(opts, kgo.TransactionalID(cfg.TransactionalID))
+	}
+
 	if cfg.ConsumerGroup != "" {
 		opts = append(opts, kgo.ConsumerGroup(cfg.ConsumerGroup))
 
@@ -75,7 +94,7 @@ func NewClient(cfg Config) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{Client: client}, nil
+	return &Client{Client: client, cfg: cfg}, nil
 }
 
 func (c *Client) Close() {
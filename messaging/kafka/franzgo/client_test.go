@@ -169,6 +169,96 @@ func TestProducer_ProduceBatch_DoesNotMutateInputRecords(t *testing.T) {
 	}
 }
 
+func TestProducer_ProduceSync_Empty(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	producer := NewProducer(client, "test-topic")
+	results, err := producer.ProduceSync(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %d", len(results))
+	}
+}
+
+func TestProducer_ProduceSync_NilRecord(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	producer := NewProducer(client, "test-topic")
+	_, err = producer.ProduceSync(context.Background(), []*kgo.Record{nil})
+	if !errors.Is(err, ErrProducerRecordNil) {
+		t.Fatalf("expected ErrProducerRecordNil, got %v", err)
+	}
+}
+
+func TestProducer_ProduceSync_NilClient(t *testing.T) {
+	var producer *Producer
+	_, err := producer.ProduceSync(context.Background(), []*kgo.Record{{}})
+	if !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("expected ErrProducerClientNil, got %v", err)
+	}
+}
+
+func TestProducer_ProduceSync_CancelledContext(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	producer := NewProducer(client, "test-topic")
+	records := []*kgo.Record{
+		{Key: []byte("k1"), Value: []byte("v1")},
+		{Topic: "other-topic", Key: []byte("k2"), Value: []byte("v2")},
+		{Key: []byte("k3"), Value: []byte("v3")},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := producer.ProduceSync(ctx, records)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(results) != len(records) {
+		t.Fatalf("expected %d results, got %d", len(records), len(results))
+	}
+
+	wantTopics := []string{"test-topic", "other-topic", "test-topic"}
+	for i, res := range results {
+		if res.Topic != wantTopics[i] {
+			t.Fatalf("result %d: expected topic %q, got %q", i, wantTopics[i], res.Topic)
+		}
+		if !errors.Is(res.Err, context.Canceled) {
+			t.Fatalf("result %d: expected context.Canceled, got %v", i, res.Err)
+		}
+		if res.Partition != -1 || res.Offset != -1 {
+			t.Fatalf("result %d: expected unset partition/offset, got %d/%d", i, res.Partition, res.Offset)
+		}
+	}
+
+	if records[0].Topic != "" || records[2].Topic != "" {
+		t.Fatalf("expected input record topics to remain empty, got %q and %q", records[0].Topic, records[2].Topic)
+	}
+	if records[1].Topic != "other-topic" {
+		t.Fatalf("expected input record topic to stay 'other-topic', got %q", records[1].Topic)
+	}
+	for i, rec := range records {
+		if rec.Partition != 0 || rec.Offset != 0 {
+			t.Fatalf("record %d: expected input partition/offset untouched, got %d/%d", i, rec.Partition, rec.Offset)
+		}
+	}
+}
+
 func TestNewConsumer(t *testing.T) {
 	client, err := NewClient(Config{})
 	if err != nil {
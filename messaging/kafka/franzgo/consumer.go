@@ -4,14 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	kgo "github.com/twmb/franz-go/pkg/kgo"
 )
 
 var (
-	ErrConsumerClientNil  = errors.New("consumer client is nil")
-	ErrConsumerHandlerNil = errors.New("consumer handler is nil")
+	ErrConsumerClientNil      = errors.New("consumer client is nil")
+	ErrConsumerHandlerNil     = errors.New("consumer handler is nil")
+	ErrConsumerMessageNil     = errors.New("consumer message is nil")
+	ErrConsumerMessageUnknown = errors.New("consumer message was not fetched by a consumer")
+	ErrConsumerGroupRequired  = errors.New("offset commits require a consumer group")
+)
+
+const (
+	HeaderDeadLetterTopic     = "x-dlt-original-topic"
+	HeaderDeadLetterPartition = "x-dlt-original-partition"
+	HeaderDeadLetterOffset    = "x-dlt-original-offset"
+	HeaderDeadLetterError     = "x-dlt-error"
 )
 
 type Message struct {
@@ -22,13 +34,31 @@ type Message struct {
 	Value     []byte
 	Headers   []kgo.RecordHeader
 	Timestamp time.Time
+
+	record *kgo.Record
 }
 
 type HandlerFunc func(msg *Message)
 
+type ErrorHandlerFunc func(msg *Message) error
+
+type AckFunc func() error
+
+type AckHandlerFunc func(msg *Message, ack AckFunc)
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
 type Consumer struct {
 	client *Client
 	group  string
+
+	mu            sync.Mutex
+	pending       map[topicPartition]*kgo.Record
+	commitRecords func(ctx context.Context, records ...*kgo.Record) error
+	produce       func(ctx context.Context, record *kgo.Record) error
 }
 
 func NewConsumer(client *Client, group string) *Consumer {
@@ -39,10 +69,76 @@ func NewConsumer(client *Client, group string) *Consumer {
 }
 
 func (c *Consumer) Consume(ctx context.Context, topics []string, handler HandlerFunc) error {
+	if handler == nil {
+		return c.consume(ctx, topics, nil)
+	}
+	return c.consume(ctx, topics, func(msg *Message) error {
+		handler(msg)
+		return nil
+	})
+}
+
+func (c *Consumer) ConsumeWithAck(ctx context.Context, topics []string, handler AckHandlerFunc) error {
+	if handler == nil {
+		return c.consume(ctx, topics, nil)
+	}
+	return c.consume(ctx, topics, func(msg *Message) error {
+		handler(msg, func() error { return c.Mark(msg) })
+		return nil
+	})
+}
+
+func (c *Consumer) ConsumeWithError(ctx context.Context, topics []string, handler ErrorHandlerFunc) error {
+	if handler == nil {
+		return c.consume(ctx, topics, nil)
+	}
+
+	return c.consume(ctx, topics, func(msg *Message) error {
+		return c.process(ctx, msg, handler)
+	})
+}
+
+func (c *Consumer) process(ctx context.Context, msg *Message, handler ErrorHandlerFunc) error {
+	if err := handler(msg); err != nil {
+		if !c.shouldDeadLetter(msg, err) {
+			return fmt.Errorf("kafka handler failed for %s[%d]@%d: %w", msg.Topic, msg.Partition, msg.Offset, err)
+		}
+		if dltErr := c.deadLetter(ctx, msg, err); dltErr != nil {
+			return fmt.Errorf("kafka dead letter failed for %s[%d]@%d: %w", msg.Topic, msg.Partition, msg.Offset, errors.Join(err, dltErr))
+		}
+	}
+
+	if c.client.cfg.ConsumerGroup == "" {
+		return nil
+	}
+	return c.Mark(msg)
+}
+
+func (c *Consumer) shouldDeadLetter(msg *Message, err error) bool {
+	cfg := c.client.cfg
+	if cfg.DeadLetterTopic == "" {
+		return false
+	}
+	return cfg.ShouldDeadLetter == nil || cfg.ShouldDeadLetter(msg, err)
+}
+
+func (c *Consumer) deadLetter(ctx context.Context, msg *Message, cause error) error {
+	record := deadLetterRecord(c.client.cfg.DeadLetterTopic, msg, cause)
+
+	produce := c.produce
+	if produce == nil {
+		produce = func(ctx context.Context, record *kgo.Record) error {
+			return c.client.ProduceSync(ctx, record).FirstErr()
+		}
+	}
+	return produce(ctx, record)
+}
+
+func (c *Consumer) consume(ctx context.Context, topics []string, handle func(msg *Message) error) error {
 	if c == nil || c.client == nil || c.client.Client == nil {
 		return ErrConsumerClientNil
 	}
-	if handler == nil {
+	if handle == nil {
 		return ErrConsumerHandlerNil
 	}
 	if len(topics) == 0 {
@@ -70,21 +166,132 @@ func (c *Consumer) Consume(ctx context.Context, topics []string, handler Handler
 
 			iter := fetches.RecordIter()
 			for !iter.Done() {
-				record := iter.Next()
-				handler(&Message{
-					Topic:     record.Topic,
-					Partition: record.Partition,
-					Offset:    record.Offset,
-					Key:       record.Key,
-					Value:     record.Value,
-					Headers:   record.Headers,
-					Timestamp: record.Timestamp,
-				})
+				if err := handle(newMessage(iter.Next())); err != nil {
+					return err
+				}
 			}
 		}
 	}
 }
 
+func (c *Consumer) Mark(msg *Message) error {
+	if c == nil || c.client == nil || c.client.Client == nil {
+		return ErrConsumerClientNil
+	}
+	if c.client.cfg.ConsumerGroup == "" {
+		return ErrConsumerGroupRequired
+	}
+	if msg == nil {
+		return ErrConsumerMessageNil
+	}
+	if msg.record == nil {
+		return ErrConsumerMessageUnknown
+	}
+
+	switch {
+	case c.client.cfg.AutoCommitMarks:
+		c.client.MarkCommitRecords(msg.record)
+	case c.client.cfg.DisableAutoCommit:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.pending == nil {
+			c.pending = make(map[topicPartition]*kgo.Record)
+		}
+		tp := topicPartition{topic: msg.record.Topic, partition: msg.record.Partition}
+		if prev, ok := c.pending[tp]; !ok || prev.Offset < msg.record.Offset {
+			c.pending[tp] = msg.record
+		}
+	}
+	return nil
+}
+
+func (c *Consumer) Commit(ctx context.Context) error {
+	if c == nil || c.client == nil || c.client.Client == nil {
+		return ErrConsumerClientNil
+	}
+	if c.client.cfg.ConsumerGroup == "" {
+		return ErrConsumerGroupRequired
+	}
+	return c.commitPending(ctx)
+}
+
+func (c *Consumer) commitPending(ctx context.Context) error {
+	cfg := c.client.cfg
+	switch {
+	case cfg.ConsumerGroup == "":
+		return nil
+	case cfg.AutoCommitMarks:
+		return c.client.CommitMarkedOffsets(ctx)
+	case cfg.DisableAutoCommit:
+		return c.commitMarkedRecords(ctx)
+	default:
+		return c.client.CommitUncommittedOffsets(ctx)
+	}
+}
+
+func (c *Consumer) commitMarkedRecords(ctx context.Context) error {
+	c.mu.Lock()
+	records := make([]*kgo.Record, 0, len(c.pending))
+	for _, record := range c.pending {
+		records = append(records, record)
+	}
+	c.mu.Unlock()
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	commit := c.commitRecords
+	if commit == nil {
+		commit = c.client.CommitRecords
+	}
+	if err := commit(ctx, records...); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	for _, record := range records {
+		tp := topicPartition{topic: record.Topic, partition: record.Partition}
+		if c.pending[tp] == record {
+			delete(c.pending, tp)
+		}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
 func (c *Consumer) Group() string {
 	return c.group
 }
+
+func newMessage(record *kgo.Record) *Message {
+	return &Message{
+		Topic:     record.Topic,
+		Partition: record.Partition,
+		Offset:    record.Offset,
+		Key:       record.Key,
+		Value:     record.Value,
+		Headers:   record.Headers,
+		Timestamp: record.Timestamp,
+		record:    record,
+	}
+}
+
+func deadLetterRecord(topic string, msg *Message, cause error) *kgo.Record {
+	headers := make([]kgo.RecordHeader, 0, len(msg.Headers)+4)
+	headers = append(headers, msg.Headers...)
+	headers = append(headers,
+		kgo.RecordHeader{Key: HeaderDeadLetterTopic, Value: []byte(msg.Topic)},
+		kgo.RecordHeader{Key: HeaderDeadLetterPartition, Value: []byte(strconv.FormatInt(int64(msg.Partition), 10))},
+		kgo.RecordHeader{Key: HeaderDeadLetterOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		kgo.RecordHeader{Key: HeaderDeadLetterError, Value: []byte(cause.Error())},
+	)
+
+	return &kgo.Record{
+		Topic:   topic,
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	}
+}
@@ -0,0 +1,356 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kgo "github.com/twmb/franz-go/pkg/kgo"
+)
+
+func newManualCommitConsumer(t *testing.T) (*Consumer, *[][]*kgo.Record) {
+	t.Helper()
+
+	client, err := NewClient(Config{ConsumerGroup: "test-group", DisableAutoCommit: true})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	var commits [][]*kgo.Record
+	consumer := NewConsumer(client, "test-group")
+	consumer.commitRecords = func(_ context.Context, records ...*kgo.Record) error {
+		commits = append(commits, records)
+		return nil
+	}
+	return consumer, &commits
+}
+
+func TestConsumer_MarkThenCommit(t *testing.T) {
+	consumer, commits := newManualCommitConsumer(t)
+
+	first := newMessage(&kgo.Record{Topic: "payments", Partition: 0, Offset: 10})
+	second := newMessage(&kgo.Record{Topic: "payments", Partition: 0, Offset: 11})
+	stale := newMessage(&kgo.Record{Topic: "payments", Partition: 0, Offset: 9})
+
+	for _, msg := range []*Message{first, second, stale} {
+		if err := consumer.Mark(msg); err != nil {
+			t.Fatalf("expected no error from Mark, got %v", err)
+		}
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from Commit, got %v", err)
+	}
+	if len(*commits) != 1 {
+		t.Fatalf("expected 1 commit call, got %d", len(*commits))
+	}
+	got := (*commits)[0]
+	if len(got) != 1 || got[0].Offset != 11 {
+		t.Fatalf("expected highest marked offset 11 to be committed, got %+v", got)
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from empty Commit, got %v", err)
+	}
+	if len(*commits) != 1 {
+		t.Fatalf("expected no commit call without new marks, got %d", len(*commits))
+	}
+}
+
+func TestConsumer_Commit_NothingMarked(t *testing.T) {
+	consumer, commits := newManualCommitConsumer(t)
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(*commits) != 0 {
+		t.Fatalf("expected no commit calls, got %d", len(*commits))
+	}
+}
+
+func TestConsumer_Commit_FailureKeepsMarks(t *testing.T) {
+	consumer, commits := newManualCommitConsumer(t)
+	commitErr := errors.New("commit failed")
+	fail := true
+	consumer.commitRecords = func(_ context.Context, records ...*kgo.Record) error {
+		if fail {
+			return commitErr
+		}
+		*commits = append(*commits, records)
+		return nil
+	}
+
+	if err := consumer.Mark(newMessage(&kgo.Record{Topic: "payments", Partition: 1, Offset: 5})); err != nil {
+		t.Fatalf("expected no error from Mark, got %v", err)
+	}
+	if err := consumer.Commit(context.Background()); !errors.Is(err, commitErr) {
+		t.Fatalf("expected commit error, got %v", err)
+	}
+
+	fail = false
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error on retry, got %v", err)
+	}
+	if len(*commits) != 1 || (*commits)[0][0].Offset != 5 {
+		t.Fatalf("expected retry to commit offset 5, got %+v", *commits)
+	}
+}
+
+func TestConsumer_Mark_RequiresConsumerGroup(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	consumer := NewConsumer(client, "test-group")
+	msg := newMessage(&kgo.Record{Topic: "payments", Offset: 1})
+
+	if err := consumer.Mark(msg); !errors.Is(err, ErrConsumerGroupRequired) {
+		t.Fatalf("expected ErrConsumerGroupRequired from Mark, got %v", err)
+	}
+	if err := consumer.Commit(context.Background()); !errors.Is(err, ErrConsumerGroupRequired) {
+		t.Fatalf("expected ErrConsumerGroupRequired from Commit, got %v", err)
+	}
+}
+
+func TestConsumer_Mark_InvalidMessage(t *testing.T) {
+	consumer, _ := newManualCommitConsumer(t)
+
+	if err := consumer.Mark(nil); !errors.Is(err, ErrConsumerMessageNil) {
+		t.Fatalf("expected ErrConsumerMessageNil, got %v", err)
+	}
+	if err := consumer.Mark(&Message{Topic: "payments", Offset: 1}); !errors.Is(err, ErrConsumerMessageUnknown) {
+		t.Fatalf("expected ErrConsumerMessageUnknown, got %v", err)
+	}
+}
+
+func TestConsumer_Mark_NilClient(t *testing.T) {
+	var consumer *Consumer
+	if err := consumer.Mark(&Message{}); !errors.Is(err, ErrConsumerClientNil) {
+		t.Fatalf("expected ErrConsumerClientNil from Mark, got %v", err)
+	}
+	if err := consumer.Commit(context.Background()); !errors.Is(err, ErrConsumerClientNil) {
+		t.Fatalf("expected ErrConsumerClientNil from Commit, got %v", err)
+	}
+}
+
+func TestConsumer_ConsumeWithAck_NilHandler(t *testing.T) {
+	consumer, _ := newManualCommitConsumer(t)
+
+	err := consumer.ConsumeWithAck(context.Background(), []string{"topic"}, nil)
+	if !errors.Is(err, ErrConsumerHandlerNil) {
+		t.Fatalf("expected ErrConsumerHandlerNil, got %v", err)
+	}
+}
+
+func TestNewMessage_CopiesRecordFields(t *testing.T) {
+	record := &kgo.Record{
+		Topic:     "payments",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("k"),
+		Value:     []byte("v"),
+		Headers:   []kgo.RecordHeader{{Key: "h", Value: []byte("1")}},
+	}
+
+	msg := newMessage(record)
+	if msg.Topic != "payments" || msg.Partition != 2 || msg.Offset != 42 {
+		t.Fatalf("unexpected message position: %+v", msg)
+	}
+	if string(msg.Key) != "k" || string(msg.Value) != "v" || len(msg.Headers) != 1 {
+		t.Fatalf("unexpected message payload: %+v", msg)
+	}
+	if msg.record != record {
+		t.Fatal("expected message to keep its source record")
+	}
+}
+
+func newDeadLetterConsumer(t *testing.T, cfg Config) (*Consumer, *[]*kgo.Record, *[][]*kgo.Record) {
+	t.Helper()
+
+	cfg.ConsumerGroup = "test-group"
+	cfg.DisableAutoCommit = true
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	var produced []*kgo.Record
+	var commits [][]*kgo.Record
+	consumer := NewConsumer(client, "test-group")
+	consumer.produce = func(_ context.Context, record *kgo.Record) error {
+		produced = append(produced, record)
+		return nil
+	}
+	consumer.commitRecords = func(_ context.Context, records ...*kgo.Record) error {
+		commits = append(commits, records)
+		return nil
+	}
+	return consumer, &produced, &commits
+}
+
+func headerValue(headers []kgo.RecordHeader, key string) (string, bool) {
+	for _, h := range headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
+	}
+	return "", false
+}
+
+func TestConsumer_Process_DeadLettersFailingMessage(t *testing.T) {
+	consumer, produced, commits := newDeadLetterConsumer(t, Config{DeadLetterTopic: "payments.dlt"})
+
+	msg := newMessage(&kgo.Record{
+		Topic:     "payments",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("payment-1"),
+		Value:     []byte("poison"),
+		Headers:   []kgo.RecordHeader{{Key: "trace-id", Value: []byte("abc")}},
+	})
+
+	err := consumer.process(context.Background(), msg, func(_ *Message) error {
+		return errors.New("cannot decode payload")
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(*produced) != 1 {
+		t.Fatalf("expected 1 dead letter record, got %d", len(*produced))
+	}
+	dlt := (*produced)[0]
+	if dlt.Topic != "payments.dlt" {
+		t.Fatalf("expected topic 'payments.dlt', got %q", dlt.Topic)
+	}
+	if string(dlt.Key) != "payment-1" || string(dlt.Value) != "poison" {
+		t.Fatalf("expected original key/value, got %q/%q", dlt.Key, dlt.Value)
+	}
+
+	want := map[string]string{
+		"trace-id":                "abc",
+		HeaderDeadLetterTopic:     "payments",
+		HeaderDeadLetterPartition: "3",
+		HeaderDeadLetterOffset:    "42",
+		HeaderDeadLetterError:     "cannot decode payload",
+	}
+	for key, value := range want {
+		got, ok := headerValue(dlt.Headers, key)
+		if !ok {
+			t.Fatalf("expected header %q", key)
+		}
+		if got != value {
+			t.Fatalf("expected header %q=%q, got %q", key, value, got)
+		}
+	}
+	if len(msg.Headers) != 1 {
+		t.Fatalf("expected original message headers untouched, got %d", len(msg.Headers))
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from Commit, got %v", err)
+	}
+	if len(*commits) != 1 || (*commits)[0][0].Offset != 42 {
+		t.Fatalf("expected dead-lettered message to be committed, got %+v", *commits)
+	}
+}
+
+func TestConsumer_Process_PredicateRejectsDeadLetter(t *testing.T) {
+	retryable := errors.New("database unavailable")
+	consumer, produced, commits := newDeadLetterConsumer(t, Config{
+		DeadLetterTopic: "payments.dlt",
+		ShouldDeadLetter: func(_ *Message, err error) bool {
+			return !errors.Is(err, retryable)
+		},
+	})
+
+	msg := newMessage(&kgo.Record{Topic: "payments", Offset: 7})
+	err := consumer.process(context.Background(), msg, func(_ *Message) error { return retryable })
+	if !errors.Is(err, retryable) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if len(*produced) != 0 {
+		t.Fatalf("expected no dead letter record, got %d", len(*produced))
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from Commit, got %v", err)
+	}
+	if len(*commits) != 0 {
+		t.Fatalf("expected failed message to stay uncommitted, got %+v", *commits)
+	}
+}
+
+func TestConsumer_Process_WithoutDeadLetterTopic(t *testing.T) {
+	consumer, produced, _ := newDeadLetterConsumer(t, Config{})
+
+	handlerErr := errors.New("boom")
+	msg := newMessage(&kgo.Record{Topic: "payments", Offset: 1})
+	err := consumer.process(context.Background(), msg, func(_ *Message) error { return handlerErr })
+	if !errors.Is(err, handlerErr) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if len(*produced) != 0 {
+		t.Fatalf("expected no dead letter record, got %d", len(*produced))
+	}
+}
+
+func TestConsumer_Process_DeadLetterProduceFailure(t *testing.T) {
+	consumer, _, commits := newDeadLetterConsumer(t, Config{DeadLetterTopic: "payments.dlt"})
+	produceErr := errors.New("broker unavailable")
+	consumer.produce = func(context.Context, *kgo.Record) error { return produceErr }
+
+	msg := newMessage(&kgo.Record{Topic: "payments", Offset: 1})
+	err := consumer.process(context.Background(), msg, func(_ *Message) error { return errors.New("boom") })
+	if !errors.Is(err, produceErr) {
+		t.Fatalf("expected produce error, got %v", err)
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from Commit, got %v", err)
+	}
+	if len(*commits) != 0 {
+		t.Fatalf("expected message to stay uncommitted, got %+v", *commits)
+	}
+}
+
+func TestConsumer_Process_SuccessMarksMessage(t *testing.T) {
+	consumer, produced, commits := newDeadLetterConsumer(t, Config{DeadLetterTopic: "payments.dlt"})
+
+	msg := newMessage(&kgo.Record{Topic: "payments", Offset: 9})
+	if err := consumer.process(context.Background(), msg, func(_ *Message) error { return nil }); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(*produced) != 0 {
+		t.Fatalf("expected no dead letter record, got %d", len(*produced))
+	}
+
+	if err := consumer.Commit(context.Background()); err != nil {
+		t.Fatalf("expected no error from Commit, got %v", err)
+	}
+	if len(*commits) != 1 || (*commits)[0][0].Offset != 9 {
+		t.Fatalf("expected processed message to be committed, got %+v", *commits)
+	}
+}
+
+func TestConsumer_ConsumeWithError_NilHandler(t *testing.T) {
+	consumer, _, _ := newDeadLetterConsumer(t, Config{})
+
+	err := consumer.ConsumeWithError(context.Background(), []string{"topic"}, nil)
+	if !errors.Is(err, ErrConsumerHandlerNil) {
+		t.Fatalf("expected ErrConsumerHandlerNil, got %v", err)
+	}
+}
+
+func TestNewClient_ShouldDeadLetterRequiresTopic(t *testing.T) {
+	_, err := NewClient(Config{
+		ShouldDeadLetter: func(*Message, error) bool { return true },
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+}
@@ -4,10 +4,13 @@ package franzgo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"testing"
 	"time"
+
+	kgo "github.com/twmb/franz-go/pkg/kgo"
 )
 
 func integrationBroker() string {
@@ -90,3 +93,61 @@ func TestIntegration_ProduceAndConsume(t *testing.T) {
 		}
 	}
 }
+
+func TestIntegration_TransactionalProducer(t *testing.T) {
+	client, err := NewClient(Config{
+		SeedBrokers:     []string{integrationBroker()},
+		ClientID:        "integration-test",
+		TransactionalID: fmt.Sprintf("integration-test-tx-%d", time.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	producer, err := NewTransactionalProducer(client, "integration-test-topic")
+	if err != nil {
+		t.Fatalf("failed to create transactional producer: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := producer.Begin(ctx); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	if !producer.InTransaction() {
+		t.Fatal("expected InTransaction after Begin")
+	}
+	if err := producer.Begin(ctx); !errors.Is(err, ErrTransactionActive) {
+		t.Fatalf("second Begin: expected ErrTransactionActive, got %v", err)
+	}
+	if err := producer.ProduceBatch(ctx, nil); err != nil {
+		t.Fatalf("empty batch: expected no error, got %v", err)
+	}
+	if err := producer.ProduceBatch(ctx, []*kgo.Record{nil}); !errors.Is(err, ErrProducerRecordNil) {
+		t.Fatalf("expected ErrProducerRecordNil, got %v", err)
+	}
+	if err := producer.Produce(ctx, []byte("tx-key"), []byte("tx-value")); err != nil {
+		t.Fatalf("Produce: %v", err)
+	}
+	if err := producer.Commit(ctx); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if producer.InTransaction() {
+		t.Fatal("expected no transaction after Commit")
+	}
+
+	if err := producer.Begin(ctx); err != nil {
+		t.Fatalf("Begin after Commit: %v", err)
+	}
+	if err := producer.Produce(ctx, []byte("tx-key"), []byte("aborted")); err != nil {
+		t.Fatalf("Produce: %v", err)
+	}
+	if err := producer.Abort(ctx); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if producer.InTransaction() {
+		t.Fatal("expected no transaction after Abort")
+	}
+}
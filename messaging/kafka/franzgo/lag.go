@@ -0,0 +1,112 @@
+package franzgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Lag returns consumer lag per topic and partition: the high watermark minus the committed
+// offset of Config.ConsumerGroup. Only partitions with a committed offset are reported and
+// lag is never negative. It issues OffsetFetch and ListOffsets requests and returns when ctx is done.
+func (c *Consumer) Lag(ctx context.Context) (map[string]map[int32]int64, error) {
+	if c == nil || c.client == nil || c.client.Client == nil {
+		return nil, ErrConsumerClientNil
+	}
+	group := c.client.cfg.ConsumerGroup
+	if group == "" {
+		return nil, ErrConsumerGroupRequired
+	}
+
+	committed, err := c.fetchCommitted(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	if len(committed) == 0 {
+		return map[string]map[int32]int64{}, nil
+	}
+
+	ends, err := c.fetchHighWatermarks(ctx, committed)
+	if err != nil {
+		return nil, err
+	}
+	return groupLag(committed, ends), nil
+}
+
+func (c *Consumer) fetchCommitted(ctx context.Context, group string) (map[topicPartition]int64, error) {
+	req := kmsg.NewPtrOffsetFetchRequest()
+	req.Group = group
+	resp, err := req.RequestWith(ctx, c.client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("fetch committed offsets for group %q: %w", group, err)
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, fmt.Errorf("fetch committed offsets for group %q: %w", group, err)
+	}
+
+	committed := make(map[topicPartition]int64)
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return nil, fmt.Errorf("fetch committed offset for %s[%d]: %w", t.Topic, p.Partition, err)
+			}
+			if p.Offset >= 0 {
+				committed[topicPartition{topic: t.Topic, partition: p.Partition}] = p.Offset
+			}
+		}
+	}
+	return committed, nil
+}
+
+func (c *Consumer) fetchHighWatermarks(ctx context.Context, partitions map[topicPartition]int64) (map[topicPartition]int64, error) {
+	byTopic := make(map[string][]int32)
+	for tp := range partitions {
+		byTopic[tp.topic] = append(byTopic[tp.topic], tp.partition)
+	}
+
+	req := kmsg.NewPtrListOffsetsRequest()
+	for topic, parts := range byTopic {
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = topic
+		for _, partition := range parts {
+			rp := kmsg.NewListOffsetsRequestTopicPartition()
+			rp.Partition = partition
+			rp.Timestamp = -1 // latest
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+
+	resp, err := req.RequestWith(ctx, c.client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("list end offsets: %w", err)
+	}
+
+	ends := make(map[topicPartition]int64)
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return nil, fmt.Errorf("list end offset for %s[%d]: %w", t.Topic, p.Partition, err)
+			}
+			ends[topicPartition{topic: t.Topic, partition: p.Partition}] = p.Offset
+		}
+	}
+	return ends, nil
+}
+
+func groupLag(committed, ends map[topicPartition]int64) map[string]map[int32]int64 {
+	lag := make(map[string]map[int32]int64)
+	for tp, offset := range committed {
+		end, ok := ends[tp]
+		if !ok {
+			continue
+		}
+		if lag[tp.topic] == nil {
+			lag[tp.topic] = make(map[int32]int64)
+		}
+		lag[tp.topic][tp.partition] = max(end-offset, 0)
+	}
+	return lag
+}
@@ -0,0 +1,74 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroupLag(t *testing.T) {
+	committed := map[topicPartition]int64{
+		{topic: "payments", partition: 0}: 10,
+		{topic: "payments", partition: 1}: 50,
+		{topic: "refunds", partition: 0}:  7,
+		{topic: "refunds", partition: 1}:  3,
+	}
+	ends := map[topicPartition]int64{
+		{topic: "payments", partition: 0}: 25,
+		{topic: "payments", partition: 1}: 50,
+		{topic: "refunds", partition: 0}:  5, // truncated log: lag clamps to 0
+	}
+
+	got := groupLag(committed, ends)
+	want := map[string]map[int32]int64{
+		"payments": {0: 15, 1: 0},
+		"refunds":  {0: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected lag: got %v, want %v", got, want)
+	}
+}
+
+func TestConsumer_Lag_NilClient(t *testing.T) {
+	var c *Consumer
+	if _, err := c.Lag(context.Background()); !errors.Is(err, ErrConsumerClientNil) {
+		t.Fatalf("expected ErrConsumerClientNil, got %v", err)
+	}
+}
+
+func TestConsumer_Lag_RequiresGroup(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := NewConsumer(client, "").Lag(context.Background()); !errors.Is(err, ErrConsumerGroupRequired) {
+		t.Fatalf("expected ErrConsumerGroupRequired, got %v", err)
+	}
+}
+
+func TestConsumer_Lag_UnreachableBrokerHonoursContext(t *testing.T) {
+	client, err := NewClient(Config{
+		SeedBrokers:   []string{"127.0.0.1:1"},
+		ConsumerGroup: "lag-test",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	lag, err := NewConsumer(client, "lag-test").Lag(ctx)
+	if err == nil {
+		t.Fatalf("expected error on unreachable broker, got lag %v", lag)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Lag did not return promptly after ctx deadline: %v", elapsed)
+	}
+}
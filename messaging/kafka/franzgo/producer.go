@@ -3,6 +3,7 @@ package franzgo
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	kgo "github.com/twmb/franz-go/pkg/kgo"
 )
@@ -12,6 +13,13 @@ var (
 	ErrProducerRecordNil = errors.New("producer record is nil")
 )
 
+type ProduceResult struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Err       error
+}
+
 type Producer struct {
 	client *Client
 	topic  string
@@ -78,6 +86,55 @@ func (p *Producer) ProduceBatch(ctx context.Context, records []*kgo.Record) erro
 	return p.client.ProduceSync(ctx, batch...).FirstErr()
 }
 
+func (p *Producer) ProduceSync(ctx context.Context, records []*kgo.Record) ([]ProduceResult, error) {
+	if p == nil || p.client == nil || p.client.Client == nil {
+		return nil, ErrProducerClientNil
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	batch := make([]*kgo.Record, 0, len(records))
+	index := make(map[*kgo.Record]int, len(records))
+	for i, record := range records {
+		if record == nil {
+			return nil, ErrProducerRecordNil
+		}
+
+		copyRecord := *record
+		if copyRecord.Topic == "" {
+			copyRecord.Topic = p.topic
+		}
+		batch = append(batch, &copyRecord)
+		index[&copyRecord] = i
+	}
+
+	results := make([]ProduceResult, len(batch))
+	for i, record := range batch {
+		results[i] = ProduceResult{Topic: record.Topic, Partition: -1, Offset: -1}
+	}
+
+	for _, res := range p.client.ProduceSync(ctx, batch...) {
+		i, ok := index[res.Record]
+		if !ok {
+			continue
+		}
+		results[i].Err = res.Err
+		if res.Err == nil {
+			results[i].Partition = res.Record.Partition
+			results[i].Offset = res.Record.Offset
+		}
+	}
+
+	var errs []error
+	for i, res := range results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("record %d (%s): %w", i, res.Topic, res.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
 func (p *Producer) Topic() string {
 	return p.topic
 }
@@ -0,0 +1,140 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const defaultConsumerServerName = "franzgo-consumer"
+
+type ConsumerServer struct {
+	consumer *Consumer
+	topics   []string
+	handler  HandlerFunc
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopped  bool
+	stopOnce sync.Once
+}
+
+func NewConsumerServer(consumer *Consumer, topics []string, handler HandlerFunc) *ConsumerServer {
+	return &ConsumerServer{
+		consumer: consumer,
+		topics:   append([]string(nil), topics...),
+		handler:  handler,
+	}
+}
+
+func (s *ConsumerServer) Name() string {
+	if s == nil || s.consumer == nil || s.consumer.Group() == "" {
+		return defaultConsumerServerName
+	}
+	return s.consumer.Group()
+}
+
+func (s *ConsumerServer) Serve(ctx context.Context) error {
+	if s == nil || s.consumer == nil {
+		return ErrConsumerClientNil
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	if s.done != nil {
+		s.mu.Unlock()
+		return errors.New("consumer server is already serving")
+	}
+	done := make(chan struct{})
+	s.cancel = cancel
+	s.done = done
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.cancel = nil
+		s.done = nil
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	err := s.consumer.Consume(runCtx, s.topics, s.handler)
+	if errors.Is(err, context.Canceled) && s.isStopped() {
+		return nil
+	}
+	return err
+}
+
+func (s *ConsumerServer) GracefulStopWithTimeout(ctx context.Context) error {
+	if s == nil || s.consumer == nil || s.consumer.client == nil || s.consumer.client.Client == nil {
+		return ErrConsumerClientNil
+	}
+
+	done := s.stop()
+	if done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if err := s.consumer.commitPending(ctx); err != nil {
+		return err
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		s.closeClient()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *ConsumerServer) ForceStop() {
+	if s == nil || s.consumer == nil {
+		return
+	}
+	s.stop()
+	go s.closeClient()
+}
+
+func (s *ConsumerServer) stop() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return s.done
+}
+
+func (s *ConsumerServer) serving() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.done != nil
+}
+
+func (s *ConsumerServer) isStopped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopped
+}
+
+func (s *ConsumerServer) closeClient() {
+	s.stopOnce.Do(s.consumer.client.Close)
+}
@@ -0,0 +1,127 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestConsumerServer(t *testing.T, group string) *ConsumerServer {
+	t.Helper()
+
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	return NewConsumerServer(NewConsumer(client, group), []string{"test-topic"}, func(_ *Message) {})
+}
+
+func startServe(t *testing.T, ctx context.Context, srv *ConsumerServer) <-chan error {
+	t.Helper()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ctx) }()
+
+	deadline := time.Now().Add(time.Second)
+	for !srv.serving() {
+		if time.Now().After(deadline) {
+			t.Fatal("consumer server did not start serving")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return errCh
+}
+
+func waitServe(t *testing.T, errCh <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return")
+		return nil
+	}
+}
+
+func TestConsumerServer_Name(t *testing.T) {
+	if name := newTestConsumerServer(t, "payments").Name(); name != "payments" {
+		t.Fatalf("expected name 'payments', got %q", name)
+	}
+	if name := newTestConsumerServer(t, "").Name(); name != "franzgo-consumer" {
+		t.Fatalf("expected default name 'franzgo-consumer', got %q", name)
+	}
+
+	var nilServer *ConsumerServer
+	if name := nilServer.Name(); name != "franzgo-consumer" {
+		t.Fatalf("expected default name for nil server, got %q", name)
+	}
+}
+
+func TestConsumerServer_Serve_StopsOnContextCancel(t *testing.T) {
+	srv := newTestConsumerServer(t, "test-group")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := startServe(t, ctx, srv)
+	cancel()
+
+	if err := waitServe(t, errCh); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestConsumerServer_GracefulStop(t *testing.T) {
+	srv := newTestConsumerServer(t, "test-group")
+	errCh := startServe(t, context.Background(), srv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := srv.GracefulStopWithTimeout(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := waitServe(t, errCh); err != nil {
+		t.Fatalf("expected nil from Serve after graceful stop, got %v", err)
+	}
+}
+
+func TestConsumerServer_GracefulStopBeforeServe(t *testing.T) {
+	srv := newTestConsumerServer(t, "test-group")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := srv.GracefulStopWithTimeout(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := srv.Serve(context.Background()); err != nil {
+		t.Fatalf("expected nil from Serve after stop, got %v", err)
+	}
+}
+
+func TestConsumerServer_ForceStop(t *testing.T) {
+	srv := newTestConsumerServer(t, "test-group")
+	errCh := startServe(t, context.Background(), srv)
+
+	srv.ForceStop()
+	srv.ForceStop()
+
+	if err := waitServe(t, errCh); err != nil {
+		t.Fatalf("expected nil from Serve after force stop, got %v", err)
+	}
+}
+
+func TestConsumerServer_NilConsumer(t *testing.T) {
+	srv := NewConsumerServer(nil, nil, nil)
+
+	if err := srv.Serve(context.Background()); !errors.Is(err, ErrConsumerClientNil) {
+		t.Fatalf("expected ErrConsumerClientNil from Serve, got %v", err)
+	}
+	if err := srv.GracefulStopWithTimeout(context.Background()); !errors.Is(err, ErrConsumerClientNil) {
+		t.Fatalf("expected ErrConsumerClientNil from GracefulStopWithTimeout, got %v", err)
+	}
+	srv.ForceStop()
+}
@@ -0,0 +1,151 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	kgo "github.com/twmb/franz-go/pkg/kgo"
+)
+
+var (
+	ErrNotTransactional     = errors.New("client is not configured with a transactional id")
+	ErrTransactionActive    = errors.New("transaction already begun")
+	ErrTransactionNotActive = errors.New("no transaction in progress")
+)
+
+type TransactionalProducer struct {
+	client *Client
+	topic  string
+
+	mu     sync.Mutex
+	active bool
+}
+
+func NewTransactionalProducer(client *Client, topic string) (*TransactionalProducer, error) {
+	if client == nil || client.Client == nil {
+		return nil, ErrProducerClientNil
+	}
+	if client.cfg.TransactionalID == "" {
+		return nil, ErrNotTransactional
+	}
+	if topic == "" {
+		topic = "default-topic"
+	}
+	return &TransactionalProducer{client: client, topic: topic}, nil
+}
+
+func (p *TransactionalProducer) Begin(ctx context.Context) error {
+	if p == nil || p.client == nil || p.client.Client == nil {
+		return ErrProducerClientNil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.active {
+		return ErrTransactionActive
+	}
+	if err := p.client.BeginTransaction(); err != nil {
+		return err
+	}
+	p.active = true
+	return nil
+}
+
+func (p *TransactionalProducer) Produce(ctx context.Context, key, value []byte) error {
+	return p.ProduceBatch(ctx, []*kgo.Record{{Key: key, Value: value}})
+}
+
+func (p *TransactionalProducer) ProduceBatch(ctx context.Context, records []*kgo.Record) error {
+	if p == nil || p.client == nil || p.client.Client == nil {
+		return ErrProducerClientNil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return ErrTransactionNotActive
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	batch := make([]*kgo.Record, 0, len(records))
+	for _, record := range records {
+		if record == nil {
+			return ErrProducerRecordNil
+		}
+
+		copyRecord := *record
+		if copyRecord.Topic == "" {
+			copyRecord.Topic = p.topic
+		}
+		batch = append(batch, &copyRecord)
+	}
+
+	return p.client.ProduceSync(ctx, batch...).FirstErr()
+}
+
+func (p *TransactionalProducer) Commit(ctx context.Context) error {
+	if p == nil || p.client == nil || p.client.Client == nil {
+		return ErrProducerClientNil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return ErrTransactionNotActive
+	}
+	if err := p.client.Flush(ctx); err != nil {
+		return err
+	}
+	if err := p.client.EndTransaction(ctx, kgo.TryCommit); err != nil {
+		return err
+	}
+	p.active = false
+	return nil
+}
+
+func (p *TransactionalProducer) Abort(ctx context.Context) error {
+	if p == nil || p.client == nil || p.client.Client == nil {
+		return ErrProducerClientNil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return ErrTransactionNotActive
+	}
+	if err := p.client.AbortBufferedRecords(ctx); err != nil {
+		return err
+	}
+	if err := p.client.EndTransaction(ctx, kgo.TryAbort); err != nil {
+		return err
+	}
+	p.active = false
+	return nil
+}
+
+func (p *TransactionalProducer) InTransaction() bool {
+	if p == nil {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active
+}
+
+func (p *TransactionalProducer) Topic() string {
+	if p == nil {
+		return ""
+	}
+	return p.topic
+}
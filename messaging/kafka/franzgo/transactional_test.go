@@ -0,0 +1,107 @@
+package franzgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kgo "github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestNewClient_BlankTransactionalID(t *testing.T) {
+	_, err := NewClient(Config{TransactionalID: "   "})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+}
+
+func TestNewTransactionalProducer_RequiresTransactionalID(t *testing.T) {
+	client, err := NewClient(Config{})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := NewTransactionalProducer(client, "test-topic"); !errors.Is(err, ErrNotTransactional) {
+		t.Fatalf("expected ErrNotTransactional, got %v", err)
+	}
+}
+
+func TestNewTransactionalProducer_NilClient(t *testing.T) {
+	if _, err := NewTransactionalProducer(nil, "test-topic"); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("expected ErrProducerClientNil, got %v", err)
+	}
+}
+
+func newTestTransactionalProducer(t *testing.T) *TransactionalProducer {
+	t.Helper()
+	client, err := NewClient(Config{TransactionalID: "payments-tx"})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	t.Cleanup(client.Close)
+
+	producer, err := NewTransactionalProducer(client, "")
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if producer.Topic() != "default-topic" {
+		t.Fatalf("expected default topic, got %q", producer.Topic())
+	}
+	return producer
+}
+
+func TestTransactionalProducer_RequiresBegin(t *testing.T) {
+	producer := newTestTransactionalProducer(t)
+	ctx := context.Background()
+
+	if err := producer.Produce(ctx, []byte("k"), []byte("v")); !errors.Is(err, ErrTransactionNotActive) {
+		t.Fatalf("Produce: expected ErrTransactionNotActive, got %v", err)
+	}
+	if err := producer.ProduceBatch(ctx, []*kgo.Record{{Value: []byte("v")}}); !errors.Is(err, ErrTransactionNotActive) {
+		t.Fatalf("ProduceBatch: expected ErrTransactionNotActive, got %v", err)
+	}
+	if err := producer.Commit(ctx); !errors.Is(err, ErrTransactionNotActive) {
+		t.Fatalf("Commit: expected ErrTransactionNotActive, got %v", err)
+	}
+	if err := producer.Abort(ctx); !errors.Is(err, ErrTransactionNotActive) {
+		t.Fatalf("Abort: expected ErrTransactionNotActive, got %v", err)
+	}
+}
+
+func TestTransactionalProducer_NilReceiver(t *testing.T) {
+	var producer *TransactionalProducer
+	ctx := context.Background()
+
+	if err := producer.Begin(ctx); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("Begin: expected ErrProducerClientNil, got %v", err)
+	}
+	if err := producer.Produce(ctx, []byte("k"), []byte("v")); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("Produce: expected ErrProducerClientNil, got %v", err)
+	}
+	if err := producer.ProduceBatch(ctx, []*kgo.Record{{Value: []byte("v")}}); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("ProduceBatch: expected ErrProducerClientNil, got %v", err)
+	}
+	if err := producer.Commit(ctx); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("Commit: expected ErrProducerClientNil, got %v", err)
+	}
+	if err := producer.Abort(ctx); !errors.Is(err, ErrProducerClientNil) {
+		t.Fatalf("Abort: expected ErrProducerClientNil, got %v", err)
+	}
+	if producer.InTransaction() || producer.Topic() != "" {
+		t.Fatal("nil producer must report no transaction and no topic")
+	}
+}
+
+func TestTransactionalProducer_BeginCancelledContext(t *testing.T) {
+	producer := newTestTransactionalProducer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := producer.Begin(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if producer.InTransaction() {
+		t.Fatal("transaction must not be active after failed Begin")
+	}
+}
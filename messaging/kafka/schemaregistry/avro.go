@@ -0,0 +1,93 @@
+package schemaregistry
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrCodecFactoryRequired        = errors.New("avro codec factory is required")
+	ErrAvroRegistrationUnsupported = errors.New("registry client does not support avro schema registration")
+)
+
+// Codec encodes a value with a parsed Avro schema (e.g. a hamba/avro or goavro wrapper).
+type Codec interface {
+	Encode(value any) ([]byte, error)
+}
+
+// AvroRegistrar registers schemas with type AVRO. *Client implements it.
+type AvroRegistrar interface {
+	RegisterAvroSchema(subject, schema string) (int, error)
+}
+
+// AvroSerializer writes Avro payloads in Confluent wire format: magic byte and 4-byte
+// schema ID, without the protobuf message-index bytes.
+type AvroSerializer struct {
+	registry RegistryClient
+	codecFor func(schema string) (Codec, error)
+	cache    subjectCache
+}
+
+// NewAvroSerializer creates an Avro serializer. codecFor builds a Codec from schema text;
+// it is called once per registered schema and the codec is cached with the schema ID.
+func NewAvroSerializer(registry RegistryClient, codecFor func(schema string) (Codec, error)) *AvroSerializer {
+	return &AvroSerializer{registry: registry, codecFor: codecFor}
+}
+
+// Serialize encodes value using the cached schema for subject.
+// For first write, call SerializeWithSchema.
+func (s *AvroSerializer) Serialize(subject string, value any) ([]byte, int, error) {
+	if strings.TrimSpace(subject) == "" {
+		return nil, 0, ErrSubjectRequired
+	}
+
+	cached, ok := s.cache.get(subject)
+	if !ok {
+		return nil, 0, ErrSchemaNotCached
+	}
+	return encodeAvro(cached, value)
+}
+
+// SerializeWithSchema registers schema as AVRO (if needed), caches ID and codec and encodes value.
+// The registry must implement AvroRegistrar.
+func (s *AvroSerializer) SerializeWithSchema(subject, schema string, value any) ([]byte, int, error) {
+	if strings.TrimSpace(subject) == "" {
+		return nil, 0, ErrSubjectRequired
+	}
+
+	if cached, ok := s.cache.get(subject); ok && cached.schema == schema {
+		return encodeAvro(cached, value)
+	}
+
+	if strings.TrimSpace(schema) == "" {
+		return nil, 0, ErrSchemaRequired
+	}
+	if s.codecFor == nil {
+		return nil, 0, ErrCodecFactoryRequired
+	}
+	registrar, ok := s.registry.(AvroRegistrar)
+	if !ok {
+		return nil, 0, ErrAvroRegistrationUnsupported
+	}
+
+	codec, err := s.codecFor(schema)
+	if err != nil {
+		return nil, 0, err
+	}
+	schemaID, err := registrar.RegisterAvroSchema(subject, schema)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entry := subjectSchemaCache{id: schemaID, schema: schema, codec: codec}
+	s.cache.put(subject, entry)
+	return encodeAvro(entry, value)
+}
+
+func encodeAvro(entry subjectSchemaCache, value any) ([]byte, int, error) {
+	data, err := entry.codec.Encode(value)
+	if err != nil {
+		return nil, 0, err
+	}
+	return createWireFormat(data, entry.id, nil), entry.id, nil
+}
@@ -0,0 +1,156 @@
+package schemaregistry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const testAvroSchema = `{"type":"record","name":"Payment","fields":[{"name":"id","type":"string"}]}`
+
+type stubAvroCodec struct {
+	schema string
+}
+
+func (c stubAvroCodec) Encode(value any) ([]byte, error) {
+	if value == nil {
+		return nil, errors.New("stub codec: nil value")
+	}
+	return []byte(fmt.Sprint(value)), nil
+}
+
+type avroMockRegistry struct {
+	*mockRegistry
+	avroCalls []registerCall
+	avroErr   error
+}
+
+func newAvroMockRegistry() *avroMockRegistry {
+	return &avroMockRegistry{mockRegistry: &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}}
+}
+
+func (m *avroMockRegistry) RegisterAvroSchema(subject, schema string) (int, error) {
+	m.avroCalls = append(m.avroCalls, registerCall{subject: subject, schema: schema})
+	if m.avroErr != nil {
+		return 0, m.avroErr
+	}
+	return m.RegisterSchema(subject, schema)
+}
+
+func TestAvroSerializer_WireHeaderWithoutMessageIndex(t *testing.T) {
+	registry := newAvroMockRegistry()
+	registry.ids["other-value"] = 6
+	registry.schemas["other-value"] = "x"
+
+	var codecCalls int
+	serializer := NewAvroSerializer(registry, func(schema string) (Codec, error) {
+		codecCalls++
+		return stubAvroCodec{schema: schema}, nil
+	})
+
+	encoded, id, err := serializer.SerializeWithSchema("payments-value", testAvroSchema, "pay-1")
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+	if id != 2 {
+		t.Fatalf("expected schema ID 2, got %d", id)
+	}
+
+	want := append([]byte{0, 0, 0, 0, 2}, []byte("pay-1")...)
+	if !bytes.Equal(encoded, want) {
+		t.Fatalf("unexpected wire format: got %v, want %v", encoded, want)
+	}
+	if codecCalls != 1 {
+		t.Fatalf("expected 1 codec build, got %d", codecCalls)
+	}
+	if len(registry.avroCalls) != 1 || registry.avroCalls[0].schema != testAvroSchema {
+		t.Fatalf("expected 1 RegisterAvroSchema call, got %+v", registry.avroCalls)
+	}
+}
+
+func TestAvroSerializer_CachesSchemaIDPerSubject(t *testing.T) {
+	registry := newAvroMockRegistry()
+	var codecCalls int
+	serializer := NewAvroSerializer(registry, func(schema string) (Codec, error) {
+		codecCalls++
+		return stubAvroCodec{schema: schema}, nil
+	})
+
+	if _, _, err := serializer.SerializeWithSchema("payments-value", testAvroSchema, "pay-1"); err != nil {
+		t.Fatalf("first serialize failed: %v", err)
+	}
+	if _, _, err := serializer.SerializeWithSchema("payments-value", testAvroSchema, "pay-2"); err != nil {
+		t.Fatalf("second serialize failed: %v", err)
+	}
+	encoded, id, err := serializer.Serialize("payments-value", "pay-3")
+	if err != nil {
+		t.Fatalf("cached serialize failed: %v", err)
+	}
+	if id != 1 {
+		t.Fatalf("expected cached ID 1, got %d", id)
+	}
+	if !bytes.Equal(encoded[5:], []byte("pay-3")) {
+		t.Fatalf("unexpected payload %q", encoded[5:])
+	}
+
+	if len(registry.avroCalls) != 1 {
+		t.Fatalf("expected 1 RegisterAvroSchema call, got %d", len(registry.avroCalls))
+	}
+	if codecCalls != 1 {
+		t.Fatalf("expected 1 codec build, got %d", codecCalls)
+	}
+	if len(registry.registerWithRefsCalls) != 0 {
+		t.Fatalf("avro serializer must not register protobuf schemas, got %d calls", len(registry.registerWithRefsCalls))
+	}
+
+	_, id2, err := serializer.SerializeWithSchema("refunds-value", testAvroSchema, "ref-1")
+	if err != nil {
+		t.Fatalf("second subject serialize failed: %v", err)
+	}
+	if id2 != 2 {
+		t.Fatalf("expected new ID 2 for second subject, got %d", id2)
+	}
+}
+
+func TestAvroSerializer_Errors(t *testing.T) {
+	codecFor := func(schema string) (Codec, error) { return stubAvroCodec{schema: schema}, nil }
+
+	serializer := NewAvroSerializer(newAvroMockRegistry(), codecFor)
+	if _, _, err := serializer.Serialize("payments-value", "pay-1"); !errors.Is(err, ErrSchemaNotCached) {
+		t.Fatalf("expected ErrSchemaNotCached, got %v", err)
+	}
+	if _, _, err := serializer.SerializeWithSchema("payments-value", "  ", "pay-1"); !errors.Is(err, ErrSchemaRequired) {
+		t.Fatalf("expected ErrSchemaRequired, got %v", err)
+	}
+	if _, _, err := serializer.SerializeWithSchema(" ", testAvroSchema, "pay-1"); !errors.Is(err, ErrSubjectRequired) {
+		t.Fatalf("expected ErrSubjectRequired, got %v", err)
+	}
+
+	noFactory := NewAvroSerializer(newAvroMockRegistry(), nil)
+	if _, _, err := noFactory.SerializeWithSchema("payments-value", testAvroSchema, "pay-1"); !errors.Is(err, ErrCodecFactoryRequired) {
+		t.Fatalf("expected ErrCodecFactoryRequired, got %v", err)
+	}
+
+	protoOnly := NewAvroSerializer(&mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}, codecFor)
+	if _, _, err := protoOnly.SerializeWithSchema("payments-value", testAvroSchema, "pay-1"); !errors.Is(err, ErrAvroRegistrationUnsupported) {
+		t.Fatalf("expected ErrAvroRegistrationUnsupported, got %v", err)
+	}
+
+	registryErr := errors.New("registry down")
+	failing := newAvroMockRegistry()
+	failing.avroErr = registryErr
+	s := NewAvroSerializer(failing, codecFor)
+	if _, _, err := s.SerializeWithSchema("payments-value", testAvroSchema, "pay-1"); !errors.Is(err, registryErr) {
+		t.Fatalf("expected registry error, got %v", err)
+	}
+	if _, _, err := s.Serialize("payments-value", "pay-1"); !errors.Is(err, ErrSchemaNotCached) {
+		t.Fatalf("failed registration must not be cached, got %v", err)
+	}
+
+	codecErr := errors.New("bad schema")
+	badCodec := NewAvroSerializer(newAvroMockRegistry(), func(string) (Codec, error) { return nil, codecErr })
+	if _, _, err := badCodec.SerializeWithSchema("payments-value", testAvroSchema, "pay-1"); !errors.Is(err, codecErr) {
+		t.Fatalf("expected codec factory error, got %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package schemaregistry
+
+import (
+	"container/list"
+	"sync"
+)
+
+type subjectCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type subjectCacheEntry struct {
+	subject string
+	value   subjectSchemaCache
+}
+
+func (c *subjectCache) get(subject string) (subjectSchemaCache, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[subject]
+	if !ok {
+		return subjectSchemaCache{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*subjectCacheEntry).value, true
+}
+
+func (c *subjectCache) put(subject string, value subjectSchemaCache) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[subject]; ok {
+		el.Value.(*subjectCacheEntry).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	c.entries[subject] = c.order.PushFront(&subjectCacheEntry{subject: subject, value: value})
+	for c.max > 0 && c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*subjectCacheEntry).subject)
+	}
+}
+
+func (c *subjectCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -2,6 +2,7 @@ package schemaregistry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -38,6 +39,12 @@ type RegistryClient interface {
 	RegisterSchemaWithRefs(subject, schema string, refs []SchemaReference) (int, error)
 }
 
+type CompatibilityChecker interface {
+	CheckCompatibilityWithRefs(subject, schema string, refs []SchemaReference) (bool, error)
+}
+
+const errorCodeSubjectNotFound = 40401
+
 func NewClient(cfg Config) (*Client, error) {
 	if strings.TrimSpace(cfg.URL) == "" {
 		return nil, fmt.Errorf("schema registry URL is required")
@@ -67,7 +74,14 @@ func (c *Client) RawClient() *sr.Client {
 }
 
 func (c *Client) withTimeout() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), c.timeout)
+	return c.withContextTimeout(context.Background())
+}
+
+func (c *Client) withContextTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, c.timeout)
 }
 
 func toSRSchema(schema string, refs []SchemaReference) sr.Schema {
@@ -99,6 +113,22 @@ func (c *Client) GetLatestSchema(subject string) (string, int, error) {
 	return ss.Schema.Schema, ss.ID, nil
 }
 
+func (c *Client) GetSchemaByID(id int) (string, error) {
+	return c.schemaByID(context.Background(), id)
+}
+
+func (c *Client) schemaByID(ctx context.Context, id int) (string, error) {
+	ctx, cancel := c.withContextTimeout(ctx)
+	defer cancel()
+
+	s, err := c.registry.SchemaByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Schema, nil
+}
+
 func (c *Client) RegisterSchema(subject, schema string) (int, error) {
 	return c.RegisterSchemaWithRefs(subject, schema, nil)
 }
@@ -115,6 +145,14 @@ func (c *Client) RegisterSchemaWithRefs(subject, schema string, refs []SchemaRef
 	return id, nil
 }
 
+// RegisterAvroSchema registers schema with type AVRO (RegisterSchema uses PROTOBUF).
+func (c *Client) RegisterAvroSchema(subject, schema string) (int, error) {
+	ctx, cancel := c.withTimeout()
+	defer cancel()
+
+	return c.registry.RegisterSchema(ctx, subject, sr.Schema{Schema: schema, Type: sr.TypeAvro}, -1, -1)
+}
+
 func (c *Client) ValidateSchema(subject, schema string) (bool, error) {
 	return c.ValidateSchemaWithRefs(subject, schema, nil)
 }
@@ -131,6 +169,24 @@ func (c *Client) ValidateSchemaWithRefs(subject, schema string, refs []SchemaRef
 	return result.Is, nil
 }
 
+// CheckCompatibility reports whether schema is compatible with the latest version of subject.
+// A subject that does not exist yet is compatible.
+func (c *Client) CheckCompatibility(subject, schema string) (bool, error) {
+	return c.CheckCompatibilityWithRefs(subject, schema, nil)
+}
+
+func (c *Client) CheckCompatibilityWithRefs(subject, schema string, refs []SchemaReference) (bool, error) {
+	ok, err := c.ValidateSchemaWithRefs(subject, schema, refs)
+	if err != nil {
+		var re *sr.ResponseError
+		if errors.As(err, &re) && re.ErrorCode == errorCodeSubjectNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	return ok, nil
+}
+
 func (c *Client) GetAllSubjects() ([]string, error) {
 	ctx, cancel := c.withTimeout()
 	defer cancel()
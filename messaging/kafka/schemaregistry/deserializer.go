@@ -1,21 +1,40 @@
 package schemaregistry
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/twmb/franz-go/pkg/sr"
+	"google.golang.org/protobuf/proto"
 )
 
 var (
 	ErrDataTooShort          = errors.New("schema registry payload is too short")
 	ErrInvalidMagicByte      = errors.New("schema registry payload has invalid magic byte")
 	ErrInvalidMessageIndexes = errors.New("schema registry payload has invalid protobuf message indexes")
+	ErrMessageIndexMismatch  = errors.New("schema registry payload message indexes do not match target message")
 )
 
-type ProtoDeserializer struct{}
+type schemaLookup interface {
+	schemaByID(ctx context.Context, id int) (string, error)
+}
 
-func NewProtoDeserializer(_ *Client) *ProtoDeserializer {
-	return &ProtoDeserializer{}
+type ProtoDeserializer struct {
+	lookup schemaLookup
+	known  sync.Map
+}
+
+// NewProtoDeserializer creates a deserializer. With a non-nil client,
+// DeserializeInto verifies that schema IDs exist in the registry.
+func NewProtoDeserializer(client *Client) *ProtoDeserializer {
+	d := &ProtoDeserializer{}
+	if client != nil {
+		d.lookup = client
+	}
+	return d
 }
 
 // Deserialize parses Confluent wire format and returns protobuf payload + schema ID.
@@ -52,3 +71,41 @@ func (d *ProtoDeserializer) DeserializeWithIndexes(data []byte) ([]byte, int, []
 
 	return payload, schemaID, indexes, nil
 }
+
+// DeserializeInto parses Confluent wire format, checks the schema ID and message-index path
+// and unmarshals the payload into message.
+func (d *ProtoDeserializer) DeserializeInto(ctx context.Context, data []byte, message proto.Message) (int, error) {
+	if message == nil {
+		return 0, ErrNilMessage
+	}
+
+	payload, schemaID, indexes, err := d.DeserializeWithIndexes(data)
+	if err != nil {
+		return 0, err
+	}
+	if !slices.Equal(indexes, protobufMessageIndexPath(message)) {
+		return schemaID, ErrMessageIndexMismatch
+	}
+	if err := d.checkSchemaID(ctx, schemaID); err != nil {
+		return schemaID, err
+	}
+
+	if err := proto.Unmarshal(payload, message); err != nil {
+		return schemaID, fmt.Errorf("unmarshal protobuf payload: %w", err)
+	}
+	return schemaID, nil
+}
+
+func (d *ProtoDeserializer) checkSchemaID(ctx context.Context, schemaID int) error {
+	if d.lookup == nil {
+		return nil
+	}
+	if _, ok := d.known.Load(schemaID); ok {
+		return nil
+	}
+	if _, err := d.lookup.schemaByID(ctx, schemaID); err != nil {
+		return fmt.Errorf("lookup schema id %d: %w", schemaID, err)
+	}
+	d.known.Store(schemaID, struct{}{})
+	return nil
+}
@@ -1,9 +1,13 @@
 package schemaregistry
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestProtoDeserializer_Deserialize(t *testing.T) {
@@ -58,3 +62,86 @@ func TestProtoDeserializer_InvalidMessageIndexes(t *testing.T) {
 		t.Fatalf("expected ErrInvalidMessageIndexes, got %v", err)
 	}
 }
+
+func TestProtoDeserializer_DeserializeInto_RoundTrip(t *testing.T) {
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}
+	serializer := NewProtoSerializer(registry)
+	in := &wrapperspb.StringValue{Value: "pay-123"}
+
+	wire, schemaID, err := serializer.SerializeWithSchema("payments-value", `syntax = "proto3"; message StringValue { string value = 1; }`, in)
+	if err != nil {
+		t.Fatalf("serialize failed: %v", err)
+	}
+
+	out := &wrapperspb.StringValue{}
+	gotID, err := NewProtoDeserializer(nil).DeserializeInto(context.Background(), wire, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotID != schemaID {
+		t.Fatalf("expected schema ID %d, got %d", schemaID, gotID)
+	}
+	if !proto.Equal(in, out) {
+		t.Fatalf("round trip mismatch: got %v want %v", out, in)
+	}
+}
+
+func TestProtoDeserializer_DeserializeInto_Errors(t *testing.T) {
+	d := NewProtoDeserializer(nil)
+	wire := createWireFormat([]byte{}, 5, protobufMessageIndexPath(&wrapperspb.Int32Value{}))
+
+	tests := []struct {
+		name string
+		data []byte
+		msg  proto.Message
+		want error
+	}{
+		{"nil message", wire, nil, ErrNilMessage},
+		{"wrong magic byte", append([]byte{1}, wire[1:]...), &wrapperspb.Int32Value{}, ErrInvalidMagicByte},
+		{"truncated header", wire[:4], &wrapperspb.Int32Value{}, ErrDataTooShort},
+		{"index mismatch", wire, &wrapperspb.BoolValue{}, ErrMessageIndexMismatch},
+	}
+	for _, tt := range tests {
+		if _, err := d.DeserializeInto(context.Background(), tt.data, tt.msg); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestProtoDeserializer_DeserializeInto_ChecksSchemaIDOnce(t *testing.T) {
+	lookup := &lookupStub{known: map[int]string{7: "schema"}}
+	d := &ProtoDeserializer{lookup: lookup}
+	msg := &wrapperspb.Int32Value{Value: 3}
+	payload, _ := proto.Marshal(msg)
+
+	wire := createWireFormat(payload, 7, protobufMessageIndexPath(msg))
+	for i := 0; i < 3; i++ {
+		if _, err := d.DeserializeInto(context.Background(), wire, &wrapperspb.Int32Value{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lookup.calls != 1 {
+		t.Fatalf("expected 1 registry lookup, got %d", lookup.calls)
+	}
+
+	unknown := createWireFormat(payload, 8, protobufMessageIndexPath(msg))
+	if _, err := d.DeserializeInto(context.Background(), unknown, &wrapperspb.Int32Value{}); !errors.Is(err, errSchemaNotFound) {
+		t.Fatalf("expected lookup error, got %v", err)
+	}
+}
+
+var errSchemaNotFound = errors.New("schema not found")
+
+type lookupStub struct {
+	known map[int]string
+	calls int
+}
+
+func (l *lookupStub) schemaByID(_ context.Context, id int) (string, error) {
+	l.calls++
+	s, ok := l.known[id]
+	if !ok {
+		return "", errSchemaNotFound
+	}
+	return s, nil
+}
@@ -2,9 +2,9 @@ package schemaregistry
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/twmb/franz-go/pkg/sr"
 	"google.golang.org/protobuf/proto"
@@ -17,22 +17,49 @@ var (
 	ErrSchemaRequired  = errors.New("protobuf schema text is required for first serialize")
 	ErrSchemaNotCached = errors.New("schema id is not cached for subject; call SerializeWithSchema first")
 
+	ErrIncompatibleSchema            = errors.New("protobuf schema is incompatible with registered version")
+	ErrCompatibilityCheckUnsupported = errors.New("registry client does not support compatibility checks")
+
 	confluentHeader = new(sr.ConfluentHeader)
 )
 
 type ProtoSerializer struct {
 	registry RegistryClient
-	cache    sync.Map
+	cache    subjectCache
+
+	strictCompatibility bool
+}
+
+type SerializerOption func(*ProtoSerializer)
+
+// WithStrictCompatibility checks every schema against the registered version before
+// registering it and fails with ErrIncompatibleSchema instead of creating a new ID.
+// The registry must implement CompatibilityChecker.
+func WithStrictCompatibility() SerializerOption {
+	return func(s *ProtoSerializer) { s.strictCompatibility = true }
 }
 
 type subjectSchemaCache struct {
 	id      int
 	schema  string
 	refsKey string
+	codec   Codec // AvroSerializer only
 }
 
-func NewProtoSerializer(registry RegistryClient) *ProtoSerializer {
-	return &ProtoSerializer{registry: registry}
+// WithMaxCachedSubjects bounds the per-subject schema ID cache, evicting the least recently
+// used subject. An evicted subject needs SerializeWithSchema again. n <= 0 means unbounded.
+func WithMaxCachedSubjects(n int) SerializerOption {
+	return func(s *ProtoSerializer) { s.cache.max = n }
+}
+
+func NewProtoSerializer(registry RegistryClient, opts ...SerializerOption) *ProtoSerializer {
+	s := &ProtoSerializer{registry: registry}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
 }
 
 // Serialize serializes protobuf payload using cached schema ID for subject.
@@ -50,9 +77,8 @@ func (s *ProtoSerializer) Serialize(subject string, message proto.Message) ([]by
 		return nil, 0, err
 	}
 
-	if cachedRaw, ok := s.cache.Load(subject); ok {
-		schemaID := cachedRaw.(subjectSchemaCache).id
-		return createWireFormat(data, schemaID, protobufMessageIndexPath(message)), schemaID, nil
+	if cached, ok := s.cache.get(subject); ok {
+		return createWireFormat(data, cached.id, protobufMessageIndexPath(message)), cached.id, nil
 	}
 
 	return nil, 0, ErrSchemaNotCached
@@ -78,8 +104,7 @@ func (s *ProtoSerializer) SerializeWithSchemaRefs(subject, schema string, refs [
 	}
 
 	refsKey := referencesCacheKey(refs)
-	if cachedRaw, ok := s.cache.Load(subject); ok {
-		cached := cachedRaw.(subjectSchemaCache)
+	if cached, ok := s.cache.get(subject); ok {
 		if cached.schema == schema && cached.refsKey == refsKey {
 			return createWireFormat(data, cached.id, protobufMessageIndexPath(message)), cached.id, nil
 		}
@@ -89,15 +114,37 @@ func (s *ProtoSerializer) SerializeWithSchemaRefs(subject, schema string, refs [
 		return nil, 0, ErrSchemaRequired
 	}
 
+	if s.strictCompatibility {
+		if err := s.checkCompatibility(subject, schema, refs); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	schemaID, err := s.registry.RegisterSchemaWithRefs(subject, schema, refs)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	s.cache.Store(subject, subjectSchemaCache{id: schemaID, schema: schema, refsKey: refsKey})
+	s.cache.put(subject, subjectSchemaCache{id: schemaID, schema: schema, refsKey: refsKey})
 	return createWireFormat(data, schemaID, protobufMessageIndexPath(message)), schemaID, nil
 }
 
+func (s *ProtoSerializer) checkCompatibility(subject, schema string, refs []SchemaReference) error {
+	checker, ok := s.registry.(CompatibilityChecker)
+	if !ok {
+		return ErrCompatibilityCheckUnsupported
+	}
+
+	compatible, err := checker.CheckCompatibilityWithRefs(subject, schema, refs)
+	if err != nil {
+		return fmt.Errorf("check schema compatibility for subject %q: %w", subject, err)
+	}
+	if !compatible {
+		return fmt.Errorf("%w: subject %q", ErrIncompatibleSchema, subject)
+	}
+	return nil
+}
+
 func referencesCacheKey(refs []SchemaReference) string {
 	if len(refs) == 0 {
 		return ""
@@ -2,7 +2,9 @@ package schemaregistry
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/wrapperspb"
@@ -222,15 +224,230 @@ func TestProtoSerializer_DifferentMessagesUseDifferentIndexes(t *testing.T) {
 	}
 }
 
+const (
+	int32Schema        = `syntax = "proto3"; message Int32Value { int32 value = 1; }`
+	int32SchemaAddNote = `syntax = "proto3"; message Int32Value { int32 value = 1; string note = 2; }`
+	int32SchemaRetype  = `syntax = "proto3"; message Int32Value { string value = 1; }`
+)
+
+func TestProtoSerializer_StrictCompatibility_AllowsCompatibleChange(t *testing.T) {
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}
+	serializer := NewProtoSerializer(registry, WithStrictCompatibility())
+	msg := &wrapperspb.Int32Value{Value: 1}
+
+	_, id1, err := serializer.SerializeWithSchema("test-value", int32Schema, msg)
+	if err != nil {
+		t.Fatalf("first serialize failed: %v", err)
+	}
+	_, id2, err := serializer.SerializeWithSchema("test-value", int32SchemaAddNote, msg)
+	if err != nil {
+		t.Fatalf("compatible change failed: %v", err)
+	}
+
+	if id1 == id2 {
+		t.Fatalf("expected new schema ID for compatible change")
+	}
+	if len(registry.compatCalls) != 2 {
+		t.Fatalf("expected 2 compatibility checks, got %d", len(registry.compatCalls))
+	}
+	if registry.compatCalls[1].schema != int32SchemaAddNote {
+		t.Fatalf("expected changed schema to be checked, got %q", registry.compatCalls[1].schema)
+	}
+}
+
+func TestProtoSerializer_StrictCompatibility_RejectsIncompatibleChange(t *testing.T) {
+	registry := &mockRegistry{
+		schemas:      map[string]string{},
+		ids:          map[string]int{},
+		incompatible: map[string]bool{int32SchemaRetype: true},
+	}
+	serializer := NewProtoSerializer(registry, WithStrictCompatibility())
+	msg := &wrapperspb.Int32Value{Value: 1}
+
+	_, id1, err := serializer.SerializeWithSchema("test-value", int32Schema, msg)
+	if err != nil {
+		t.Fatalf("first serialize failed: %v", err)
+	}
+	_, _, err = serializer.SerializeWithSchema("test-value", int32SchemaRetype, msg)
+	if !errors.Is(err, ErrIncompatibleSchema) {
+		t.Fatalf("expected ErrIncompatibleSchema, got %v", err)
+	}
+	if len(registry.registerWithRefsCalls) != 1 {
+		t.Fatalf("incompatible schema must not be registered, got %d register calls", len(registry.registerWithRefsCalls))
+	}
+
+	_, cachedID, err := serializer.Serialize("test-value", msg)
+	if err != nil || cachedID != id1 {
+		t.Fatalf("expected cached ID %d to survive rejection, got %d (%v)", id1, cachedID, err)
+	}
+}
+
+func TestProtoSerializer_StrictCompatibility_CheckError(t *testing.T) {
+	checkErr := errors.New("registry unavailable")
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}, compatErr: checkErr}
+	serializer := NewProtoSerializer(registry, WithStrictCompatibility())
+
+	_, _, err := serializer.SerializeWithSchema("test-value", int32Schema, &wrapperspb.Int32Value{})
+	if !errors.Is(err, checkErr) {
+		t.Fatalf("expected check error, got %v", err)
+	}
+	if len(registry.registerWithRefsCalls) != 0 {
+		t.Fatalf("schema must not be registered when check fails")
+	}
+}
+
+func TestProtoSerializer_StrictCompatibility_RequiresChecker(t *testing.T) {
+	registry := struct{ RegistryClient }{&mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}}
+	serializer := NewProtoSerializer(registry, WithStrictCompatibility())
+
+	_, _, err := serializer.SerializeWithSchema("test-value", int32Schema, &wrapperspb.Int32Value{})
+	if !errors.Is(err, ErrCompatibilityCheckUnsupported) {
+		t.Fatalf("expected ErrCompatibilityCheckUnsupported, got %v", err)
+	}
+}
+
+func TestProtoSerializer_WithoutStrictCompatibility_SkipsCheck(t *testing.T) {
+	registry := &mockRegistry{
+		schemas:      map[string]string{},
+		ids:          map[string]int{},
+		incompatible: map[string]bool{int32SchemaRetype: true},
+	}
+	serializer := NewProtoSerializer(registry)
+	msg := &wrapperspb.Int32Value{Value: 1}
+
+	if _, _, err := serializer.SerializeWithSchema("test-value", int32Schema, msg); err != nil {
+		t.Fatalf("first serialize failed: %v", err)
+	}
+	if _, _, err := serializer.SerializeWithSchema("test-value", int32SchemaRetype, msg); err != nil {
+		t.Fatalf("expected unchanged behavior without strict mode, got %v", err)
+	}
+	if len(registry.compatCalls) != 0 {
+		t.Fatalf("expected no compatibility checks, got %d", len(registry.compatCalls))
+	}
+}
+
+func TestProtoSerializer_ConcurrentSerialize(t *testing.T) {
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}
+	serializer := NewProtoSerializer(registry, WithMaxCachedSubjects(4))
+
+	subjects := make([]string, 8)
+	for i := range subjects {
+		subjects[i] = fmt.Sprintf("tenant-%d-value", i)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for g := 0; g < 64; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				subject := subjects[(g+i)%len(subjects)]
+				msg := &wrapperspb.Int32Value{Value: int32(i)}
+				encoded, id, err := serializer.SerializeWithSchema(subject, int32Schema, msg)
+				if err != nil {
+					errs <- err
+					return
+				}
+				gotID, indexes, err := decodeHeaderIndexes(encoded)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if gotID != id || !reflect.DeepEqual(indexes, protobufMessageIndexPath(msg)) {
+					errs <- fmt.Errorf("corrupt header for %s: id %d/%d indexes %v", subject, gotID, id, indexes)
+					return
+				}
+				if _, _, err := serializer.Serialize(subject, msg); err != nil && !errors.Is(err, ErrSchemaNotCached) {
+					errs <- err
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if n := serializer.cache.len(); n > 4 {
+		t.Fatalf("cache exceeded bound: %d entries", n)
+	}
+}
+
+func TestProtoSerializer_EvictionForcesReRegister(t *testing.T) {
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}
+	serializer := NewProtoSerializer(registry, WithMaxCachedSubjects(2))
+	msg := &wrapperspb.Int32Value{Value: 1}
+
+	for _, subject := range []string{"a-value", "b-value"} {
+		if _, _, err := serializer.SerializeWithSchema(subject, int32Schema, msg); err != nil {
+			t.Fatalf("serialize %s failed: %v", subject, err)
+		}
+	}
+	if _, _, err := serializer.Serialize("a-value", msg); err != nil {
+		t.Fatalf("expected a-value cached: %v", err)
+	}
+	if _, _, err := serializer.SerializeWithSchema("c-value", int32Schema, msg); err != nil {
+		t.Fatalf("serialize c-value failed: %v", err)
+	}
+
+	if _, _, err := serializer.Serialize("b-value", msg); !errors.Is(err, ErrSchemaNotCached) {
+		t.Fatalf("expected least recently used b-value to be evicted, got %v", err)
+	}
+	if _, _, err := serializer.Serialize("a-value", msg); err != nil {
+		t.Fatalf("recently used a-value must stay cached: %v", err)
+	}
+
+	before := len(registry.registerWithRefsCalls)
+	if _, _, err := serializer.SerializeWithSchema("b-value", int32Schema, msg); err != nil {
+		t.Fatalf("re-serialize b-value failed: %v", err)
+	}
+	if got := len(registry.registerWithRefsCalls) - before; got != 1 {
+		t.Fatalf("expected evicted subject to be re-registered once, got %d calls", got)
+	}
+}
+
+func TestProtoSerializer_ZeroValueUsable(t *testing.T) {
+	registry := &mockRegistry{schemas: map[string]string{}, ids: map[string]int{}}
+	serializer := &ProtoSerializer{registry: registry}
+
+	if _, _, err := serializer.SerializeWithSchema("test-value", int32Schema, &wrapperspb.Int32Value{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := serializer.Serialize("test-value", &wrapperspb.Int32Value{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 type mockRegistry struct {
+	mu                    sync.Mutex
 	schemas               map[string]string
 	ids                   map[string]int
 	getCalls              []string
 	registerCalls         []string
 	registerWithRefsCalls []registerCall
+	compatCalls           []registerCall
+	incompatible          map[string]bool
+	compatErr             error
+}
+
+func (m *mockRegistry) CheckCompatibilityWithRefs(subject, schema string, refs []SchemaReference) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.compatCalls = append(m.compatCalls, registerCall{subject: subject, schema: schema, refs: refs})
+	if m.compatErr != nil {
+		return false, m.compatErr
+	}
+	return !m.incompatible[schema], nil
 }
 
 func (m *mockRegistry) GetLatestSchema(subject string) (string, int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.getCalls = append(m.getCalls, subject)
 	schema, ok := m.schemas[subject]
 	if !ok {
@@ -244,6 +461,9 @@ func (m *mockRegistry) GetLatestSchema(subject string) (string, int, error) {
 }
 
 func (m *mockRegistry) RegisterSchema(subject, schema string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.registerCalls = append(m.registerCalls, subject)
 	if id, ok := m.ids[subject]; ok && m.schemas[subject] == schema {
 		return id, nil
@@ -255,6 +475,9 @@ func (m *mockRegistry) RegisterSchema(subject, schema string) (int, error) {
 }
 
 func (m *mockRegistry) RegisterSchemaWithRefs(subject, schema string, refs []SchemaReference) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.registerWithRefsCalls = append(m.registerWithRefsCalls, registerCall{subject: subject, schema: schema, refs: refs})
 	if id, ok := m.ids[subject]; ok && m.schemas[subject] == schema {
 		return id, nil
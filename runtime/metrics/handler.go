@@ -2,10 +2,14 @@ package metrics
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/pprof"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -32,17 +36,24 @@ type Options struct {
 	Registry *prometheus.Registry
 	Register func(reg prometheus.Registerer) error
 
-	// Health and Ready must respect ctx.Done() and return promptly on cancellation,
+	// Health, Ready and Live must respect ctx.Done() and return promptly on cancellation,
 	// otherwise healthCheckConcurrencyLimit can be exhausted by stuck checks.
 	Health func(ctx context.Context, r *http.Request) error
 	Ready  func(ctx context.Context, r *http.Request) error
+	Live   func(ctx context.Context, r *http.Request) error
+
+	// ReadyChecks runs several named readiness checks concurrently on the ready path
+	// and reports each result as JSON. Mutually exclusive with Ready: New panics if both are set.
+	ReadyChecks map[string]func(ctx context.Context, r *http.Request) error
 
 	MetricsPath string
 	HealthPath  string
 	ReadyPath   string
+	LivePath    string
 
 	HealthTimeout time.Duration
 	ReadyTimeout  time.Duration
+	LiveTimeout   time.Duration
 
 	MetricsAuth AuthFunc
 	Log         LogFunc
@@ -54,6 +65,112 @@ type Options struct {
 
 	// DisableBuildInfo: if true, does not register build_info metrics.
 	DisableBuildInfo bool
+
+	// DisableSelfMetrics: if true, does not register metrics about the handler's own endpoints
+	// (metrics_handler_requests_total, metrics_handler_request_duration_seconds).
+	DisableSelfMetrics bool
+
+	// DisableGzip: if true, the metrics endpoint never compresses the exposition. By default
+	// it honors Accept-Encoding: gzip, like promhttp. Ignored when HandlerOpts is set.
+	// Health, ready and live responses are never compressed.
+	DisableGzip bool
+
+	// HandlerOpts is passed to promhttp.HandlerFor as-is (e.g. to disable OpenMetrics or set
+	// MaxRequestsInFlight), including DisableCompression.
+	// nil means {EnableOpenMetrics: true, DisableCompression: DisableGzip}.
+	HandlerOpts *promhttp.HandlerOpts
+
+	// EnablePprof: if true, serves net/http/pprof under /debug/pprof/, guarded by PprofAuth
+	// (or MetricsAuth when PprofAuth is nil). Without either, pprof is not mounted: New logs
+	// an error and NewServer fails with ErrPprofAuthRequired. Off by default: profiles expose
+	// memory contents, command-line flags and symbols.
+	EnablePprof bool
+	PprofAuth   AuthFunc
+
+	// TLSCertFile and TLSKeyFile make NewServer serve HTTPS. Set both or neither.
+	// Ignored by New.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// HealthError lets a health/ready/live check control the HTTP status and the response message.
+// Status must be 4xx or 5xx, otherwise 503 is used. Msg is written to the body for GET requests;
+// if empty, the status text is used. Plain errors map to 503 with err.Error() as the body.
+type HealthError struct {
+	Status int
+	Msg    string
+}
+
+func (e HealthError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return fmt.Sprintf("health check failed: status %d", e.Status)
+}
+
+func checkFailure(err error) (status int, msg string) {
+	var he HealthError
+	var hp *HealthError
+	switch {
+	case errors.As(err, &he):
+	case errors.As(err, &hp) && hp != nil:
+		he = *hp
+	default:
+		return http.StatusServiceUnavailable, err.Error()
+	}
+	status = he.Status
+	if status < 400 || status > 599 {
+		status = http.StatusServiceUnavailable
+	}
+	msg = he.Msg
+	if msg == "" {
+		msg = http.StatusText(status)
+	}
+	return status, msg
+}
+
+type selfMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func (m *selfMetrics) observe(path, method string, status int, d time.Duration) {
+	method = methodLabel(method)
+	m.requests.WithLabelValues(path, method, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(path, method).Observe(d.Seconds())
+}
+
+// methodLabel keeps the method label bounded: the request method is client-controlled.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return method
+	default:
+		return "other"
+	}
+}
+
+func registerSelfMetrics(reg prometheus.Registerer, log LogFunc) (*selfMetrics, error) {
+	m := &selfMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "metrics_handler_requests_total",
+			Help: "Requests served by the metrics handler by path, method and status",
+		}, []string{"path", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "metrics_handler_request_duration_seconds",
+			Help:    "Duration of requests served by the metrics handler",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+		}, []string{"path", "method"}),
+	}
+
+	var err error
+	if m.requests, err = registerOrExisting(reg, m.requests, log, "self_requests"); err != nil {
+		return nil, err
+	}
+	if m.duration, err = registerOrExisting(reg, m.duration, log, "self_duration"); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 func registerCollector(reg prometheus.Registerer, c prometheus.Collector, log LogFunc, name string) error {
@@ -70,6 +187,23 @@ func registerCollector(reg prometheus.Registerer, c prometheus.Collector, log Lo
 	return nil
 }
 
+func registerOrExisting[T prometheus.Collector](reg prometheus.Registerer, c T, log LogFunc, name string) (T, error) {
+	err := reg.Register(c)
+	if err == nil {
+		return c, nil
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+	if log != nil {
+		log(LogError, fmt.Sprintf("metrics.register.%s: %v", name, err), "REGISTER", http.StatusInternalServerError, 0)
+	}
+	return c, err
+}
+
 func methodNotAllowed(w http.ResponseWriter, headOnly bool) {
 	w.Header().Set("Cache-Control", "no-store")
 	w.Header().Set("Allow", "GET, HEAD")
@@ -100,9 +234,14 @@ func writeError(w http.ResponseWriter, msg string, status int, headOnly bool) {
 }
 
 func New(opts Options) (http.Handler, *prometheus.Registry) {
+	if opts.Ready != nil && len(opts.ReadyChecks) > 0 {
+		panic("metrics: Ready and ReadyChecks are mutually exclusive")
+	}
+
 	metricsPath := normalizePath(opts.MetricsPath, "/metrics")
 	healthPath := normalizePath(opts.HealthPath, "/health")
 	readyPath := normalizePath(opts.ReadyPath, "/ready")
+	livePath := normalizePath(opts.LivePath, "/livez")
 
 	healthTimeout := opts.HealthTimeout
 	if healthTimeout <= 0 {
@@ -112,6 +251,10 @@ func New(opts Options) (http.Handler, *prometheus.Registry) {
 	if readyTimeout <= 0 {
 		readyTimeout = 500 * time.Millisecond
 	}
+	liveTimeout := opts.LiveTimeout
+	if liveTimeout <= 0 {
+		liveTimeout = 500 * time.Millisecond
+	}
 
 	reg := opts.Registry
 	if reg == nil {
@@ -133,6 +276,14 @@ func New(opts Options) (http.Handler, *prometheus.Registry) {
 		}
 	}
 
+	var self *selfMetrics
+	if !opts.DisableSelfMetrics {
+		var err error
+		if self, err = registerSelfMetrics(reg, log); err != nil && strict {
+			return nil, nil
+		}
+	}
+
 	if opts.Register != nil {
 		if err := opts.Register(reg); err != nil {
 			if log != nil {
@@ -147,9 +298,11 @@ func New(opts Options) (http.Handler, *prometheus.Registry) {
 	mux := http.NewServeMux()
 	healthSem := make(chan struct{}, healthCheckConcurrencyLimit)
 
-	metricsHandler := promhttp.HandlerFor(reg, promhttp.HandlerOpts{
-		EnableOpenMetrics: true,
-	})
+	handlerOpts := promhttp.HandlerOpts{EnableOpenMetrics: true, DisableCompression: opts.DisableGzip}
+	if opts.HandlerOpts != nil {
+		handlerOpts = *opts.HandlerOpts
+	}
+	metricsHandler := promhttp.HandlerFor(reg, handlerOpts)
 
 	mux.Handle(metricsPath, withLog(
 		withMetricsAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -158,30 +311,150 @@ func New(opts Options) (http.Handler, *prometheus.Registry) {
 				return
 			}
 			w.Header().Set("Cache-Control", "no-store")
+			if !handlerOpts.DisableCompression {
+				w.Header().Add("Vary", "Accept-Encoding")
+			}
 			metricsHandler.ServeHTTP(w, r)
 		}), opts.MetricsAuth),
-		metricsPath, log,
+		metricsPath, log, self,
 	))
 
-	mux.Handle(healthPath, withLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if opts.EnablePprof {
+		if opts.PprofAuth == nil && opts.MetricsAuth == nil {
+			if log != nil {
+				log(LogError, "metrics.pprof: "+ErrPprofAuthRequired.Error()+"; not mounted", "PPROF", http.StatusInternalServerError, 0)
+			}
+		} else {
+			registerPprof(mux, opts, log, self)
+		}
+	}
+
+	mux.Handle(healthPath, withLog(probeHandler(opts.Health, healthTimeout, healthSem), healthPath, log, self))
+	if len(opts.ReadyChecks) > 0 {
+		mux.Handle(readyPath, withLog(readyChecksHandler(opts.ReadyChecks, readyTimeout, healthSem), readyPath, log, self))
+	} else {
+		mux.Handle(readyPath, withLog(probeHandler(opts.Ready, readyTimeout, healthSem), readyPath, log, self))
+	}
+	mux.Handle(livePath, withLog(probeHandler(opts.Live, liveTimeout, healthSem), livePath, log, self))
+
+	return mux, reg
+}
+
+// registerPprof mounts the pprof handlers. Named profiles (heap, goroutine, ...) are served by
+// pprof.Index and logged under the /debug/pprof/ path to keep the label set bounded.
+func registerPprof(mux *http.ServeMux, opts Options, log LogFunc, self *selfMetrics) {
+	auth := opts.PprofAuth
+	if auth == nil {
+		auth = opts.MetricsAuth
+	}
+	for path, h := range map[string]http.HandlerFunc{
+		"/debug/pprof/":        pprof.Index,
+		"/debug/pprof/cmdline": pprof.Cmdline,
+		"/debug/pprof/profile": pprof.Profile,
+		"/debug/pprof/symbol":  pprof.Symbol,
+		"/debug/pprof/trace":   pprof.Trace,
+	} {
+		mux.Handle(path, withLog(
+			withMetricsAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-store")
+				h.ServeHTTP(w, r)
+			}), auth),
+			path, log, self,
+		))
+	}
+}
+
+func probeHandler(check func(context.Context, *http.Request) error, timeout time.Duration, sem chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			methodNotAllowed(w, r.Method == http.MethodHead)
 			return
 		}
 		w.Header().Set("Cache-Control", "no-store")
-		runHealthCheck(w, r, opts.Health, healthTimeout, healthSem, r.Method == http.MethodHead)
-	}), healthPath, log))
+		runHealthCheck(w, r, check, timeout, sem, r.Method == http.MethodHead)
+	})
+}
 
-	mux.Handle(readyPath, withLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+func readyChecksHandler(checks map[string]func(context.Context, *http.Request) error, timeout time.Duration, sem chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			methodNotAllowed(w, r.Method == http.MethodHead)
 			return
 		}
 		w.Header().Set("Cache-Control", "no-store")
-		runHealthCheck(w, r, opts.Ready, readyTimeout, healthSem, r.Method == http.MethodHead)
-	}), readyPath, log))
+		runReadyChecks(w, r, checks, timeout, sem, r.Method == http.MethodHead)
+	})
+}
 
-	return mux, reg
+type checkResult struct {
+	name string
+	err  error
+}
+
+func runReadyChecks(w http.ResponseWriter, r *http.Request, checks map[string]func(context.Context, *http.Request) error, timeout time.Duration, sem chan struct{}, headOnly bool) {
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// One slot per request, held until every check has returned, so any number of
+	// checks fits the limit and stuck checks still count against it.
+	select {
+	case sem <- struct{}{}:
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeError(w, "health check busy", http.StatusServiceUnavailable, headOnly)
+		return
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan checkResult, len(checks))
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if check == nil {
+				done <- checkResult{name: name}
+				return
+			}
+			done <- checkResult{name: name, err: check(ctx, r)}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		<-sem
+	}()
+
+	results := make(map[string]string, len(checks))
+	status := http.StatusOK
+	timedOut := false
+	for len(results) < len(checks) && !timedOut {
+		select {
+		case res := <-done:
+			if res.err != nil {
+				_, msg := checkFailure(res.err)
+				results[res.name] = "error: " + msg
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			results[res.name] = "ok"
+		case <-ctx.Done():
+			timedOut = true
+		}
+	}
+	if timedOut {
+		for name := range checks {
+			if _, ok := results[name]; !ok {
+				results[name] = "error: health check timeout"
+			}
+		}
+		status = http.StatusServiceUnavailable
+		w.Header().Set("Retry-After", "1")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if !headOnly {
+		_ = json.NewEncoder(w).Encode(results)
+	}
 }
 
 func runHealthCheck(w http.ResponseWriter, r *http.Request, check func(context.Context, *http.Request) error, timeout time.Duration, sem chan struct{}, headOnly bool) {
@@ -213,7 +486,8 @@ func runHealthCheck(w http.ResponseWriter, r *http.Request, check func(context.C
 	select {
 	case err := <-done:
 		if err != nil {
-			writeError(w, err.Error(), http.StatusServiceUnavailable, headOnly)
+			status, msg := checkFailure(err)
+			writeError(w, msg, status, headOnly)
 			return
 		}
 		w.WriteHeader(http.StatusOK)
@@ -226,8 +500,8 @@ func runHealthCheck(w http.ResponseWriter, r *http.Request, check func(context.C
 	}
 }
 
-func withLog(h http.Handler, path string, log LogFunc) http.Handler {
-	if log == nil {
+func withLog(h http.Handler, path string, log LogFunc, self *selfMetrics) http.Handler {
+	if log == nil && self == nil {
 		return h
 	}
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -237,7 +511,13 @@ func withLog(h http.Handler, path string, log LogFunc) http.Handler {
 		if lrw.status == 0 {
 			lrw.status = http.StatusOK
 		}
-		log(logLevelFromStatus(lrw.status), path, r.Method, lrw.status, time.Since(start))
+		d := time.Since(start)
+		if self != nil {
+			self.observe(path, r.Method, lrw.status, d)
+		}
+		if log != nil {
+			log(logLevelFromStatus(lrw.status), path, r.Method, lrw.status, d)
+		}
 	})
 }
 
@@ -1,8 +1,11 @@
 package metrics
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +16,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func TestMetricsHandler_Defaults(t *testing.T) {
@@ -327,6 +331,339 @@ func TestMetricsHandler_ReadyEndpoint_OK(t *testing.T) {
 	}
 }
 
+func TestMetricsHandler_LiveEndpoint(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Live: func(ctx context.Context, r *http.Request) error {
+			return errors.New("event loop stuck")
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/livez")
+	if err != nil {
+		t.Fatalf("GET /livez: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status /livez = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestMetricsHandler_LiveEndpoint_OK(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Live: func(ctx context.Context, r *http.Request) error {
+			return nil
+		},
+		Ready: func(ctx context.Context, r *http.Request) error {
+			return errors.New("not ready")
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/livez")
+	if err != nil {
+		t.Fatalf("GET /livez: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status /livez = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestMetricsHandler_LiveCustomPath_HeadAnd405(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{LivePath: "alive"})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodHead, srv.URL+"/alive", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("HEAD /alive: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || len(body) != 0 {
+		t.Fatalf("HEAD /alive = %d body=%q, want 200 and empty body", resp.StatusCode, body)
+	}
+
+	resp2, err := http.Post(srv.URL+"/alive", "text/plain", nil)
+	if err != nil {
+		t.Fatalf("POST /alive: %v", err)
+	}
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("POST /alive = %d, want 405", resp2.StatusCode)
+	}
+	if got := resp2.Header.Get("Allow"); got != "GET, HEAD" {
+		t.Fatalf("Allow = %q, want %q", got, "GET, HEAD")
+	}
+}
+
+func TestMetricsHandler_LiveTimeoutRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Live: func(ctx context.Context, r *http.Request) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+		LiveTimeout: 20 * time.Millisecond,
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/livez")
+	if err != nil {
+		t.Fatalf("GET /livez: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status /livez = %d, want 503", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want 1", got)
+	}
+}
+
+func TestMetricsHandler_ReadyChecks_JSONAndAggregateStatus(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		ReadyChecks: map[string]func(ctx context.Context, r *http.Request) error{
+			"db":    func(ctx context.Context, r *http.Request) error { return nil },
+			"redis": func(ctx context.Context, r *http.Request) error { return errors.New("connection refused") },
+			"kafka": func(ctx context.Context, r *http.Request) error { return nil },
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status /ready = %d, want 503", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	want := map[string]string{"db": "ok", "redis": "error: connection refused", "kafka": "ok"}
+	if len(got) != len(want) {
+		t.Fatalf("body = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("body[%q] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestMetricsHandler_ReadyChecks_AllOK_HeadNoBody(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		ReadyChecks: map[string]func(ctx context.Context, r *http.Request) error{
+			"db":    func(ctx context.Context, r *http.Request) error { return nil },
+			"redis": func(ctx context.Context, r *http.Request) error { return nil },
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status /ready = %d, want 200", resp.StatusCode)
+	}
+	if strings.TrimSpace(string(body)) != `{"db":"ok","redis":"ok"}` {
+		t.Fatalf("body = %q", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodHead, srv.URL+"/ready", nil)
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("HEAD /ready: %v", err)
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK || len(body2) != 0 {
+		t.Fatalf("HEAD /ready = %d body=%q, want 200 and empty body", resp2.StatusCode, body2)
+	}
+}
+
+func TestMetricsHandler_ReadyChecks_Timeout(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		ReadyTimeout: 20 * time.Millisecond,
+		ReadyChecks: map[string]func(ctx context.Context, r *http.Request) error{
+			"db": func(ctx context.Context, r *http.Request) error { return nil },
+			"slow": func(ctx context.Context, r *http.Request) error {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				return ctx.Err()
+			},
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status /ready = %d, want 503", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want 1", got)
+	}
+	var got map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got["db"] != "ok" || got["slow"] != "error: health check timeout" {
+		t.Fatalf("body = %v", got)
+	}
+}
+
+func TestMetricsHandler_ReadyChecks_MoreThanConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	checks := make(map[string]func(ctx context.Context, r *http.Request) error, 2*healthCheckConcurrencyLimit)
+	for i := range 2 * healthCheckConcurrencyLimit {
+		checks[fmt.Sprintf("check-%d", i)] = func(ctx context.Context, r *http.Request) error { return nil }
+	}
+	h, _ := New(Options{ReadyChecks: checks})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status /ready = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if len(got) != len(checks) {
+		t.Fatalf("expected %d results, got %d", len(checks), len(got))
+	}
+}
+
+func TestMetricsHandler_ReadyAndReadyChecks_Panics(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic when both Ready and ReadyChecks are set")
+		}
+	}()
+	New(Options{
+		Ready: func(ctx context.Context, r *http.Request) error { return nil },
+		ReadyChecks: map[string]func(ctx context.Context, r *http.Request) error{
+			"db": func(ctx context.Context, r *http.Request) error { return nil },
+		},
+	})
+}
+
+func TestMetricsHandler_HealthError_CustomStatus(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Health: func(ctx context.Context, r *http.Request) error {
+			return fmt.Errorf("config check: %w", HealthError{Status: http.StatusInternalServerError, Msg: "misconfigured"})
+		},
+		Ready: func(ctx context.Context, r *http.Request) error {
+			return &HealthError{Status: http.StatusTooManyRequests}
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status /health = %d, want 500", resp.StatusCode)
+	}
+	if strings.TrimSpace(string(body)) != "misconfigured" {
+		t.Fatalf("body = %q, want safe message only", body)
+	}
+
+	resp2, err := http.Get(srv.URL + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready: %v", err)
+	}
+	body2, _ := io.ReadAll(resp2.Body)
+	resp2.Body.Close()
+	if resp2.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status /ready = %d, want 429", resp2.StatusCode)
+	}
+	if strings.TrimSpace(string(body2)) != http.StatusText(http.StatusTooManyRequests) {
+		t.Fatalf("body = %q, want status text", body2)
+	}
+
+	req, _ := http.NewRequest(http.MethodHead, srv.URL+"/health", nil)
+	resp3, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("HEAD /health: %v", err)
+	}
+	body3, _ := io.ReadAll(resp3.Body)
+	resp3.Body.Close()
+	if resp3.StatusCode != http.StatusInternalServerError || len(body3) != 0 {
+		t.Fatalf("HEAD /health = %d body=%q, want 500 and empty body", resp3.StatusCode, body3)
+	}
+}
+
+func TestMetricsHandler_HealthError_PlainAndInvalidStatusAre503(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Health: func(ctx context.Context, r *http.Request) error {
+			return errors.New("db down")
+		},
+		Live: func(ctx context.Context, r *http.Request) error {
+			return HealthError{Status: http.StatusOK, Msg: "bogus"}
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, path := range []string{"/health", "/livez"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("status %s = %d, want 503", path, resp.StatusCode)
+		}
+	}
+}
+
 func TestMetricsHandler_Auth(t *testing.T) {
 	t.Parallel()
 
@@ -414,6 +751,114 @@ func TestMetricsHandler_Logging(t *testing.T) {
 	}
 }
 
+func TestMetricsHandler_SelfMetrics(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		Ready: func(ctx context.Context, r *http.Request) error {
+			return errors.New("not ready")
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, path := range []string{"/health", "/health", "/ready"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	out := string(body)
+
+	for _, want := range []string{
+		"# TYPE metrics_handler_requests_total counter",
+		`metrics_handler_requests_total{method="GET",path="/health",status="200"} 2`,
+		`metrics_handler_requests_total{method="GET",path="/ready",status="503"} 1`,
+		"# TYPE metrics_handler_request_duration_seconds histogram",
+		`metrics_handler_request_duration_seconds_count{method="GET",path="/health"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestMetricsHandler_SelfMetrics_BoundedMethodLabel(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{})
+	for _, m := range []string{"BREW", "X-JUNK-1", "X-JUNK-2", http.MethodPost} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(m, "/health", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	out := rec.Body.String()
+	if !strings.Contains(out, `metrics_handler_requests_total{method="other",path="/health",status="405"} 4`) {
+		t.Fatalf("expected junk methods under method=\"other\":\n%s", out)
+	}
+	for _, junk := range []string{"BREW", "X-JUNK", "POST"} {
+		if strings.Contains(out, `method="`+junk) {
+			t.Fatalf("raw method %q leaked into labels:\n%s", junk, out)
+		}
+	}
+}
+
+func TestMetricsHandler_DisableSelfMetrics(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{DisableSelfMetrics: true})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health: %v", err)
+	}
+	resp.Body.Close()
+
+	resp2, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	defer resp2.Body.Close()
+	body, _ := io.ReadAll(resp2.Body)
+	if strings.Contains(string(body), "metrics_handler_") {
+		t.Fatalf("self metrics must be disabled:\n%s", body)
+	}
+}
+
+func TestMetricsHandler_SelfMetrics_SharedRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+	h1, _ := New(Options{Registry: reg, StrictRegister: true})
+	h2, _ := New(Options{Registry: reg, StrictRegister: true})
+	if h1 == nil || h2 == nil {
+		t.Fatal("expected both handlers to reuse already registered self metrics")
+	}
+
+	for _, h := range []http.Handler{h1, h2} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	}
+
+	rec := httptest.NewRecorder()
+	h1.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `metrics_handler_requests_total{method="GET",path="/health",status="200"} 2`) {
+		t.Fatalf("expected both handlers to update shared series:\n%s", rec.Body.String())
+	}
+}
+
 func TestMetricsHandler_HealthAndReadySeparate(t *testing.T) {
 	t.Parallel()
 
@@ -530,6 +975,134 @@ func TestMetricsHandler_CacheControlNoStore(t *testing.T) {
 	}
 }
 
+func TestMetricsHandler_Gzip(t *testing.T) {
+	t.Parallel()
+
+	ctr := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "test",
+		Name:      "gzip_total",
+		Help:      "gzip counter",
+	})
+	h, _ := New(Options{
+		MetricsAuth: func(r *http.Request) bool { return r.Header.Get("X-Token") == "ok" },
+		Register: func(reg prometheus.Registerer) error {
+			return reg.Register(ctr)
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("X-Token", "ok")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Fatalf("Cache-Control = %q, want no-store", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read gzip body: %v", err)
+	}
+	content := string(body)
+	if !strings.Contains(content, "# HELP test_gzip_total gzip counter") ||
+		!strings.Contains(content, "# TYPE test_gzip_total counter") {
+		t.Fatalf("decompressed output missing HELP/TYPE lines:\n%s", content)
+	}
+
+	// Без авторизации сжатие не применяется: auth срабатывает раньше.
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("Content-Encoding") != "" {
+		t.Fatalf("unauthorized scrape: status %d, Content-Encoding %q", rec.Code, rec.Header().Get("Content-Encoding"))
+	}
+
+	for _, path := range []string{"/health", "/ready", "/livez"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Content-Encoding"); got != "" {
+			t.Fatalf("%s: Content-Encoding = %q, want none", path, got)
+		}
+	}
+}
+
+func TestMetricsHandler_DisableGzip(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{DisableGzip: true})
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("Content-Encoding = %q, want none", got)
+	}
+	if !strings.Contains(rec.Body.String(), "# TYPE") {
+		t.Fatalf("expected plain exposition, got %q", rec.Body.String())
+	}
+}
+
+func TestMetricsHandler_HandlerOpts(t *testing.T) {
+	t.Parallel()
+
+	scrape := func(h http.Handler) string {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		return rec.Header().Get("Content-Type")
+	}
+
+	def, _ := New(Options{})
+	if ct := scrape(def); !strings.HasPrefix(ct, "application/openmetrics-text") {
+		t.Fatalf("default Content-Type = %q, want OpenMetrics", ct)
+	}
+
+	classic, _ := New(Options{HandlerOpts: &promhttp.HandlerOpts{EnableOpenMetrics: false}})
+	if ct := scrape(classic); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("Content-Type with OpenMetrics disabled = %q, want classic text format", ct)
+	}
+}
+
+func TestMetricsHandler_HandlerOpts_DisableCompressionHonored(t *testing.T) {
+	t.Parallel()
+
+	encoding := func(h http.Handler) string {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Header().Get("Content-Encoding")
+	}
+
+	off, _ := New(Options{HandlerOpts: &promhttp.HandlerOpts{DisableCompression: true}})
+	if got := encoding(off); got != "" {
+		t.Fatalf("HandlerOpts.DisableCompression=true: Content-Encoding = %q, want none", got)
+	}
+
+	on, _ := New(Options{DisableGzip: true, HandlerOpts: &promhttp.HandlerOpts{}})
+	if got := encoding(on); got != "gzip" {
+		t.Fatalf("HandlerOpts.DisableCompression=false: Content-Encoding = %q, want gzip", got)
+	}
+}
+
 func TestMetricsHandler_RetryAfterOnBusy(t *testing.T) {
 	t.Parallel()
 
@@ -906,3 +1479,146 @@ func TestNormalizePath_EmptyDefault(t *testing.T) {
 		t.Fatalf("normalizePath empty = %q, want /", got)
 	}
 }
+
+func TestMetricsHandler_PprofDisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, p := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap"} {
+		resp, err := http.Get(srv.URL + p)
+		if err != nil {
+			t.Fatalf("GET %s: %v", p, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("status %s = %d, want 404", p, resp.StatusCode)
+		}
+	}
+}
+
+func TestMetricsHandler_PprofWithoutAuthNotMounted(t *testing.T) {
+	t.Parallel()
+
+	var logged []string
+	h, _ := New(Options{
+		EnablePprof: true,
+		Log: func(level LogLevel, path, method string, status int, duration time.Duration) {
+			if level == LogError {
+				logged = append(logged, path)
+			}
+		},
+	})
+
+	for _, p := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap"} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("status %s = %d, want 404", p, rec.Code)
+		}
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], ErrPprofAuthRequired.Error()) {
+		t.Fatalf("expected one pprof config error logged, got %v", logged)
+	}
+}
+
+func TestMetricsHandler_PprofAuth(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var logged []string
+
+	h, _ := New(Options{
+		EnablePprof: true,
+		MetricsAuth: func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer secret"
+		},
+		Log: func(level LogLevel, path, method string, status int, duration time.Duration) {
+			mu.Lock()
+			logged = append(logged, fmt.Sprintf("%s %d", path, status))
+			mu.Unlock()
+		},
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	for _, p := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap?debug=1"} {
+		resp, err := http.Get(srv.URL + p)
+		if err != nil {
+			t.Fatalf("GET %s: %v", p, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("status %s without auth = %d, want 401", p, resp.StatusCode)
+		}
+
+		req, _ := http.NewRequest("GET", srv.URL+p, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		resp2, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s with auth: %v", p, err)
+		}
+		body, _ := io.ReadAll(resp2.Body)
+		resp2.Body.Close()
+		if resp2.StatusCode != http.StatusOK || len(body) == 0 {
+			t.Fatalf("status %s with auth = %d (body %d bytes), want 200", p, resp2.StatusCode, len(body))
+		}
+		if cc := resp2.Header.Get("Cache-Control"); cc != "no-store" {
+			t.Fatalf("Cache-Control %s = %q, want no-store", p, cc)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{
+		"/debug/pprof/ 401", "/debug/pprof/ 200",
+		"/debug/pprof/cmdline 401", "/debug/pprof/cmdline 200",
+		"/debug/pprof/ 401", "/debug/pprof/ 200",
+	}
+	if strings.Join(logged, ",") != strings.Join(want, ",") {
+		t.Fatalf("logged %v, want %v", logged, want)
+	}
+}
+
+func TestMetricsHandler_PprofAuthOverridesMetricsAuth(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{
+		EnablePprof: true,
+		MetricsAuth: func(r *http.Request) bool { return true },
+		PprofAuth:   func(r *http.Request) bool { return r.Header.Get("X-Debug-Token") == "ok" },
+	})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status /metrics = %d, want 200", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/debug/pprof/")
+	if err != nil {
+		t.Fatalf("GET /debug/pprof/: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status /debug/pprof/ without token = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/debug/pprof/goroutine?debug=1", nil)
+	req.Header.Set("X-Debug-Token", "ok")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /debug/pprof/goroutine: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status /debug/pprof/goroutine with token = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vortex-fintech/go-lib/runtime/shutdown/adapters"
+)
+
+var (
+	ErrTLSFilesIncomplete = errors.New("metrics: TLSCertFile and TLSKeyFile must be set together")
+	ErrHandlerNotCreated  = errors.New("metrics: handler not created (StrictRegister failed)")
+	ErrPprofAuthRequired  = errors.New("metrics: EnablePprof requires PprofAuth or MetricsAuth")
+)
+
+// Server is a ready-to-run admin server for the handler built by New.
+// It implements shutdown.Server (Serve, GracefulStopWithTimeout, ForceStop, Name).
+type Server struct {
+	http *adapters.HTTP
+	ln   net.Listener
+	reg  *prometheus.Registry
+}
+
+// NewServer builds the handler with New(opts) and binds addr right away, so Addr reports
+// the real port for ":0". With opts.TLSCertFile/TLSKeyFile it serves HTTPS (TLS 1.2+).
+// Like New, it panics if both Ready and ReadyChecks are set.
+func NewServer(addr string, opts Options) (*Server, error) {
+	if (opts.TLSCertFile == "") != (opts.TLSKeyFile == "") {
+		return nil, ErrTLSFilesIncomplete
+	}
+	if opts.EnablePprof && opts.PprofAuth == nil && opts.MetricsAuth == nil {
+		return nil, ErrPprofAuthRequired
+	}
+
+	var tlsCfg *tls.Config
+	if opts.TLSCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.TLSCertFile, opts.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("metrics: load TLS key pair: %w", err)
+		}
+		tlsCfg = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+
+	h, reg := New(opts)
+	if h == nil {
+		return nil, ErrHandlerNotCreated
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("metrics: listen %s: %w", addr, err)
+	}
+	if tlsCfg != nil {
+		ln = tls.NewListener(ln, tlsCfg)
+	}
+
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return &Server{
+		http: &adapters.HTTP{Srv: srv, Lis: ln, NameStr: "metrics"},
+		ln:   ln,
+		reg:  reg,
+	}, nil
+}
+
+// Addr returns the bound listener address (e.g. "127.0.0.1:43127").
+func (s *Server) Addr() string { return s.ln.Addr().String() }
+
+// Registry returns the registry the handler serves.
+func (s *Server) Registry() *prometheus.Registry { return s.reg }
+
+// Serve blocks until ctx is done or the server is stopped. A stopped server returns nil.
+func (s *Server) Serve(ctx context.Context) error {
+	err := s.http.Serve(ctx)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// GracefulStopWithTimeout stops accepting connections and waits for in-flight scrapes
+// until ctx is done.
+func (s *Server) GracefulStopWithTimeout(ctx context.Context) error {
+	err := s.http.GracefulStopWithTimeout(ctx)
+	_ = s.ln.Close() // Shutdown only closes listeners Serve was called with
+	return err
+}
+
+// ForceStop closes the listener and all connections.
+func (s *Server) ForceStop() {
+	s.http.ForceStop()
+	_ = s.ln.Close()
+}
+
+// Name returns "metrics".
+func (s *Server) Name() string { return s.http.Name() }
@@ -0,0 +1,289 @@
+package metrics
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func startServer(t *testing.T, s *Server) <-chan error {
+	t.Helper()
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Serve(context.Background()) }()
+	t.Cleanup(s.ForceStop)
+	return errCh
+}
+
+func waitServe(t *testing.T, errCh <-chan error) {
+	t.Helper()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("Serve returned %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return")
+	}
+}
+
+func TestServer_ScrapeOnEphemeralPort(t *testing.T) {
+	t.Parallel()
+
+	ctr := prometheus.NewCounter(prometheus.CounterOpts{Name: "server_test_total", Help: "test counter"})
+	s, err := NewServer("127.0.0.1:0", Options{
+		Register: func(reg prometheus.Registerer) error { return reg.Register(ctr) },
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	if s.Name() != "metrics" {
+		t.Fatalf("Name = %q", s.Name())
+	}
+	if s.Registry() == nil {
+		t.Fatal("Registry is nil")
+	}
+	errCh := startServer(t, s)
+
+	resp, err := http.Get("http://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "server_test_total") {
+		t.Fatalf("status %d, body:\n%s", resp.StatusCode, body)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.GracefulStopWithTimeout(ctx); err != nil {
+		t.Fatalf("GracefulStopWithTimeout: %v", err)
+	}
+	waitServe(t, errCh)
+
+	if _, err := http.Get("http://" + s.Addr() + "/metrics"); err == nil {
+		t.Fatal("expected connection error after stop")
+	}
+}
+
+func TestServer_GracefulStopWaitsForInFlight(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	s, err := NewServer("127.0.0.1:0", Options{
+		HealthTimeout: 5 * time.Second,
+		Health: func(ctx context.Context, _ *http.Request) error {
+			close(entered)
+			<-release
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	errCh := startServer(t, s)
+
+	respCh := make(chan int, 1)
+	go func() {
+		resp, err := http.Get("http://" + s.Addr() + "/health")
+		if err != nil {
+			respCh <- 0
+			return
+		}
+		resp.Body.Close()
+		respCh <- resp.StatusCode
+	}()
+	<-entered
+
+	stopped := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		stopped <- s.GracefulStopWithTimeout(ctx)
+	}()
+
+	select {
+	case err := <-stopped:
+		t.Fatalf("graceful stop returned before in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatalf("GracefulStopWithTimeout: %v", err)
+	}
+	if code := <-respCh; code != http.StatusOK {
+		t.Fatalf("in-flight request status = %d, want 200", code)
+	}
+	waitServe(t, errCh)
+}
+
+func TestServer_GracefulStopTimeout(t *testing.T) {
+	t.Parallel()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	s, err := NewServer("127.0.0.1:0", Options{
+		HealthTimeout: 5 * time.Second,
+		Health: func(ctx context.Context, _ *http.Request) error {
+			close(entered)
+			<-release
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	startServer(t, s)
+
+	go func() {
+		if resp, err := http.Get("http://" + s.Addr() + "/health"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.GracefulStopWithTimeout(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestServer_TLS(t *testing.T) {
+	t.Parallel()
+
+	certFile, keyFile, pool := writeTestCert(t)
+	s, err := NewServer("127.0.0.1:0", Options{TLSCertFile: certFile, TLSKeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	errCh := startServer(t, s)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get("https://" + s.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET https /metrics: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	s.ForceStop()
+	waitServe(t, errCh)
+}
+
+func TestNewServer_Errors(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewServer("127.0.0.1:0", Options{TLSCertFile: "cert.pem"}); !errors.Is(err, ErrTLSFilesIncomplete) {
+		t.Fatalf("expected ErrTLSFilesIncomplete, got %v", err)
+	}
+	if _, err := NewServer("127.0.0.1:0", Options{EnablePprof: true}); !errors.Is(err, ErrPprofAuthRequired) {
+		t.Fatalf("expected ErrPprofAuthRequired, got %v", err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := NewServer("127.0.0.1:0", Options{TLSCertFile: missing, TLSKeyFile: missing}); err == nil {
+		t.Fatal("expected error for missing key pair")
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	if _, err := NewServer(ln.Addr().String(), Options{}); err == nil {
+		t.Fatal("expected error for busy address")
+	}
+
+	strict := Options{
+		StrictRegister: true,
+		Register:       func(prometheus.Registerer) error { return errors.New("boom") },
+	}
+	if _, err := NewServer("127.0.0.1:0", strict); !errors.Is(err, ErrHandlerNotCreated) {
+		t.Fatalf("expected ErrHandlerNotCreated, got %v", err)
+	}
+}
+
+func TestServer_StopWithoutServeReleasesPort(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewServer("127.0.0.1:0", Options{})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	addr := s.Addr()
+	if err := s.GracefulStopWithTimeout(context.Background()); err != nil {
+		t.Fatalf("GracefulStopWithTimeout: %v", err)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("port not released: %v", err)
+	}
+	ln.Close()
+}
+
+// writeTestCert пишет self-signed сертификат для 127.0.0.1 и возвращает пути и пул для клиента.
+func writeTestCert(t *testing.T) (certFile, keyFile string, pool *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "metrics-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool = x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -34,6 +35,12 @@ type Metrics interface {
 	IncServerStopResult(name, result string)
 }
 
+// PhaseMetrics is an optional extension of Metrics.
+// If Config.Metrics implements it, the duration of every shutdown phase is observed.
+type PhaseMetrics interface {
+	ObservePhaseDuration(phase int, d time.Duration)
+}
+
 // Config for Manager.
 type Config struct {
 	// ShutdownTimeout is the maximum time to wait for graceful shutdown.
@@ -52,6 +59,36 @@ type Config struct {
 
 	// Metrics collects shutdown statistics.
 	Metrics Metrics
+
+	// PreStopHook is called by Run after shutdown is triggered and before Stop,
+	// e.g. to flip readiness to failing. It shares the shutdown budget with Stop:
+	// time spent in the hook is taken from the servers' graceful stop. With a zero
+	// budget (ShutdownTimeout 0) the hook gets DefaultPreStopTimeout instead.
+	// An error is logged and does not prevent shutdown.
+	PreStopHook func(ctx context.Context) error
+
+	// PreStopDelay is waited after PreStopHook and before Stop,
+	// giving load balancers time to stop routing new traffic.
+	PreStopDelay time.Duration
+}
+
+// DefaultPreStopTimeout bounds PreStopHook when the shutdown budget is zero.
+const DefaultPreStopTimeout = 30 * time.Second
+
+// ServerOptions configures how a single server is stopped.
+type ServerOptions struct {
+	// ShutdownTimeout overrides Config.ShutdownTimeout for this server.
+	// If 0, Config.ShutdownTimeout is used.
+	ShutdownTimeout time.Duration
+
+	// Phase orders shutdown: lower phases are fully stopped before higher ones start.
+	// Servers without an explicit phase share phase 0 and stop concurrently.
+	Phase int
+}
+
+type managedServer struct {
+	srv Server
+	opt ServerOptions
 }
 
 // Manager handles graceful shutdown of multiple servers.
@@ -59,8 +96,12 @@ type Config struct {
 type Manager struct {
 	cfg     Config
 	mu      sync.Mutex
-	servers []Server
+	servers []managedServer
 	stopped bool
+	stopErr error
+
+	lastForced   atomic.Bool
+	lastDuration atomic.Int64
 }
 
 // New creates a new Manager with the given configuration.
@@ -77,18 +118,54 @@ func New(cfg Config) *Manager {
 
 // Add registers a server to be managed. Nil servers are ignored.
 func (m *Manager) Add(s Server) {
+	m.AddWithOptions(s, ServerOptions{})
+}
+
+// AddWithTimeout registers a server with its own graceful shutdown timeout.
+// A non-positive timeout falls back to Config.ShutdownTimeout.
+func (m *Manager) AddWithTimeout(s Server, timeout time.Duration) {
+	m.AddWithOptions(s, ServerOptions{ShutdownTimeout: timeout})
+}
+
+// AddWithOptions registers a server with per-server stop options. Nil servers are ignored.
+func (m *Manager) AddWithOptions(s Server, opt ServerOptions) {
 	if s == nil {
 		return
 	}
-	m.servers = append(m.servers, s)
+	m.servers = append(m.servers, managedServer{srv: s, opt: opt})
+}
+
+func (m *Manager) shutdownTimeout(ms managedServer) time.Duration {
+	if ms.opt.ShutdownTimeout > 0 {
+		return ms.opt.ShutdownTimeout
+	}
+	return m.cfg.ShutdownTimeout
+}
+
+func (m *Manager) shutdownBudget() time.Duration {
+	var total time.Duration
+	for _, phase := range m.phases() {
+		var longest time.Duration
+		for _, ms := range phase.servers {
+			longest = max(longest, m.shutdownTimeout(ms))
+		}
+		total += longest
+	}
+	return max(total, m.cfg.ShutdownTimeout)
 }
 
 // Run starts all registered servers and blocks until shutdown.
-// It returns any non-normal error from a server, or nil on clean shutdown.
+// It returns any non-normal error from a server. Otherwise, if some servers
+// had to be force-stopped, it returns the aggregated error from Stop; nil on clean shutdown.
 //
 // If HandleSignals is true, SIGINT and SIGTERM trigger graceful shutdown.
-// After ctx is cancelled or a server fails, Stop() is called to shut down all servers.
+// After ctx is cancelled or a server fails, PreStopHook and PreStopDelay are applied,
+// then Stop() is called to shut down all servers.
 func (m *Manager) Run(ctx context.Context) error {
+	m.lastForced.Store(false)
+	m.lastDuration.Store(0)
+
+	parent := ctx
 	if m.cfg.HandleSignals {
 		var stop context.CancelFunc
 		ctx, stop = signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
@@ -96,8 +173,8 @@ func (m *Manager) Run(ctx context.Context) error {
 	}
 
 	g, gctx := errgroup.WithContext(ctx)
-	for _, s := range m.servers {
-		srv := s
+	for _, ms := range m.servers {
+		srv := ms.srv
 		g.Go(func() error {
 			name := safeName(srv)
 			m.cfg.Logger("INFO", "serve start", "name", name)
@@ -119,26 +196,35 @@ func (m *Manager) Run(ctx context.Context) error {
 
 	var groupDone bool
 	var groupErr error
+	var reason ShutdownReason
 
 	select {
 	case <-ctx.Done():
-		m.cfg.Logger("INFO", "context done; starting graceful stop")
+		reason = ReasonContext
+		if parent.Err() == nil {
+			reason = ReasonSignal
+		}
+		m.cfg.Logger("INFO", "context done; starting graceful stop", "reason", reason)
 	case err := <-waitCh:
 		groupDone, groupErr = true, err
 		if err != nil && !m.cfg.IsNormalError(err) {
+			reason = ReasonServeError
 			m.cfg.Logger("WARN", "group finished with error; starting graceful stop", "err", err)
 		} else {
+			reason = ReasonServeDone
 			m.cfg.Logger("INFO", "group finished; starting graceful stop")
 		}
 	}
 
-	m.Stop()
+	triggered := time.Now()
+	m.preStop(reason, triggered.Add(m.shutdownBudget()))
+	stopErr := m.stopWithDeadline(reason, triggered.Add(m.shutdownBudget()+m.cfg.PreStopDelay))
 
 	if groupDone {
 		if groupErr != nil && !m.cfg.IsNormalError(groupErr) {
 			return groupErr
 		}
-		return nil
+		return stopErr
 	}
 
 	select {
@@ -146,98 +232,185 @@ func (m *Manager) Run(ctx context.Context) error {
 		if err != nil && !m.cfg.IsNormalError(err) {
 			return err
 		}
-		return nil
-	case <-time.After(m.cfg.ShutdownTimeout + 2*time.Second):
-		return fmt.Errorf("graceful: wait group timeout after %s", m.cfg.ShutdownTimeout)
+		return stopErr
+	case <-time.After(m.shutdownBudget() + 2*time.Second):
+		return fmt.Errorf("graceful: wait group timeout after %s", m.shutdownBudget())
+	}
+}
+
+// LastRunForced reports whether any server was force-stopped during the last shutdown.
+// It is reset at the start of each Run and is safe to call concurrently.
+func (m *Manager) LastRunForced() bool {
+	return m.lastForced.Load()
+}
+
+// LastRunDuration returns how long the last graceful shutdown (Stop) took, 0 before it completes.
+// It is reset at the start of each Run and is safe to call concurrently.
+func (m *Manager) LastRunDuration() time.Duration {
+	return time.Duration(m.lastDuration.Load())
+}
+
+func (m *Manager) preStop(reason ShutdownReason, deadline time.Time) {
+	if m.cfg.PreStopHook != nil {
+		if m.shutdownBudget() <= 0 {
+			deadline = time.Now().Add(DefaultPreStopTimeout)
+		}
+		ctx, cancel := context.WithDeadline(WithReason(context.Background(), reason), deadline)
+		defer cancel()
+		m.cfg.Logger("INFO", "pre-stop hook start")
+		if err := m.cfg.PreStopHook(ctx); err != nil {
+			m.cfg.Logger("WARN", "pre-stop hook error", "err", err)
+		}
+	}
+	if m.cfg.PreStopDelay > 0 {
+		m.cfg.Logger("INFO", "pre-stop delay", "delay", m.cfg.PreStopDelay)
+		time.Sleep(m.cfg.PreStopDelay)
 	}
 }
 
 // Stop initiates graceful shutdown of all servers.
-// It is safe to call Stop multiple times; subsequent calls are no-ops.
+// It is safe to call Stop multiple times; subsequent calls are no-ops
+// and return the result of the first call.
+//
+// Servers are stopped phase by phase in ascending ServerOptions.Phase order:
+// a phase starts only after every server of the previous phase has stopped.
+// Servers within a phase are stopped concurrently.
 //
-// Each server is given ShutdownTimeout to stop gracefully.
+// Each server is given its own ShutdownTimeout (or Config.ShutdownTimeout),
+// counted from the start of its phase, to stop gracefully.
 // If a server doesn't stop in time, ForceStop is called.
 // Metrics are updated with success/force results.
-func (m *Manager) Stop() {
+//
+// Stop returns nil if every server stopped gracefully. Otherwise it returns
+// an errors.Join of per-server reasons, one for each force-stopped server.
+//
+// Servers see ReasonManual via ReasonFromContext; use StopWithReason to pass another one.
+func (m *Manager) Stop() error {
+	return m.StopWithReason(ReasonManual)
+}
+
+// StopWithReason is Stop with the reason passed to each server's graceful context.
+// Run calls it with the reason that triggered shutdown.
+func (m *Manager) StopWithReason(reason ShutdownReason) error {
+	return m.stopWithDeadline(reason, time.Now().Add(m.shutdownBudget()))
+}
+
+// stopWithDeadline caps every server's deadline at the overall shutdown deadline,
+// so time already spent by Run before Stop (PreStopHook) is not granted again.
+func (m *Manager) stopWithDeadline(reason ShutdownReason, shutdownDeadline time.Time) error {
 	m.mu.Lock()
+	defer m.mu.Unlock()
 	if m.stopped {
-		m.mu.Unlock()
-		return
+		return m.stopErr
 	}
 	m.stopped = true
-	m.mu.Unlock()
 
+	m.cfg.Logger("INFO", "shutdown start", "reason", reason)
 	started := time.Now()
-	var forcedAny atomic.Bool
-
-	// Глобальный дедлайн
-	globalCtx, globalCancel := context.WithTimeout(context.Background(), m.cfg.ShutdownTimeout)
-	defer globalCancel()
-
-	deadline, hasDeadline := globalCtx.Deadline()
-
-	// Вместо sync.WaitGroup — errgroup
-	g, _ := errgroup.WithContext(globalCtx)
-
-	for _, s := range m.servers {
-		srv := s
-		g.Go(func() error {
-			name := safeName(srv)
-
-			// Локальный контекст «остатка времени» для сервера
-			var srvCtx context.Context
-			var cancel context.CancelFunc
-			if hasDeadline {
-				srvCtx, cancel = context.WithDeadline(context.Background(), deadline)
-			} else {
-				srvCtx, cancel = context.WithCancel(context.Background())
+	var (
+		forcedMu sync.Mutex
+		forced   []error
+	)
+
+	for _, phase := range m.phases() {
+		phaseStarted := time.Now()
+		m.cfg.Logger("INFO", "shutdown phase start", "phase", phase.num, "servers", len(phase.servers))
+
+		// Вместо sync.WaitGroup — errgroup
+		var g errgroup.Group
+		for _, ms := range phase.servers {
+			deadline := phaseStarted.Add(m.shutdownTimeout(ms))
+			if deadline.After(shutdownDeadline) {
+				deadline = shutdownDeadline
 			}
-			defer cancel()
-
-			graceDone := make(chan error, 1)
-			go func() { graceDone <- srv.GracefulStopWithTimeout(srvCtx) }()
-
-			select {
-			case err := <-graceDone:
-				if err != nil {
-					m.cfg.Logger("WARN", "graceful stop error; forcing", "name", name, "err", err)
-					srv.ForceStop()
-					forcedAny.Store(true)
-					if m.cfg.Metrics != nil {
-						m.cfg.Metrics.IncServerStopResult(name, "force")
-					}
-					return nil
-				}
-
-				m.cfg.Logger("INFO", "graceful stop done", "name", name)
-				if m.cfg.Metrics != nil {
-					m.cfg.Metrics.IncServerStopResult(name, "success")
+			g.Go(func() error {
+				if err := m.stopServer(ms.srv, phase.num, deadline, reason); err != nil {
+					forcedMu.Lock()
+					forced = append(forced, err)
+					forcedMu.Unlock()
 				}
 				return nil
+			})
+		}
+		_ = g.Wait()
 
-			case <-srvCtx.Done():
-				m.cfg.Logger("WARN", "graceful stop timeout; forcing", "name", name, "err", srvCtx.Err())
-				srv.ForceStop()
-				forcedAny.Store(true)
-				if m.cfg.Metrics != nil {
-					m.cfg.Metrics.IncServerStopResult(name, "force")
-				}
-				return nil
-			}
-		})
+		m.cfg.Logger("INFO", "shutdown phase done", "phase", phase.num)
+		if pm, ok := m.cfg.Metrics.(PhaseMetrics); ok {
+			pm.ObservePhaseDuration(phase.num, time.Since(phaseStarted))
+		}
 	}
 
-	// Ждем завершения всех горутин
-	_ = g.Wait()
+	elapsed := time.Since(started)
+	m.lastForced.Store(len(forced) > 0)
+	m.lastDuration.Store(int64(elapsed))
 
 	if m.cfg.Metrics != nil {
-		m.cfg.Metrics.ObserveGracefulDuration(time.Since(started))
+		m.cfg.Metrics.ObserveGracefulDuration(elapsed)
 		result := "success"
-		if forcedAny.Load() {
+		if len(forced) > 0 {
 			result = "force"
 		}
 		m.cfg.Metrics.IncStopTotal(result)
 	}
+
+	m.stopErr = errors.Join(forced...)
+	return m.stopErr
+}
+
+func (m *Manager) stopServer(srv Server, phase int, deadline time.Time, reason ShutdownReason) (forced error) {
+	name := safeName(srv)
+
+	// Локальный контекст «остатка времени» для сервера; несёт причину остановки
+	srvCtx, cancel := context.WithDeadline(WithReason(context.Background(), reason), deadline)
+	defer cancel()
+
+	graceDone := make(chan error, 1)
+	go func() { graceDone <- srv.GracefulStopWithTimeout(srvCtx) }()
+
+	select {
+	case err := <-graceDone:
+		if err != nil {
+			m.cfg.Logger("WARN", "graceful stop error; forcing", "name", name, "phase", phase, "err", err)
+			m.forceStop(srv, name)
+			return fmt.Errorf("server %q force-stopped: graceful stop failed: %w", name, err)
+		}
+
+		m.cfg.Logger("INFO", "graceful stop done", "name", name, "phase", phase)
+		if m.cfg.Metrics != nil {
+			m.cfg.Metrics.IncServerStopResult(name, "success")
+		}
+		return nil
+
+	case <-srvCtx.Done():
+		m.cfg.Logger("WARN", "graceful stop timeout; forcing", "name", name, "phase", phase, "err", srvCtx.Err())
+		m.forceStop(srv, name)
+		return fmt.Errorf("server %q force-stopped: graceful stop timeout: %w", name, srvCtx.Err())
+	}
+}
+
+func (m *Manager) forceStop(srv Server, name string) {
+	srv.ForceStop()
+	if m.cfg.Metrics != nil {
+		m.cfg.Metrics.IncServerStopResult(name, "force")
+	}
+}
+
+type shutdownPhase struct {
+	num     int
+	servers []managedServer
+}
+
+func (m *Manager) phases() []shutdownPhase {
+	byNum := map[int][]managedServer{}
+	for _, ms := range m.servers {
+		byNum[ms.opt.Phase] = append(byNum[ms.opt.Phase], ms)
+	}
+	out := make([]shutdownPhase, 0, len(byNum))
+	for num, servers := range byNum {
+		out = append(out, shutdownPhase{num: num, servers: servers})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].num < out[j].num })
+	return out
 }
 
 // DefaultIsNormalErr reports whether an error is expected during normal shutdown.
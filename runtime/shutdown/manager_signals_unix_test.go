@@ -13,9 +13,8 @@ import (
 func Test_Manager_HandleSignals_SIGTERM_StopsRun(t *testing.T) {
 	t.Parallel()
 
-	// Фейковый сервер, который ждёт ctx.Done()
-	s := newFakeServer("waiter")
-	s.waitForCtx = true
+	// Фейковый сервер, который ждёт ctx.Done() и запоминает причину остановки
+	s := newReasonServer("waiter")
 
 	m := New(Config{
 		ShutdownTimeout: 300 * time.Millisecond,
@@ -46,4 +45,7 @@ func Test_Manager_HandleSignals_SIGTERM_StopsRun(t *testing.T) {
 	case <-time.After(3 * time.Second):
 		t.Fatal("Run did not stop after SIGTERM")
 	}
+	if got := s.got(); got != ReasonSignal {
+		t.Fatalf("reason = %q, want %q", got, ReasonSignal)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -288,8 +289,8 @@ func Test_Stop_ForceWhenGracefulBlocksForever(t *testing.T) {
 
 	select {
 	case err := <-done:
-		if err != nil {
-			t.Fatalf("expected nil, got %v", err)
+		if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected forced-stop error wrapping deadline exceeded, got %v", err)
 		}
 	case <-time.After(500 * time.Millisecond):
 		t.Fatal("Run did not finish when graceful stop blocked")
@@ -503,7 +504,359 @@ func Test_Stop_PerServerTimeouts_DoNotMislabelSuccess(t *testing.T) {
 	}
 }
 
+func Test_Stop_PerServerTimeouts_Overrides(t *testing.T) {
+	t.Parallel()
+
+	met := newFakeMetrics()
+	m := New(Config{ShutdownTimeout: 60 * time.Millisecond, Metrics: met})
+
+	fast := newFakeServer("metrics-http")
+	fast.waitForCtx = true
+	fast.graceDelay = 100 * time.Millisecond
+
+	drain := newFakeServer("kafka-consumer")
+	drain.waitForCtx = true
+	drain.graceDelay = 100 * time.Millisecond
+
+	fallback := newFakeServer("fallback")
+	fallback.waitForCtx = true
+	fallback.graceDelay = 20 * time.Millisecond
+
+	m.AddWithTimeout(fast, 30*time.Millisecond)
+	m.AddWithTimeout(drain, 400*time.Millisecond)
+	m.AddWithTimeout(fallback, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err == nil || !strings.Contains(err.Error(), `"metrics-http"`) {
+		t.Fatalf("expected forced-stop error for metrics-http, got %v", err)
+	}
+
+	if !fast.forced.Load() {
+		t.Fatal("expected server with short per-server timeout to be forced")
+	}
+	if drain.forced.Load() {
+		t.Fatal("server with long per-server timeout should outlive the global timeout")
+	}
+	if fallback.forced.Load() {
+		t.Fatal("server without override should use the global timeout and stop gracefully")
+	}
+	if got := met.serverStopResult["metrics-http"]["force"]; got != 1 {
+		t.Fatalf("expected metrics-http=force, got %d", got)
+	}
+	if got := met.serverStopResult["kafka-consumer"]["success"]; got != 1 {
+		t.Fatalf("expected kafka-consumer=success, got %d", got)
+	}
+	if got := met.serverStopResult["fallback"]["success"]; got != 1 {
+		t.Fatalf("expected fallback=success, got %d", got)
+	}
+}
+
+type phaseRecorder struct {
+	mu     sync.Mutex
+	starts map[string]time.Time
+	ends   map[string]time.Time
+}
+
+func (r *phaseRecorder) record(m map[string]time.Time, name string) {
+	r.mu.Lock()
+	m[name] = time.Now()
+	r.mu.Unlock()
+}
+
+type recordingServer struct {
+	*fakeServer
+	rec *phaseRecorder
+}
+
+func (s *recordingServer) GracefulStopWithTimeout(ctx context.Context) error {
+	s.rec.record(s.rec.starts, s.name)
+	defer s.rec.record(s.rec.ends, s.name)
+	return s.fakeServer.GracefulStopWithTimeout(ctx)
+}
+
+type fakePhaseMetrics struct {
+	*fakeMetrics
+	phases []int
+}
+
+func (m *fakePhaseMetrics) ObservePhaseDuration(phase int, _ time.Duration) {
+	m.mu.Lock()
+	m.phases = append(m.phases, phase)
+	m.mu.Unlock()
+}
+
+func Test_Stop_Phases_Ordered(t *testing.T) {
+	t.Parallel()
+
+	rec := &phaseRecorder{starts: map[string]time.Time{}, ends: map[string]time.Time{}}
+	met := &fakePhaseMetrics{fakeMetrics: newFakeMetrics()}
+	m := New(Config{ShutdownTimeout: 500 * time.Millisecond, Metrics: met})
+
+	newSrv := func(name string, delay time.Duration) *recordingServer {
+		s := newFakeServer(name)
+		s.waitForCtx = true
+		s.graceDelay = delay
+		return &recordingServer{fakeServer: s, rec: rec}
+	}
+
+	grpcA := newSrv("grpc-a", 40*time.Millisecond)
+	grpcB := newSrv("grpc-b", 10*time.Millisecond)
+	db := newSrv("db-pool", 10*time.Millisecond)
+	workers := newSrv("workers", 10*time.Millisecond)
+
+	m.AddWithOptions(workers, ServerOptions{Phase: 2})
+	m.AddWithOptions(db, ServerOptions{Phase: 1})
+	m.Add(grpcA)
+	m.Add(grpcB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	for _, name := range []string{"grpc-a", "grpc-b"} {
+		if rec.starts["db-pool"].Before(rec.ends[name]) {
+			t.Fatalf("phase 1 started before phase 0 server %s finished", name)
+		}
+	}
+	if rec.starts["workers"].Before(rec.ends["db-pool"]) {
+		t.Fatal("phase 2 started before phase 1 finished")
+	}
+	// Внутри фазы остановка конкурентная: grpc-b не ждёт grpc-a.
+	if rec.ends["grpc-b"].After(rec.ends["grpc-a"]) {
+		t.Fatal("expected servers within a phase to stop concurrently")
+	}
+
+	met.mu.Lock()
+	defer met.mu.Unlock()
+	if len(met.phases) != 3 || met.phases[0] != 0 || met.phases[1] != 1 || met.phases[2] != 2 {
+		t.Fatalf("expected phase durations for 0,1,2 in order, got %v", met.phases)
+	}
+}
+
+func Test_Stop_Phases_TimeoutCountedFromPhaseStart(t *testing.T) {
+	t.Parallel()
+
+	m := New(Config{ShutdownTimeout: 80 * time.Millisecond})
+
+	first := newFakeServer("first")
+	first.waitForCtx = true
+	first.graceDelay = 60 * time.Millisecond
+
+	second := newFakeServer("second")
+	second.waitForCtx = true
+	second.graceDelay = 60 * time.Millisecond
+
+	m.Add(first)
+	m.AddWithOptions(second, ServerOptions{Phase: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if first.forced.Load() || second.forced.Load() {
+		t.Fatal("each phase should get its own timeout budget")
+	}
+}
+
 // На всякий случай убеждаемся, что ошибка дедлайна действительно идёт как ошибка graceful
+func Test_Run_ReturnsAggregatedForcedErrors(t *testing.T) {
+	t.Parallel()
+	m := New(Config{ShutdownTimeout: 50 * time.Millisecond})
+
+	slow := newFakeServer("slow")
+	slow.waitForCtx = true
+	slow.graceDelay = time.Second
+	broken := newFakeServer("broken")
+	broken.waitForCtx = true
+	stopErr := errors.New("stop failed")
+	broken.graceErr = stopErr
+	ok := newFakeServer("ok")
+	ok.waitForCtx = true
+	m.Add(slow)
+	m.Add(broken)
+	m.Add(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan error, 1)
+	go func() { ch <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	err := <-ch
+	if err == nil {
+		t.Fatal("expected aggregated forced-stop error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, `"slow"`) || !strings.Contains(msg, `"broken"`) {
+		t.Fatalf("expected both forced servers in error, got %q", msg)
+	}
+	if strings.Contains(msg, `"ok"`) {
+		t.Fatalf("graceful server must not be reported, got %q", msg)
+	}
+	if !errors.Is(err, stopErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected reasons to be wrapped, got %v", err)
+	}
+	if again := m.Stop(); again == nil || again.Error() != msg {
+		t.Fatalf("expected repeated Stop to return the same error, got %v", again)
+	}
+}
+
+func Test_Stop_AllGraceful_ReturnsNil(t *testing.T) {
+	t.Parallel()
+	m := New(Config{ShutdownTimeout: 200 * time.Millisecond})
+	m.Add(newFakeServer("a"))
+	m.Add(newFakeServer("b"))
+
+	if err := m.Stop(); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func Test_Run_PreStopHook_RunsBeforeGracefulStop(t *testing.T) {
+	t.Parallel()
+
+	rec := &phaseRecorder{starts: map[string]time.Time{}, ends: map[string]time.Time{}}
+	var hookAt time.Time
+	var hookHasDeadline bool
+	const delay = 80 * time.Millisecond
+
+	m := New(Config{
+		ShutdownTimeout: 300 * time.Millisecond,
+		PreStopHook: func(ctx context.Context) error {
+			hookAt = time.Now()
+			_, hookHasDeadline = ctx.Deadline()
+			return errors.New("hook failed")
+		},
+		PreStopDelay: delay,
+	})
+
+	for _, name := range []string{"grpc", "http"} {
+		s := newFakeServer(name)
+		s.waitForCtx = true
+		m.Add(&recordingServer{fakeServer: s, rec: rec})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("hook error must not fail shutdown, got %v", err)
+	}
+	if hookAt.IsZero() {
+		t.Fatal("expected PreStopHook to be called")
+	}
+	if !hookHasDeadline {
+		t.Fatal("expected PreStopHook context to be bounded by the shutdown budget")
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	for _, name := range []string{"grpc", "http"} {
+		start, ok := rec.starts[name]
+		if !ok {
+			t.Fatalf("expected graceful stop for %s", name)
+		}
+		if got := start.Sub(hookAt); got < delay {
+			t.Fatalf("graceful stop of %s started %v after hook, want >= %v", name, got, delay)
+		}
+	}
+}
+
+type deadlineServer struct {
+	*fakeServer
+	deadline chan time.Time
+}
+
+func (s *deadlineServer) GracefulStopWithTimeout(ctx context.Context) error {
+	d, _ := ctx.Deadline()
+	s.deadline <- d
+	return s.fakeServer.GracefulStopWithTimeout(ctx)
+}
+
+func Test_Run_PreStopHook_SharesShutdownBudget(t *testing.T) {
+	t.Parallel()
+
+	const budget = 200 * time.Millisecond
+	var hookAt time.Time
+	m := New(Config{
+		ShutdownTimeout: budget,
+		PreStopHook: func(ctx context.Context) error {
+			hookAt = time.Now()
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	})
+	s := &deadlineServer{fakeServer: newFakeServer("grpc"), deadline: make(chan time.Time, 1)}
+	s.waitForCtx = true
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := (<-s.deadline).Sub(hookAt); got > budget {
+		t.Fatalf("server deadline is %v after hook start, want <= %v", got, budget)
+	}
+}
+
+func Test_Run_PreStopHook_ZeroShutdownTimeoutUsesDefault(t *testing.T) {
+	t.Parallel()
+
+	var remaining time.Duration
+	var hookErr error
+	m := New(Config{
+		PreStopHook: func(ctx context.Context) error {
+			d, _ := ctx.Deadline()
+			remaining, hookErr = time.Until(d), ctx.Err()
+			return nil
+		},
+	})
+	s := newFakeServer("grpc")
+	s.waitForCtx = true
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+
+	if hookErr != nil {
+		t.Fatalf("expected live hook context, got %v", hookErr)
+	}
+	if remaining <= DefaultPreStopTimeout-time.Second || remaining > DefaultPreStopTimeout {
+		t.Fatalf("hook deadline in %v, want about %v", remaining, DefaultPreStopTimeout)
+	}
+}
+
 func Test_fakeServer_GracefulDeadlineProducesError(t *testing.T) {
 	t.Parallel()
 	s := newFakeServer("x")
@@ -528,3 +881,69 @@ func Test_Add_NilServer_Ignored(t *testing.T) {
 		t.Fatalf("expected 0 servers, got %d", len(m.servers))
 	}
 }
+
+func Test_LastRun_ForcedSlowServer(t *testing.T) {
+	t.Parallel()
+	m := New(Config{ShutdownTimeout: 80 * time.Millisecond, Logger: func(string, string, ...any) {}})
+	s := newFakeServer("slow")
+	s.waitForCtx = true
+	s.graceDelay = 300 * time.Millisecond
+	m.Add(s)
+
+	if m.LastRunForced() || m.LastRunDuration() != 0 {
+		t.Fatal("expected zero values before Run")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	<-done
+
+	if !m.LastRunForced() {
+		t.Fatal("expected LastRunForced after forced stop")
+	}
+	if d := m.LastRunDuration(); d < 80*time.Millisecond {
+		t.Fatalf("LastRunDuration = %v, want >= shutdown timeout", d)
+	}
+}
+
+func Test_LastRun_NotForcedOnGracefulStop(t *testing.T) {
+	t.Parallel()
+	m := New(Config{ShutdownTimeout: 200 * time.Millisecond, Logger: func(string, string, ...any) {}})
+	s := newFakeServer("srv")
+	s.waitForCtx = true
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	// Concurrent readers while Run is in progress.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = m.LastRunForced()
+				_ = m.LastRunDuration()
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	wg.Wait()
+
+	if m.LastRunForced() {
+		t.Fatal("unexpected LastRunForced on graceful stop")
+	}
+	if m.LastRunDuration() <= 0 {
+		t.Fatal("expected LastRunDuration to be set after Run")
+	}
+}
@@ -3,6 +3,7 @@ package prommetrics
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,6 +16,7 @@ type PromMetrics struct {
 	serveErrors      *prometheus.CounterVec
 	serverStopResult *prometheus.CounterVec
 	gracefulDuration prometheus.Histogram
+	phaseDuration    *prometheus.HistogramVec
 }
 
 func registerCollector(reg prometheus.Registerer, c prometheus.Collector) error {
@@ -36,6 +38,7 @@ func registerCollector(reg prometheus.Registerer, c prometheus.Collector) error
 //   - {namespace}_{subsystem}_server_serve_errors_total{name} - counter of non-normal serve errors
 //   - {namespace}_{subsystem}_server_stop_result_total{name, result} - per-server stop result
 //   - {namespace}_{subsystem}_graceful_duration_seconds - histogram of shutdown duration
+//   - {namespace}_{subsystem}_phase_duration_seconds{phase} - histogram of per-phase shutdown duration
 //
 // Returns error if reg is nil or if registration fails (except AlreadyRegisteredError).
 func New(reg prometheus.Registerer, namespace, subsystem string) (*PromMetrics, error) {
@@ -50,6 +53,13 @@ func New(reg prometheus.Registerer, namespace, subsystem string) (*PromMetrics,
 		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
 	})
 
+	phaseHist := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: subsystem,
+		Name:    "phase_duration_seconds",
+		Help:    "Duration of a single shutdown phase",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+	}, []string{"phase"})
+
 	pm := &PromMetrics{
 		stopTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace, Subsystem: subsystem,
@@ -62,6 +72,7 @@ func New(reg prometheus.Registerer, namespace, subsystem string) (*PromMetrics,
 		}, []string{"name"}),
 
 		gracefulDuration: hist,
+		phaseDuration:    phaseHist,
 
 		serverStopResult: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: namespace, Subsystem: subsystem,
@@ -69,7 +80,7 @@ func New(reg prometheus.Registerer, namespace, subsystem string) (*PromMetrics,
 		}, []string{"name", "result"}),
 	}
 
-	for _, c := range []prometheus.Collector{pm.stopTotal, pm.serveErrors, pm.serverStopResult, hist} {
+	for _, c := range []prometheus.Collector{pm.stopTotal, pm.serveErrors, pm.serverStopResult, hist, phaseHist} {
 		if err := registerCollector(reg, c); err != nil {
 			return nil, err
 		}
@@ -93,3 +104,7 @@ func (p *PromMetrics) IncServeError(name string) {
 func (p *PromMetrics) IncServerStopResult(name, result string) {
 	p.serverStopResult.WithLabelValues(name, result).Inc()
 }
+
+func (p *PromMetrics) ObservePhaseDuration(phase int, d time.Duration) {
+	p.phaseDuration.WithLabelValues(strconv.Itoa(phase)).Observe(d.Seconds())
+}
@@ -0,0 +1,33 @@
+package shutdown
+
+import "context"
+
+// ShutdownReason tells servers what triggered shutdown.
+type ShutdownReason string
+
+const (
+	// ReasonManual: Stop was called directly, not by Run.
+	ReasonManual ShutdownReason = "manual"
+	// ReasonSignal: Run received SIGINT or SIGTERM (Config.HandleSignals).
+	ReasonSignal ShutdownReason = "signal"
+	// ReasonContext: the context passed to Run was cancelled.
+	ReasonContext ShutdownReason = "context"
+	// ReasonServeError: a server's Serve returned a non-normal error.
+	ReasonServeError ShutdownReason = "serve_error"
+	// ReasonServeDone: every Serve returned without a non-normal error.
+	ReasonServeDone ShutdownReason = "serve_done"
+)
+
+type reasonKey struct{}
+
+// WithReason returns a copy of ctx carrying reason.
+func WithReason(ctx context.Context, reason ShutdownReason) context.Context {
+	return context.WithValue(ctx, reasonKey{}, reason)
+}
+
+// ReasonFromContext returns the shutdown reason from the context passed to
+// GracefulStopWithTimeout or PreStopHook; "" if ctx carries none.
+func ReasonFromContext(ctx context.Context) ShutdownReason {
+	r, _ := ctx.Value(reasonKey{}).(ShutdownReason)
+	return r
+}
@@ -0,0 +1,132 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// reasonServer records the shutdown reason seen by GracefulStopWithTimeout.
+type reasonServer struct {
+	*fakeServer
+	mu     sync.Mutex
+	reason ShutdownReason
+}
+
+func (s *reasonServer) GracefulStopWithTimeout(ctx context.Context) error {
+	s.mu.Lock()
+	s.reason = ReasonFromContext(ctx)
+	s.mu.Unlock()
+	return s.fakeServer.GracefulStopWithTimeout(ctx)
+}
+
+func (s *reasonServer) got() ShutdownReason {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reason
+}
+
+func newReasonServer(name string) *reasonServer {
+	s := newFakeServer(name)
+	s.waitForCtx = true
+	return &reasonServer{fakeServer: s}
+}
+
+func Test_Reason_ServeError(t *testing.T) {
+	t.Parallel()
+
+	var hookReason ShutdownReason
+	m := New(Config{
+		ShutdownTimeout: 200 * time.Millisecond,
+		PreStopHook: func(ctx context.Context) error {
+			hookReason = ReasonFromContext(ctx)
+			return nil
+		},
+	})
+	peer := newReasonServer("consumer")
+	bad := newFakeServer("bad")
+	bad.serveErr = errors.New("listener failed")
+	m.Add(peer)
+	m.Add(bad)
+
+	if err := m.Run(context.Background()); !errors.Is(err, bad.serveErr) {
+		t.Fatalf("expected serve error, got %v", err)
+	}
+	if got := peer.got(); got != ReasonServeError {
+		t.Fatalf("server reason = %q, want %q", got, ReasonServeError)
+	}
+	if hookReason != ReasonServeError {
+		t.Fatalf("PreStopHook reason = %q, want %q", hookReason, ReasonServeError)
+	}
+}
+
+func Test_Reason_ContextCancel(t *testing.T) {
+	t.Parallel()
+
+	m := New(Config{ShutdownTimeout: 200 * time.Millisecond})
+	s := newReasonServer("srv")
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got := s.got(); got != ReasonContext {
+		t.Fatalf("reason = %q, want %q", got, ReasonContext)
+	}
+}
+
+func Test_Reason_ServeDone(t *testing.T) {
+	t.Parallel()
+
+	m := New(Config{ShutdownTimeout: 200 * time.Millisecond})
+	s := newReasonServer("srv")
+	s.waitForCtx = false // Serve returns nil right away
+	m.Add(s)
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if got := s.got(); got != ReasonServeDone {
+		t.Fatalf("reason = %q, want %q", got, ReasonServeDone)
+	}
+}
+
+func Test_Reason_ManualStopAndStopWithReason(t *testing.T) {
+	t.Parallel()
+
+	m := New(Config{ShutdownTimeout: 100 * time.Millisecond})
+	s := newReasonServer("srv")
+	m.Add(s)
+	if err := m.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if got := s.got(); got != ReasonManual {
+		t.Fatalf("reason = %q, want %q", got, ReasonManual)
+	}
+
+	m = New(Config{ShutdownTimeout: 100 * time.Millisecond})
+	s = newReasonServer("srv")
+	m.Add(s)
+	if err := m.StopWithReason("deploy"); err != nil {
+		t.Fatalf("StopWithReason: %v", err)
+	}
+	if got := s.got(); got != "deploy" {
+		t.Fatalf("reason = %q, want deploy", got)
+	}
+}
+
+func Test_ReasonFromContext_Absent(t *testing.T) {
+	t.Parallel()
+
+	if got := ReasonFromContext(context.Background()); got != "" {
+		t.Fatalf("expected empty reason, got %q", got)
+	}
+}
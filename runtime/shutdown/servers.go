@@ -0,0 +1,39 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/vortex-fintech/go-lib/runtime/shutdown/adapters"
+	"google.golang.org/grpc"
+)
+
+// HTTPServer adapts srv to Server. If ln is nil, srv.ListenAndServe is used.
+// An empty name defaults to "http". Serve returns nil for http.ErrServerClosed,
+// GracefulStopWithTimeout calls Shutdown and ForceStop calls Close.
+func HTTPServer(name string, srv *http.Server, ln net.Listener) Server {
+	return normalServe{&adapters.HTTP{Srv: srv, Lis: ln, NameStr: name}}
+}
+
+// GRPCServer adapts srv to Server; ln is required. An empty name defaults to "grpc".
+// Serve returns nil for grpc.ErrServerStopped, GracefulStopWithTimeout calls GracefulStop
+// bounded by ctx and ForceStop calls Stop.
+func GRPCServer(name string, srv *grpc.Server, ln net.Listener) Server {
+	return normalServe{&adapters.GRPC{Srv: srv, Lis: ln, NameStr: name}}
+}
+
+// normalServe hides the "server closed" errors from Serve, so the adapters behave the same
+// with a custom Config.IsNormalError.
+type normalServe struct {
+	Server
+}
+
+func (s normalServe) Serve(ctx context.Context) error {
+	err := s.Server.Serve(ctx)
+	if errors.Is(err, http.ErrServerClosed) || errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
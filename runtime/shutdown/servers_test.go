@@ -0,0 +1,143 @@
+package shutdown
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func listenLocal(t *testing.T) net.Listener {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	return ln
+}
+
+func Test_HTTPServer_ViaManager_ServesAndStopsGracefully(t *testing.T) {
+	t.Parallel()
+
+	ln := listenLocal(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte("ok")) })
+
+	s := HTTPServer("http-api", &http.Server{Handler: mux}, ln)
+	if s.Name() != "http-api" {
+		t.Fatalf("Name() = %q", s.Name())
+	}
+
+	m := New(Config{ShutdownTimeout: time.Second, Logger: func(string, string, ...any) {}})
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	client := http.Client{Timeout: time.Second}
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get("http://" + ln.Addr().String() + "/ok"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("http get: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if string(body) != "ok" {
+		t.Fatalf("body = %q", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not finish after cancel")
+	}
+}
+
+func Test_GRPCServer_ViaManager_StopsGracefully(t *testing.T) {
+	t.Parallel()
+
+	ln := listenLocal(t)
+	s := GRPCServer("", grpc.NewServer(), ln)
+	if s.Name() != "grpc" {
+		t.Fatalf("Name() = %q, want default", s.Name())
+	}
+
+	m := New(Config{ShutdownTimeout: time.Second, Logger: func(string, string, ...any) {}})
+	m.Add(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	conn, err := net.DialTimeout("tcp", ln.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	_ = conn.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run returned error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not finish after cancel")
+	}
+}
+
+func Test_Adapters_ServeClosedIsNormal(t *testing.T) {
+	t.Parallel()
+
+	httpSrv := &http.Server{}
+	_ = httpSrv.Close()
+	if err := HTTPServer("h", httpSrv, listenLocal(t)).Serve(context.Background()); err != nil {
+		t.Fatalf("http Serve after Close: %v", err)
+	}
+
+	grpcSrv := grpc.NewServer()
+	grpcSrv.Stop()
+	if err := GRPCServer("g", grpcSrv, listenLocal(t)).Serve(context.Background()); err != nil {
+		t.Fatalf("grpc Serve after Stop: %v", err)
+	}
+}
+
+func Test_Adapters_ForceStop(t *testing.T) {
+	t.Parallel()
+
+	ln := listenLocal(t)
+	s := HTTPServer("h", &http.Server{Handler: http.NewServeMux()}, ln)
+
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- s.Serve(context.Background()) }()
+	time.Sleep(50 * time.Millisecond)
+
+	s.ForceStop()
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Fatalf("Serve after ForceStop: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after ForceStop")
+	}
+}
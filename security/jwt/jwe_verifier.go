@@ -0,0 +1,144 @@
+package jwt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// ErrDecryptFailed — JWE не расшифровался: чужой ключ, подменённый ciphertext/tag/заголовок.
+// Причина намеренно не уточняется (см. RFC 7516, 11.5).
+var ErrDecryptFailed = errors.New("jwt: jwe decrypt failed")
+
+// maxJWESize — предел для compact JWE: вложенный JWS до 16 KiB плюс base64 и заголовки.
+const maxJWESize = 32 * 1024
+
+// jweHeader — защищённый заголовок JWE (RFC 7516).
+type jweHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+	Cty string `json:"cty,omitempty"`
+	Zip string `json:"zip,omitempty"`
+}
+
+type jweVerifier struct {
+	key   *rsa.PrivateKey
+	inner Verifier
+}
+
+// NewJWEVerifier оборачивает inner: токены из 5 сегментов (compact JWE) расшифровываются
+// ключом decryptKey и передаются в inner как вложенный JWT; остальные токены уходят в inner
+// без изменений. Поддерживаются alg RSA-OAEP и RSA-OAEP-256 с enc A256GCM; zip не поддерживается.
+//
+// Close и X5tS256 делегируются inner.
+func NewJWEVerifier(decryptKey *rsa.PrivateKey, inner Verifier) (Verifier, error) {
+	if decryptKey == nil {
+		return nil, errors.New("jwt: nil jwe decrypt key")
+	}
+	if inner == nil {
+		return nil, errors.New("jwt: nil inner verifier")
+	}
+	return &jweVerifier{key: decryptKey, inner: inner}, nil
+}
+
+func (v *jweVerifier) Verify(ctx context.Context, raw string) (*Claims, error) {
+	if strings.Count(raw, ".") != 4 {
+		return v.inner.Verify(ctx, raw)
+	}
+	jws, err := v.decrypt(raw)
+	if err != nil {
+		return nil, err
+	}
+	return v.inner.Verify(ctx, string(jws))
+}
+
+func (v *jweVerifier) Close() error { return CloseVerifier(v.inner) }
+
+func (v *jweVerifier) X5tS256(kid string) (string, bool) {
+	if src, ok := v.inner.(X5tS256Source); ok {
+		return src.X5tS256(kid)
+	}
+	return "", false
+}
+
+// decrypt разбирает compact JWE: header.encrypted_key.iv.ciphertext.tag.
+func (v *jweVerifier) decrypt(raw string) ([]byte, error) {
+	if len(raw) > maxJWESize {
+		return nil, newVerifyError(ErrMalformed, "jwt: invalid size")
+	}
+	parts := strings.Split(raw, ".")
+
+	hdrJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, wrapVerifyError(ErrMalformed, err)
+	}
+	var hdr jweHeader
+	if err := json.Unmarshal(hdrJSON, &hdr); err != nil {
+		return nil, wrapVerifyError(ErrMalformed, err)
+	}
+
+	var oaepHash hash.Hash
+	switch hdr.Alg {
+	case "RSA-OAEP":
+		oaepHash = sha1.New()
+	case "RSA-OAEP-256":
+		oaepHash = sha256.New()
+	default:
+		return nil, newVerifyError(ErrUnexpectedAlg, fmt.Sprintf("jwt: unexpected jwe alg %q", hdr.Alg))
+	}
+	if hdr.Enc != "A256GCM" {
+		return nil, newVerifyError(ErrUnexpectedAlg, fmt.Sprintf("jwt: unexpected jwe enc %q", hdr.Enc))
+	}
+	if hdr.Zip != "" {
+		return nil, newVerifyError(ErrMalformed, "jwt: jwe zip is not supported")
+	}
+	if hdr.Cty != "" && !strings.EqualFold(hdr.Cty, "JWT") {
+		return nil, newVerifyError(ErrMalformed, fmt.Sprintf("jwt: unexpected jwe cty %q", hdr.Cty))
+	}
+
+	var seg [4][]byte // encrypted_key, iv, ciphertext, tag
+	for i := range seg {
+		if seg[i], err = base64.RawURLEncoding.DecodeString(parts[i+1]); err != nil {
+			return nil, wrapVerifyError(ErrMalformed, err)
+		}
+	}
+	encKey, iv, ciphertext, tag := seg[0], seg[1], seg[2], seg[3]
+	if len(iv) != 12 || len(tag) != 16 {
+		return nil, newVerifyError(ErrMalformed, "jwt: bad jwe iv or tag length")
+	}
+
+	// При ошибке OAEP продолжаем со случайным CEK, чтобы по ответу и времени
+	// нельзя было отличить сбой key unwrap от сбоя GCM.
+	cek, err := rsa.DecryptOAEP(oaepHash, nil, v.key, encKey, nil)
+	if err != nil || len(cek) != 32 {
+		cek = make([]byte, 32)
+		if _, err := rand.Read(cek); err != nil {
+			return nil, err
+		}
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// AAD — ASCII(BASE64URL(protected header)), см. RFC 7516, 5.2.
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+	return plaintext, nil
+}
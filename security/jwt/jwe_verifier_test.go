@@ -0,0 +1,214 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"strings"
+	"testing"
+)
+
+// encryptJWE шифрует plaintext в compact JWE (alg RSA-OAEP / RSA-OAEP-256, enc из header).
+func encryptJWE(t *testing.T, pub *rsa.PublicKey, header map[string]string, plaintext string) string {
+	t.Helper()
+
+	var h hash.Hash = sha1.New()
+	if header["alg"] == "RSA-OAEP-256" {
+		h = sha256.New()
+	}
+	cek := make([]byte, 32)
+	iv := make([]byte, 12)
+	if _, err := rand.Read(cek); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rand.Read(iv); err != nil {
+		t.Fatal(err)
+	}
+	encKey, err := rsa.EncryptOAEP(h, rand.Reader, pub, cek, nil)
+	if err != nil {
+		t.Fatalf("EncryptOAEP: %v", err)
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hEnc := base64.RawURLEncoding.EncodeToString(hb)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := gcm.Seal(nil, iv, []byte(plaintext), []byte(hEnc))
+	ct, tag := sealed[:len(sealed)-16], sealed[len(sealed)-16:]
+
+	enc := base64.RawURLEncoding.EncodeToString
+	return strings.Join([]string{hEnc, enc(encKey), enc(iv), enc(ct), enc(tag)}, ".")
+}
+
+func newJWETestVerifier(t *testing.T) (v Verifier, signKey, decKey *rsa.PrivateKey) {
+	t.Helper()
+
+	signKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	decKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	inner, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &signKey.PublicKey}, WithStaticIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+	v, err = NewJWEVerifier(decKey, inner)
+	if err != nil {
+		t.Fatalf("NewJWEVerifier: %v", err)
+	}
+	return v, signKey, decKey
+}
+
+func TestJWEVerifier_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	v, signKey, decKey := newJWETestVerifier(t)
+	jws, err := signedTokenRS256("kid-a", signKey)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+
+	for _, alg := range []string{"RSA-OAEP", "RSA-OAEP-256"} {
+		raw := encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": alg, "enc": "A256GCM", "cty": "JWT"}, jws)
+		if n := strings.Count(raw, "."); n != 4 {
+			t.Fatalf("%s: expected 5 segments, got %d", alg, n+1)
+		}
+		cl, err := v.Verify(context.Background(), raw)
+		if err != nil {
+			t.Fatalf("%s: Verify: %v", alg, err)
+		}
+		if cl.Subject != "550e8400-e29b-41d4-a716-446655440000" || cl.Issuer != "issuer" {
+			t.Fatalf("%s: unexpected claims %+v", alg, cl)
+		}
+	}
+
+	// Обычный JWS проходит в inner без изменений.
+	if _, err := v.Verify(context.Background(), jws); err != nil {
+		t.Fatalf("plain JWS: %v", err)
+	}
+}
+
+func TestJWEVerifier_InnerChecksStillApply(t *testing.T) {
+	t.Parallel()
+
+	v, _, decKey := newJWETestVerifier(t)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := signedTokenRS256("kid-a", other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": "RSA-OAEP", "enc": "A256GCM"}, forged)
+	if _, err := v.Verify(context.Background(), raw); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected ErrBadSignature from inner, got %v", err)
+	}
+}
+
+func TestJWEVerifier_Rejects(t *testing.T) {
+	t.Parallel()
+
+	v, signKey, decKey := newJWETestVerifier(t)
+	jws, err := signedTokenRS256("kid-a", signKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hdr := map[string]string{"alg": "RSA-OAEP", "enc": "A256GCM"}
+	good := encryptJWE(t, &decKey.PublicKey, hdr, jws)
+	parts := strings.Split(good, ".")
+	with := func(i int, seg string) string {
+		p := append([]string(nil), parts...)
+		p[i] = seg
+		return strings.Join(p, ".")
+	}
+	flip := func(seg string) string {
+		b, _ := base64.RawURLEncoding.DecodeString(seg)
+		b[0] ^= 0xff
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	wrongKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamperedHdr, _ := json.Marshal(map[string]string{"alg": "RSA-OAEP", "enc": "A256GCM", "kid": "x"})
+
+	cases := []struct {
+		name string
+		raw  string
+		want error
+	}{
+		{"bad header base64", with(0, "!!"), ErrMalformed},
+		{"bad iv base64", with(2, "!!"), ErrMalformed},
+		{"short iv", with(2, base64.RawURLEncoding.EncodeToString([]byte("short"))), ErrMalformed},
+		{"empty tag", with(4, ""), ErrMalformed},
+		{"tampered ciphertext", with(3, flip(parts[3])), ErrDecryptFailed},
+		{"tampered tag", with(4, flip(parts[4])), ErrDecryptFailed},
+		{"tampered header", with(0, base64.RawURLEncoding.EncodeToString(tamperedHdr)), ErrDecryptFailed},
+		{"tampered key", with(1, flip(parts[1])), ErrDecryptFailed},
+		{"wrong recipient", encryptJWE(t, &wrongKey.PublicKey, hdr, jws), ErrDecryptFailed},
+		{"unsupported alg", encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": "RSA1_5", "enc": "A256GCM"}, jws), ErrUnexpectedAlg},
+		{"unsupported enc", encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": "RSA-OAEP", "enc": "A128CBC-HS256"}, jws), ErrUnexpectedAlg},
+		{"zip", encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": "RSA-OAEP", "enc": "A256GCM", "zip": "DEF"}, jws), ErrMalformed},
+		{"non-JWT cty", encryptJWE(t, &decKey.PublicKey, map[string]string{"alg": "RSA-OAEP", "enc": "A256GCM", "cty": "json"}, jws), ErrMalformed},
+		{"garbage plaintext", encryptJWE(t, &decKey.PublicKey, hdr, "not-a-jwt"), ErrMalformed},
+		{"oversized", with(3, strings.Repeat("A", maxJWESize)), ErrMalformed},
+	}
+	for _, tc := range cases {
+		if _, err := v.Verify(context.Background(), tc.raw); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestNewJWEVerifier_Errors(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inner, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &key.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWEVerifier(nil, inner); err == nil {
+		t.Fatal("expected error for nil key")
+	}
+	if _, err := NewJWEVerifier(key, nil); err == nil {
+		t.Fatal("expected error for nil inner verifier")
+	}
+
+	v, err := NewJWEVerifier(key, inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := CloseVerifier(v); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, ok := v.(X5tS256Source).X5tS256("kid-a"); ok {
+		t.Fatal("static inner verifier has no x5t")
+	}
+}
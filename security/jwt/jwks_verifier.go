@@ -4,8 +4,12 @@ package jwt
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
@@ -16,9 +20,54 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Ошибки Verify. Исходные тексты ошибок сохранены, а классифицировать их можно
+// через errors.Is (в т.ч. с ErrExpired и ErrIATInFuture из verifier.go).
+var (
+	ErrVerifierClosed = errors.New("jwt: verifier closed")
+	ErrMalformed      = errors.New("jwt: malformed")
+	ErrUnknownKID     = errors.New("jwt: unknown kid")
+	ErrUnexpectedAlg  = errors.New("jwt: unexpected alg")
+	ErrAlgNone        = errors.New("jwt: alg none")
+	ErrUnexpectedTyp  = errors.New("jwt: unexpected typ")
+	ErrBadSignature   = errors.New("jwt: bad signature")
+	ErrIssuerMismatch = errors.New("jwt: unexpected iss")
+)
+
+// verifyError сохраняет исходное сообщение, но матчится на sentinel (kind)
+// и на первопричину (cause), если она есть.
+type verifyError struct {
+	kind  error
+	msg   string
+	cause error
+}
+
+func (e *verifyError) Error() string { return e.msg }
+
+func (e *verifyError) Unwrap() []error {
+	if e.cause == nil {
+		return []error{e.kind}
+	}
+	return []error{e.kind, e.cause}
+}
+
+func newVerifyError(kind error, msg string) error {
+	return &verifyError{kind: kind, msg: msg}
+}
+
+// wrapVerifyError классифицирует err как kind, не меняя текст.
+// Уже классифицированные ошибки возвращаются как есть.
+func wrapVerifyError(kind, err error) error {
+	var ve *verifyError
+	if errors.As(err, &ve) {
+		return err
+	}
+	return &verifyError{kind: kind, msg: err.Error(), cause: err}
+}
+
 // JWKS-клиент с in-memory кэшем + поддержкой Cache-Control/ETag.
 type JWKSConfig struct {
 	URL            string        // https://sso.internal/.well-known/jwks.json
@@ -26,15 +75,48 @@ type JWKSConfig struct {
 	Timeout        time.Duration // HTTP timeout для JWKS-запроса
 	ExpectedIssuer string        // опциональная проверка iss
 	Leeway         time.Duration // опциональный leeway для iat/exp (если 0 => 5s)
+	MaxLeeway      time.Duration // верхняя граница Leeway (если 0 => 5m)
+
+	// RejectFutureNBF — разбирать nbf (в Claims его нет) и отклонять токены
+	// с nbf > now + leeway (ErrNBFInFuture). По умолчанию nbf игнорируется.
+	RejectFutureNBF bool
+
+	// Clock — источник времени для проверок exp/iat (по умолчанию time.Now).
+	// Например, timeutil.NewFrozenClock(t).Now в тестах. На расписание
+	// обновления JWKS не влияет.
+	Clock func() time.Time
+
+	// BackgroundRefresh — обновлять ключи в отдельной горутине, а не внутри Verify.
+	// Verify при этом никогда не ходит в сеть; неизвестный kid лишь ускоряет
+	// следующий фоновый refresh. Горутина останавливается в Close.
+	BackgroundRefresh bool
+
+	// TLSClientConfig — TLS-настройки транспорта JWKS-запросов: клиентский
+	// сертификат для mTLS и/или RootCAs приватного CA (например, из
+	// mtls.TLSConfigClient). Конфиг клонируется; nil — настройки по умолчанию.
+	TLSClientConfig *tls.Config
+
+	// AllowedTypes — допустимые значения заголовка typ (без учёта регистра).
+	// Пусто => DefaultAllowedTypes, токен без typ при этом принимается.
+	// Если список задан явно, токен без typ отклоняется (ErrUnexpectedTyp).
+	AllowedTypes []string
 }
 
+// DefaultAllowedTypes — значения typ, принимаемые по умолчанию (RFC 7519, RFC 9068).
+var DefaultAllowedTypes = []string{"JWT", "at+jwt"}
+
 type jwk struct {
 	Kty string `json:"kty"`
 	Kid string `json:"kid"`
 	N   string `json:"n"`
 	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
 	Alg string `json:"alg"`
 	Use string `json:"use"`
+
+	X5c []string `json:"x5c,omitempty"` // base64 DER, leaf первым
 }
 
 type jwks struct {
@@ -44,75 +126,229 @@ type jwks struct {
 type jwksVerifier struct {
 	cfg         JWKSConfig
 	mu          sync.RWMutex
-	rsa         map[string]*rsa.PublicKey // kid -> key
+	rsa         map[string]*rsa.PublicKey   // kid -> key
+	ec          map[string]*ecdsa.PublicKey // kid -> key
+	x5t         map[string]string           // kid -> x5t#S256 leaf-сертификата из x5c
 	httpClient  *http.Client
+	transport   *http.Transport
 	nextRefresh time.Time
 	etag        string
+	closed      atomic.Bool
+
+	// background refresh
+	kick   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
+// NewJWKSVerifier создаёт JWKS-верификатор и сразу загружает ключи.
+// Возвращаемый Verifier реализует io.Closer: Close освобождает idle-соединения
+// HTTP-транспорта (см. CloseVerifier).
 func NewJWKSVerifier(cfg JWKSConfig) (Verifier, error) {
 	tr := &http.Transport{
 		MaxIdleConns:        100,
 		IdleConnTimeout:     90 * time.Second,
 		TLSHandshakeTimeout: 5 * time.Second,
 	}
+	if cfg.TLSClientConfig != nil {
+		tr.TLSClientConfig = cfg.TLSClientConfig.Clone()
+	}
 	if cfg.Timeout <= 0 {
 		cfg.Timeout = 5 * time.Second
 	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
 	v := &jwksVerifier{
 		cfg: cfg,
 		rsa: make(map[string]*rsa.PublicKey),
+		ec:  make(map[string]*ecdsa.PublicKey),
 		httpClient: &http.Client{
 			Timeout:   cfg.Timeout,
 			Transport: tr,
 		},
+		transport: tr,
 	}
 	if err := v.refresh(context.Background()); err != nil {
+		tr.CloseIdleConnections()
 		return nil, err
 	}
+	if cfg.BackgroundRefresh {
+		ctx, cancel := context.WithCancel(context.Background())
+		v.kick = make(chan struct{}, 1)
+		v.cancel = cancel
+		v.done = make(chan struct{})
+		go v.backgroundLoop(ctx)
+	}
 	return v, nil
 }
 
+// Close помечает верификатор закрытым и закрывает idle-соединения.
+// Повторный вызов безопасен.
+func (v *jwksVerifier) Close() error {
+	if v.closed.Swap(true) {
+		return nil
+	}
+	if v.cancel != nil {
+		v.cancel()
+		<-v.done
+	}
+	v.transport.CloseIdleConnections()
+	return nil
+}
+
+// backgroundLoop обновляет ключи по расписанию (nextRefresh учитывает
+// Cache-Control) или по запросу из keyFor. Между попытками выдерживается
+// минимальный интервал, чтобы поток неизвестных kid не превращался в DoS на JWKS.
+// При ошибках подряд интервал удваивается до интервала обновления и
+// сбрасывается после первого успешного обновления.
+func (v *jwksVerifier) backgroundLoop(ctx context.Context) {
+	defer close(v.done)
+
+	interval := v.refreshIntervalFromHeaders(nil)
+	gap := min(time.Second, interval)
+	spacing := gap
+	last := time.Now()
+	for {
+		wait := time.Until(v.nextRefreshAt())
+		timer := time.NewTimer(max(wait, spacing-time.Since(last)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-v.kick:
+			timer.Stop()
+			if d := spacing - time.Since(last); d > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(d):
+				}
+			}
+		case <-timer.C:
+		}
+
+		last = time.Now()
+		if err := v.refresh(ctx); err != nil {
+			v.mu.Lock()
+			v.nextRefresh = time.Now().Add(spacing)
+			v.mu.Unlock()
+			spacing = refreshBackoff(spacing, interval)
+			continue
+		}
+		spacing = gap
+	}
+}
+
+// refreshBackoff удваивает интервал после неудачного обновления, не выше limit.
+func refreshBackoff(cur, limit time.Duration) time.Duration {
+	if cur >= limit/2 {
+		return limit
+	}
+	return cur * 2
+}
+
+func (v *jwksVerifier) requestRefresh() {
+	select {
+	case v.kick <- struct{}{}:
+	default:
+	}
+}
+
 func (v *jwksVerifier) Verify(ctx context.Context, raw string) (*Claims, error) {
 	ctx = ensureContext(ctx)
 
-	// мягкий refresh
-	if time.Now().After(v.nextRefreshAt()) {
+	if v.closed.Load() {
+		return nil, ErrVerifierClosed
+	}
+
+	// мягкий refresh (в фоновом режиме этим занимается backgroundLoop)
+	if !v.cfg.BackgroundRefresh && time.Now().After(v.nextRefreshAt()) {
 		_ = v.refresh(ctx)
 	}
 
-	if l := len(raw); l == 0 || l > 16*1024 {
-		return nil, errors.New("jwt: invalid size")
+	return verifyToken(ctx, raw, v.keyFor, verifyParams{
+		issuer:          v.cfg.ExpectedIssuer,
+		leeway:          v.cfg.Leeway,
+		maxLeeway:       v.cfg.MaxLeeway,
+		rejectFutureNBF: v.cfg.RejectFutureNBF,
+		clock:           v.cfg.Clock,
+		allowedTypes:    v.cfg.AllowedTypes,
+	})
+}
+
+// verifyParams — проверки claims, общие для всех Verifier.
+type verifyParams struct {
+	issuer          string
+	leeway          time.Duration // <= 0 => 5s
+	maxLeeway       time.Duration // <= 0 => 5m
+	rejectFutureNBF bool
+	clock           func() time.Time
+	allowedTypes    []string // пусто => DefaultAllowedTypes
+}
+
+// typAllowed сравнивает typ без учёта регистра (RFC 7515, 4.1.9).
+// Отсутствующий typ допустим, только если allowedTypes не задан явно.
+func (p verifyParams) typAllowed(typ string) bool {
+	allowed := p.allowedTypes
+	if len(allowed) == 0 {
+		if typ == "" {
+			return true
+		}
+		allowed = DefaultAllowedTypes
+	}
+	for _, a := range allowed {
+		if typ != "" && strings.EqualFold(typ, a) {
+			return true
+		}
 	}
+	return false
+}
 
-	parts := strings.Split(raw, ".")
-	if len(parts) != 3 {
-		return nil, errors.New("jwt: malformed")
+const (
+	defaultLeeway    = 5 * time.Second
+	defaultMaxLeeway = 5 * time.Minute
+)
+
+// effectiveLeeway применяет значения по умолчанию и ограничивает leeway сверху,
+// чтобы опечатка в конфиге (например, 5h вместо 5s) не отключала проверку exp.
+func (p verifyParams) effectiveLeeway() time.Duration {
+	leeway := p.leeway
+	if leeway <= 0 {
+		leeway = defaultLeeway
 	}
+	maxLeeway := p.maxLeeway
+	if maxLeeway <= 0 {
+		maxLeeway = defaultMaxLeeway
+	}
+	return min(leeway, maxLeeway)
+}
 
-	// Header
-	hdrJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+// verifyToken разбирает JWS, проверяет подпись ключом из keyFor и время/iss.
+func verifyToken(ctx context.Context, raw string, keyFor func(context.Context, string) (crypto.PublicKey, error), p verifyParams) (*Claims, error) {
+	parts, hdr, err := splitToken(raw)
 	if err != nil {
 		return nil, err
 	}
-	var hdr struct {
-		Kid string `json:"kid"`
-		Alg string `json:"alg"`
-		Typ string `json:"typ"`
-	}
-	if err := json.Unmarshal(hdrJSON, &hdr); err != nil {
-		return nil, err
+	// alg=none отклоняем явно (для аудита); errors.Is(err, ErrUnexpectedAlg) тоже true.
+	if strings.EqualFold(hdr.Alg, "none") {
+		return nil, &verifyError{kind: ErrAlgNone, msg: "jwt: alg none is not allowed", cause: ErrUnexpectedAlg}
 	}
 	if hdr.Kid == "" {
-		return nil, errors.New("jwt: no kid")
+		return nil, newVerifyError(ErrMalformed, "jwt: no kid")
 	}
-	// Разрешаем RS256 и PS256
-	if hdr.Alg != "RS256" && hdr.Alg != "PS256" {
-		return nil, errors.New("jwt: unexpected alg")
+	// Разрешаем RS256, PS256, ES256 и ES384
+	switch hdr.Alg {
+	case "RS256", "PS256", "ES256", "ES384":
+	default:
+		return nil, ErrUnexpectedAlg
+	}
+	if !p.typAllowed(hdr.Typ) {
+		return nil, newVerifyError(ErrUnexpectedTyp, fmt.Sprintf("jwt: unexpected typ %q", hdr.Typ))
 	}
 
 	// Ключ по kid
-	key, err := v.keyFor(ctx, hdr.Kid)
+	key, err := keyFor(ctx, hdr.Kid)
 	if err != nil {
 		return nil, err
 	}
@@ -120,76 +356,146 @@ func (v *jwksVerifier) Verify(ctx context.Context, raw string) (*Claims, error)
 	signed := parts[0] + "." + parts[1]
 	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
 	if err != nil {
-		return nil, err
+		return nil, wrapVerifyError(ErrMalformed, err)
 	}
 	switch hdr.Alg {
-	case "RS256":
-		if err := verifyRS256(key, []byte(signed), sig); err != nil {
-			return nil, err
+	case "RS256", "PS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, newVerifyError(ErrUnexpectedAlg, "jwt: key type mismatch")
 		}
-	case "PS256":
-		if err := verifyPS256(key, []byte(signed), sig); err != nil {
-			return nil, err
+		if hdr.Alg == "RS256" {
+			err = verifyRS256(pub, []byte(signed), sig)
+		} else {
+			err = verifyPS256(pub, []byte(signed), sig)
+		}
+		if err != nil {
+			return nil, wrapVerifyError(ErrBadSignature, err)
+		}
+	case "ES256", "ES384":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, newVerifyError(ErrUnexpectedAlg, "jwt: key type mismatch")
+		}
+		if err := verifyES(hdr.Alg, pub, []byte(signed), sig); err != nil {
+			return nil, wrapVerifyError(ErrBadSignature, err)
 		}
 	}
 
 	// Payload
 	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return nil, err
+		return nil, wrapVerifyError(ErrMalformed, err)
 	}
 	cl, err := decodeClaims(payload)
 	if err != nil {
-		return nil, err
+		return nil, wrapVerifyError(ErrMalformed, err)
 	}
 
 	// Time checks (leeway)
-	leeway := v.cfg.Leeway
-	if leeway <= 0 {
-		leeway = 5 * time.Second
-	}
-	now := time.Now()
+	leeway := p.effectiveLeeway()
+	now := p.clock()
 	if now.Add(-leeway).After(cl.ExpiresAt()) {
-		return nil, errors.New("jwt: expired")
+		return nil, newVerifyError(ErrExpired, "jwt: expired")
 	}
 	if cl.Iat > now.Add(leeway).Unix() {
-		return nil, errors.New("jwt: iat in the future")
+		return nil, ErrIATInFuture
+	}
+	if p.rejectFutureNBF {
+		nbf, ok, err := decodeNBF(payload)
+		if err != nil {
+			return nil, wrapVerifyError(ErrMalformed, err)
+		}
+		if ok && nbf > now.Add(leeway).Unix() {
+			return nil, ErrNBFInFuture
+		}
 	}
 
 	// Optional issuer check
-	if v.cfg.ExpectedIssuer != "" && cl.Issuer != v.cfg.ExpectedIssuer {
-		return nil, errors.New("jwt: unexpected iss")
+	if p.issuer != "" && cl.Issuer != p.issuer {
+		return nil, ErrIssuerMismatch
 	}
 
 	return cl, nil
 }
 
-func (v *jwksVerifier) keyFor(ctx context.Context, kid string) (*rsa.PublicKey, error) {
-	ctx = ensureContext(ctx)
+// splitToken проверяет размер, делит JWS на 3 сегмента и декодирует заголовок.
+// Общая часть Verify и ParseUnverified: некорректный токен отклоняется одинаково.
+func splitToken(raw string) ([]string, Header, error) {
+	if l := len(raw); l == 0 || l > 16*1024 {
+		return nil, Header{}, newVerifyError(ErrMalformed, "jwt: invalid size")
+	}
 
-	v.mu.RLock()
-	k := v.rsa[kid]
-	v.mu.RUnlock()
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, Header{}, ErrMalformed
+	}
+
+	hdrJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, Header{}, wrapVerifyError(ErrMalformed, err)
+	}
+	var hdr Header
+	if err := json.Unmarshal(hdrJSON, &hdr); err != nil {
+		return nil, Header{}, wrapVerifyError(ErrMalformed, err)
+	}
+	return parts, hdr, nil
+}
+
+// keyFor возвращает *rsa.PublicKey или *ecdsa.PublicKey по kid.
+func (v *jwksVerifier) keyFor(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	ctx = ensureContext(ctx)
 
-	if k != nil {
+	if k := v.lookup(kid); k != nil {
 		return k, nil
 	}
 
 	// Unknown kid can mean key rotation happened before next scheduled refresh.
+	if v.cfg.BackgroundRefresh {
+		v.requestRefresh()
+		return nil, ErrUnknownKID
+	}
 	_ = v.refresh(ctx)
-	v.mu.RLock()
-	k = v.rsa[kid]
-	v.mu.RUnlock()
-	if k != nil {
+	if k := v.lookup(kid); k != nil {
 		return k, nil
 	}
 
-	return nil, errors.New("jwt: unknown kid")
+	return nil, ErrUnknownKID
+}
+
+// X5tS256Source — опциональное расширение Verifier: x5t#S256 leaf-сертификата
+// из x5c по kid. Реализуется верификатором из NewJWKSVerifier.
+type X5tS256Source interface {
+	X5tS256(kid string) (string, bool)
+}
+
+// X5tS256 возвращает x5t#S256 (см. X5tS256FromCert) для ключа, опубликованного с x5c.
+func (v *jwksVerifier) X5tS256(kid string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	th, ok := v.x5t[kid]
+	return th, ok
+}
+
+func (v *jwksVerifier) lookup(kid string) crypto.PublicKey {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if k := v.rsa[kid]; k != nil {
+		return k
+	}
+	if k := v.ec[kid]; k != nil {
+		return k
+	}
+	return nil
 }
 
 func (v *jwksVerifier) refresh(ctx context.Context) error {
 	ctx = ensureContext(ctx)
 
+	if v.closed.Load() {
+		return ErrVerifierClosed
+	}
 	if v.cfg.URL == "" {
 		return errors.New("jwks: empty url")
 	}
@@ -224,55 +530,198 @@ func (v *jwksVerifier) refresh(ctx context.Context) error {
 	}
 
 	m := make(map[string]*rsa.PublicKey, len(set.Keys))
+	ecm := make(map[string]*ecdsa.PublicKey)
+	x5t := make(map[string]string)
 	for _, k := range set.Keys {
-		if k.Kty != "RSA" {
-			continue
-		}
 		if k.Use != "" && k.Use != "sig" {
 			continue
 		}
-		if k.Alg != "" && k.Alg != "RS256" && k.Alg != "PS256" {
-			continue
-		}
-		if k.Kid == "" || k.N == "" || k.E == "" {
+		if k.Kid == "" {
 			continue
 		}
 
-		nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
-		if err != nil {
-			continue
-		}
-		eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
-		if err != nil {
-			continue
-		}
-		if len(nBytes) == 0 {
-			continue
+		// x5c: ключ берётся из leaf-сертификата, если n/e (x/y) не заданы.
+		var leaf *x509.Certificate
+		if len(k.X5c) > 0 {
+			var ok bool
+			if leaf, ok = parseX5C(k.X5c); !ok {
+				continue
+			}
 		}
 
-		eBig := new(big.Int).SetBytes(eBytes)
-		if !eBig.IsInt64() {
+		switch k.Kty {
+		case "RSA":
+			pub, ok := parseRSAKey(k, leaf)
+			if !ok {
+				continue
+			}
+			m[k.Kid] = pub
+		case "EC":
+			pub, ok := parseECKey(k, leaf)
+			if !ok {
+				continue
+			}
+			ecm[k.Kid] = pub
+		default:
 			continue
 		}
-		e := int(eBig.Int64())
-		if e < 3 || e%2 == 0 {
-			continue
+		if leaf != nil {
+			x5t[k.Kid] = X5tS256FromCert(leaf)
 		}
-
-		m[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: e}
 	}
-	if len(m) == 0 {
-		return errors.New("jwks: no valid rsa keys")
+	if len(m) == 0 && len(ecm) == 0 {
+		return errors.New("jwks: no valid keys")
 	}
 
 	v.mu.Lock()
 	v.rsa = m
+	v.ec = ecm
+	v.x5t = x5t
 	v.etag = resp.Header.Get("ETag")
 	v.nextRefresh = time.Now().Add(v.refreshIntervalFromHeaders(resp.Header))
 	v.mu.Unlock()
 	return nil
 }
 
+// parseX5C разбирает цепочку x5c (base64 DER, leaf первым) и проверяет,
+// что каждый сертификат подписан следующим. Возвращает leaf.
+func parseX5C(chain []string) (*x509.Certificate, bool) {
+	certs := make([]*x509.Certificate, 0, len(chain))
+	for _, c := range chain {
+		der, err := base64.StdEncoding.DecodeString(c)
+		if err != nil {
+			return nil, false
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, false
+		}
+		certs = append(certs, cert)
+	}
+	for i := 0; i+1 < len(certs); i++ {
+		if err := certs[i].CheckSignatureFrom(certs[i+1]); err != nil {
+			return nil, false
+		}
+	}
+	return certs[0], true
+}
+
+// parseRSAKey разбирает JWK с kty=RSA. Если n/e не заданы — ключ берётся из leaf;
+// если заданы вместе с x5c — ключи обязаны совпадать.
+func parseRSAKey(k jwk, leaf *x509.Certificate) (*rsa.PublicKey, bool) {
+	if k.Alg != "" && k.Alg != "RS256" && k.Alg != "PS256" {
+		return nil, false
+	}
+
+	var leafKey *rsa.PublicKey
+	if leaf != nil {
+		var ok bool
+		if leafKey, ok = leaf.PublicKey.(*rsa.PublicKey); !ok {
+			return nil, false
+		}
+	}
+	if k.N == "" && k.E == "" && leafKey != nil {
+		return leafKey, true
+	}
+	if k.N == "" || k.E == "" {
+		return nil, false
+	}
+
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, false
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, false
+	}
+	if len(nBytes) == 0 {
+		return nil, false
+	}
+
+	eBig := new(big.Int).SetBytes(eBytes)
+	if !eBig.IsInt64() {
+		return nil, false
+	}
+	e := int(eBig.Int64())
+	if e < 3 || e%2 == 0 {
+		return nil, false
+	}
+
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: e}
+	if leafKey != nil && !leafKey.Equal(pub) {
+		return nil, false
+	}
+	return pub, true
+}
+
+// parseECKey разбирает JWK с kty=EC (P-256/P-384). Несовместимые alg/crv и
+// точки вне кривой отбрасываются. Если x/y не заданы — ключ берётся из leaf.
+func parseECKey(k jwk, leaf *x509.Certificate) (*ecdsa.PublicKey, bool) {
+	var leafKey *ecdsa.PublicKey
+	if leaf != nil {
+		var ok bool
+		if leafKey, ok = leaf.PublicKey.(*ecdsa.PublicKey); !ok {
+			return nil, false
+		}
+	}
+
+	crv := k.Crv
+	if crv == "" && leafKey != nil {
+		crv = leafKey.Curve.Params().Name
+	}
+
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		if k.Alg != "" && k.Alg != "ES256" {
+			return nil, false
+		}
+		curve = elliptic.P256()
+	case "P-384":
+		if k.Alg != "" && k.Alg != "ES384" {
+			return nil, false
+		}
+		curve = elliptic.P384()
+	default:
+		return nil, false
+	}
+
+	if k.X == "" && k.Y == "" && leafKey != nil {
+		if leafKey.Curve != curve {
+			return nil, false
+		}
+		return leafKey, true
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, false
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, false
+	}
+	size := (curve.Params().BitSize + 7) / 8
+	if len(xBytes) != size || len(yBytes) != size {
+		return nil, false
+	}
+
+	// Проверка точки на кривой через несжатое представление (0x04 || X || Y).
+	uncompressed := make([]byte, 0, 1+2*size)
+	uncompressed = append(uncompressed, 4)
+	uncompressed = append(uncompressed, xBytes...)
+	uncompressed = append(uncompressed, yBytes...)
+	pub, err := ecdsa.ParseUncompressedPublicKey(curve, uncompressed)
+	if err != nil {
+		return nil, false
+	}
+	if leafKey != nil && !leafKey.Equal(pub) {
+		return nil, false
+	}
+	return pub, true
+}
+
 func (v *jwksVerifier) nextRefreshAt() time.Time {
 	v.mu.RLock()
 	next := v.nextRefresh
@@ -313,6 +762,24 @@ func parseMaxAge(cc string) (time.Duration, bool) {
 	return 0, false
 }
 
+// decodeNBF достаёт nbf (NumericDate, допускается дробная часть); ok=false, если его нет.
+func decodeNBF(payload []byte) (nbf int64, ok bool, err error) {
+	var w struct {
+		Nbf *json.Number `json:"nbf"`
+	}
+	if err := json.Unmarshal(payload, &w); err != nil {
+		return 0, false, err
+	}
+	if w.Nbf == nil {
+		return 0, false, nil
+	}
+	f, err := w.Nbf.Float64()
+	if err != nil {
+		return 0, false, fmt.Errorf("jwt: bad nbf: %w", err)
+	}
+	return int64(f), true, nil
+}
+
 // decodeClaims — БЕЗ legacy "scope": принимает только "scopes" как массив строк.
 // Добавлена дедупликация scopes.
 func decodeClaims(payload []byte) (*Claims, error) {
@@ -325,6 +792,8 @@ func decodeClaims(payload []byte) (*Claims, error) {
 		Sid      string   `json:"sid,omitempty"`
 		Jti      string   `json:"jti,omitempty"`
 		Scopes   any      `json:"scopes,omitempty"`
+		Roles    any      `json:"roles,omitempty"`
+		Groups   any      `json:"groups,omitempty"`
 		Azp      string   `json:"azp,omitempty"`
 		ACR      string   `json:"acr,omitempty"`
 		AMR      []string `json:"amr,omitempty"`
@@ -371,38 +840,61 @@ func decodeClaims(payload []byte) (*Claims, error) {
 		cl.Audience = append(cl.Audience, v...)
 	}
 
-	// Дедупликация scopes
-	appendUnique := func(s string, seen map[string]struct{}) {
+	var ok bool
+	if cl.Scopes, ok = uniqueStrings(w.Scopes, false); !ok {
+		return nil, errors.New("jwt: scopes must be array of strings")
+	}
+	if cl.Roles, ok = uniqueStrings(w.Roles, true); !ok {
+		return nil, errors.New("jwt: roles must be string or array of strings")
+	}
+	if cl.Groups, ok = uniqueStrings(w.Groups, true); !ok {
+		return nil, errors.New("jwt: groups must be string or array of strings")
+	}
+
+	return cl, nil
+}
+
+// uniqueStrings разбирает claim-список: массив строк (не-строки пропускаются) или,
+// при allowString, строку через пробел. Значения тримятся, пустые и дубли отбрасываются.
+// ok=false — неподдерживаемый тип.
+func uniqueStrings(v any, allowString bool) (out []string, ok bool) {
+	seen := make(map[string]struct{})
+	add := func(s string) {
 		s = strings.TrimSpace(s)
 		if s == "" {
 			return
 		}
-		if _, ok := seen[s]; ok {
+		if _, dup := seen[s]; dup {
 			return
 		}
-		cl.Scopes = append(cl.Scopes, s)
 		seen[s] = struct{}{}
+		out = append(out, s)
 	}
-	seen := make(map[string]struct{})
 
-	switch v := w.Scopes.(type) {
+	switch v := v.(type) {
 	case nil:
 		// ок
+	case string:
+		if !allowString {
+			return nil, false
+		}
+		for _, s := range strings.Fields(v) {
+			add(s)
+		}
 	case []string:
 		for _, s := range v {
-			appendUnique(s, seen)
+			add(s)
 		}
 	case []any:
 		for _, it := range v {
-			if s, ok := it.(string); ok {
-				appendUnique(s, seen)
+			if s, isStr := it.(string); isStr {
+				add(s)
 			}
 		}
 	default:
-		return nil, errors.New("jwt: scopes must be array of strings")
+		return nil, false
 	}
-
-	return cl, nil
+	return out, true
 }
 
 func verifyRS256(pub *rsa.PublicKey, payload, sig []byte) error {
@@ -416,6 +908,38 @@ func verifyPS256(pub *rsa.PublicKey, payload, sig []byte) error {
 	return rsa.VerifyPSS(pub, crypto.SHA256, h[:], sig, opts)
 }
 
+// verifyES проверяет подпись ES256/ES384 в JWS-формате: r||s фиксированной
+// длины (не ASN.1).
+func verifyES(alg string, pub *ecdsa.PublicKey, payload, sig []byte) error {
+	var digest []byte
+	var size int
+	switch alg {
+	case "ES256":
+		if pub.Curve != elliptic.P256() {
+			return newVerifyError(ErrUnexpectedAlg, "jwt: curve mismatch")
+		}
+		h := sha256.Sum256(payload)
+		digest, size = h[:], 32
+	case "ES384":
+		if pub.Curve != elliptic.P384() {
+			return newVerifyError(ErrUnexpectedAlg, "jwt: curve mismatch")
+		}
+		h := sha512.Sum384(payload)
+		digest, size = h[:], 48
+	default:
+		return ErrUnexpectedAlg
+	}
+	if len(sig) != 2*size {
+		return errors.New("jwt: bad ecdsa signature length")
+	}
+	r := new(big.Int).SetBytes(sig[:size])
+	s := new(big.Int).SetBytes(sig[size:])
+	if !ecdsa.Verify(pub, digest, r, s) {
+		return errors.New("jwt: ecdsa verification error")
+	}
+	return nil
+}
+
 func X5tS256FromCert(cert *x509.Certificate) string {
 	if cert == nil {
 		return ""
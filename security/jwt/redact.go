@@ -0,0 +1,77 @@
+package jwt
+
+import "unicode/utf8"
+
+// RedactMode — способ маскирования чувствительных полей в RedactClaims.
+type RedactMode int
+
+const (
+	// RedactFull заменяет значение на "[REDACTED]".
+	RedactFull RedactMode = iota
+	// RedactLast4 оставляет последние 4 символа ("****0000"); короткие значения скрываются полностью.
+	RedactLast4
+)
+
+const redacted = "[REDACTED]"
+
+// RedactClaims возвращает карту для логирования: sub, sid, wallet_id и device_id
+// маскируются согласно mode, остальное (iss, aud, scopes, roles, groups, exp, ...) остаётся как есть.
+// Пустые опциональные поля опускаются. Для nil возвращает nil.
+func RedactClaims(c *Claims, mode RedactMode) map[string]any {
+	if c == nil {
+		return nil
+	}
+
+	out := map[string]any{
+		"iss": c.Issuer,
+		"sub": redactValue(c.Subject, mode),
+		"aud": c.Audience,
+		"iat": c.Iat,
+		"exp": c.Exp,
+	}
+	if len(c.Scopes) > 0 {
+		out["scopes"] = c.EffectiveScopes()
+	}
+	if len(c.Roles) > 0 {
+		out["roles"] = c.Roles
+	}
+	if len(c.Groups) > 0 {
+		out["groups"] = c.Groups
+	}
+	putString := func(k, v string) {
+		if v != "" {
+			out[k] = v
+		}
+	}
+	putString("jti", c.Jti)
+	putString("azp", c.Azp)
+	putString("acr", c.ACR)
+	if len(c.AMR) > 0 {
+		out["amr"] = c.AMR
+	}
+	if c.Act != nil {
+		putString("act", c.Act.Sub)
+	}
+	if c.Sid != "" {
+		out["sid"] = redactValue(c.Sid, mode)
+	}
+	if c.WalletID != "" {
+		out["wallet_id"] = redactValue(c.WalletID, mode)
+	}
+	if c.DeviceID != "" {
+		out["device_id"] = redactValue(c.DeviceID, mode)
+	}
+	return out
+}
+
+func redactValue(v string, mode RedactMode) string {
+	if mode != RedactLast4 {
+		return redacted
+	}
+	n := utf8.RuneCountInString(v)
+	if n <= 4 {
+		return redacted
+	}
+	r := []rune(v)
+	return "****" + string(r[n-4:])
+}
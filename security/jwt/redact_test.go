@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func redactTestClaims() *Claims {
+	return &Claims{
+		Issuer:   "https://sso.vortex.internal",
+		Subject:  "550e8400-e29b-41d4-a716-446655440000",
+		Audience: []string{"wallet"},
+		Iat:      100,
+		Exp:      400,
+		Sid:      "session-123456",
+		Jti:      "jti-1",
+		Scopes:   []string{"wallet:read", "payments:create"},
+		Roles:    []string{"support"},
+		Groups:   []string{"ops"},
+		Act:      &Actor{Sub: "api-gateway"},
+		WalletID: "w-98765",
+		DeviceID: "dev",
+	}
+}
+
+func TestRedactClaims_Full(t *testing.T) {
+	t.Parallel()
+
+	got := RedactClaims(redactTestClaims(), RedactFull)
+	for _, k := range []string{"sub", "sid", "wallet_id", "device_id"} {
+		if got[k] != "[REDACTED]" {
+			t.Fatalf("%s = %v, want [REDACTED]", k, got[k])
+		}
+	}
+	if !reflect.DeepEqual(got["scopes"], []string{"payments:create", "wallet:read"}) {
+		t.Fatalf("scopes not preserved: %v", got["scopes"])
+	}
+	if !reflect.DeepEqual(got["roles"], []string{"support"}) || !reflect.DeepEqual(got["groups"], []string{"ops"}) {
+		t.Fatalf("roles/groups not preserved: %v %v", got["roles"], got["groups"])
+	}
+	if got["iss"] != "https://sso.vortex.internal" || got["exp"] != int64(400) || got["act"] != "api-gateway" {
+		t.Fatalf("debug fields not preserved: %v", got)
+	}
+	if !reflect.DeepEqual(got["aud"], []string{"wallet"}) {
+		t.Fatalf("aud not preserved: %v", got["aud"])
+	}
+}
+
+func TestRedactClaims_Last4(t *testing.T) {
+	t.Parallel()
+
+	got := RedactClaims(redactTestClaims(), RedactLast4)
+	want := map[string]string{
+		"sub":       "****0000",
+		"sid":       "****3456",
+		"wallet_id": "****8765",
+		"device_id": "[REDACTED]", // короче 5 символов
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("%s = %v, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestRedactClaims_OmitsEmptyOptional(t *testing.T) {
+	t.Parallel()
+
+	got := RedactClaims(&Claims{Subject: "x"}, RedactFull)
+	for _, k := range []string{"scopes", "roles", "groups", "sid", "wallet_id", "device_id", "jti", "act"} {
+		if _, ok := got[k]; ok {
+			t.Fatalf("unexpected key %q in %v", k, got)
+		}
+	}
+	if RedactClaims(nil, RedactFull) != nil {
+		t.Fatal("expected nil for nil claims")
+	}
+}
@@ -0,0 +1,61 @@
+// go-lib/security/jwt/replay_guard.go
+package jwt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ReplayGuard — атомарный anti-replay стор: проверяет и запоминает jti за один вызов.
+// seen=true означает, что jti уже встречался (replay). exp — момент, после которого
+// токен перестаёт приниматься; дольше хранить jti не нужно.
+type ReplayGuard interface {
+	ObserveJTI(ctx context.Context, jti string, exp time.Time) (seen bool, err error)
+}
+
+var errNilRedisClient = errors.New("jwt: redis client is nil")
+
+type RedisReplayGuardOptions struct {
+	// Префикс ключа в Redis, по умолчанию "obo:jti".
+	Prefix string
+	// MinTTL — нижняя граница TTL ключа (exp уже мог наступить в пределах leeway).
+	// По умолчанию 1s.
+	MinTTL time.Duration
+}
+
+// RedisReplayGuard — ReplayGuard на SET NX с TTL до exp токена.
+type RedisReplayGuard struct {
+	rdb    redis.UniversalClient
+	prefix string
+	minTTL time.Duration
+}
+
+func NewRedisReplayGuard(rdb redis.UniversalClient, opt RedisReplayGuardOptions) *RedisReplayGuard {
+	prefix := opt.Prefix
+	if prefix == "" {
+		prefix = "obo:jti"
+	}
+	minTTL := opt.MinTTL
+	if minTTL <= 0 {
+		minTTL = time.Second
+	}
+	return &RedisReplayGuard{rdb: rdb, prefix: prefix, minTTL: minTTL}
+}
+
+func (g *RedisReplayGuard) ObserveJTI(ctx context.Context, jti string, exp time.Time) (bool, error) {
+	if g == nil || g.rdb == nil {
+		return true, errNilRedisClient
+	}
+	ctx = ensureContext(ctx)
+
+	ttl := max(time.Until(exp), g.minTTL)
+	ok, err := g.rdb.SetNX(ctx, g.prefix+":"+jti, 1, ttl).Result()
+	if err != nil {
+		return true, err
+	}
+	// SetNX вернул false => ключ уже был => это replay.
+	return !ok, nil
+}
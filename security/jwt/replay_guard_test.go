@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memoryReplayGuard struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+	err  error
+}
+
+func (g *memoryReplayGuard) ObserveJTI(_ context.Context, jti string, exp time.Time) (bool, error) {
+	if g.err != nil {
+		return true, g.err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.seen == nil {
+		g.seen = make(map[string]time.Time)
+	}
+	if _, ok := g.seen[jti]; ok {
+		return true, nil
+	}
+	g.seen[jti] = exp
+	return false, nil
+}
+
+func replayClaims() *Claims {
+	return &Claims{
+		Subject:  "550e8400-e29b-41d4-a716-446655440000",
+		Audience: []string{"wallet"},
+		Act:      &Actor{Sub: "api-gateway"},
+		Jti:      "jti-concurrent",
+		Iat:      time.Now().Unix(),
+		Exp:      time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestValidateOBO_ReplayGuard_ConcurrentSameJTI(t *testing.T) {
+	t.Parallel()
+
+	guard := &memoryReplayGuard{}
+	opt := OBOValidateOptions{WantAudience: "wallet", ReplayGuard: guard}
+
+	const workers = 16
+	var ok, replay int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := ValidateOBOContext(context.Background(), time.Now(), replayClaims(), opt)
+			switch {
+			case err == nil:
+				atomic.AddInt32(&ok, 1)
+			case errors.Is(err, ErrReplay):
+				atomic.AddInt32(&replay, 1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if ok != 1 || replay != workers-1 {
+		t.Fatalf("expected exactly one accepted token, got ok=%d replay=%d", ok, replay)
+	}
+}
+
+func TestValidateOBO_ReplayGuard_TakesPrecedenceOverSeenJTI(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	err := ValidateOBO(time.Now(), replayClaims(), OBOValidateOptions{
+		WantAudience: "wallet",
+		ReplayGuard:  &memoryReplayGuard{},
+		SeenJTI: func(string) bool {
+			called = true
+			return true
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected OK, got %v", err)
+	}
+	if called {
+		t.Fatal("SeenJTI must not be called when ReplayGuard is set")
+	}
+}
+
+func TestValidateOBO_ReplayGuard_StoreError(t *testing.T) {
+	t.Parallel()
+
+	storeErr := errors.New("connection refused")
+	err := ValidateOBO(time.Now(), replayClaims(), OBOValidateOptions{
+		WantAudience: "wallet",
+		ReplayGuard:  &memoryReplayGuard{err: storeErr},
+	})
+	if !errors.Is(err, ErrReplayStore) {
+		t.Fatalf("expected ErrReplayStore, got %v", err)
+	}
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("expected underlying store error to be wrapped, got %v", err)
+	}
+}
+
+func TestValidateOBO_ReplayGuard_NotCalledForInvalidToken(t *testing.T) {
+	t.Parallel()
+
+	guard := &memoryReplayGuard{}
+	cl := replayClaims()
+	cl.WalletID = "wallet-a"
+
+	err := ValidateOBO(time.Now(), cl, OBOValidateOptions{
+		WantAudience: "wallet",
+		WantWalletID: "wallet-b",
+		ReplayGuard:  guard,
+	})
+	if err != ErrWalletMismatch {
+		t.Fatalf("expected ErrWalletMismatch, got %v", err)
+	}
+	if len(guard.seen) != 0 {
+		t.Fatal("jti must not be recorded for a token that failed validation")
+	}
+}
+
+func TestRedisReplayGuard_NilClient(t *testing.T) {
+	t.Parallel()
+
+	g := NewRedisReplayGuard(nil, RedisReplayGuardOptions{})
+	seen, err := g.ObserveJTI(context.Background(), "jti-1", time.Now().Add(time.Minute))
+	if err == nil {
+		t.Fatal("expected error for nil redis client")
+	}
+	if !seen {
+		t.Fatal("expected fail-closed seen=true for nil redis client")
+	}
+
+	err = ValidateOBO(time.Now(), replayClaims(), OBOValidateOptions{WantAudience: "wallet", ReplayGuard: g})
+	if !errors.Is(err, ErrReplayStore) {
+		t.Fatalf("expected ErrReplayStore, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// StaticOption настраивает верификатор из NewStaticVerifier.
+type StaticOption func(*staticVerifier)
+
+// WithStaticIssuer — ожидаемый iss (как JWKSConfig.ExpectedIssuer).
+func WithStaticIssuer(iss string) StaticOption {
+	return func(v *staticVerifier) { v.params.issuer = iss }
+}
+
+// WithStaticLeeway — leeway для iat/exp (как JWKSConfig.Leeway, 0 => 5s, не больше 5m).
+func WithStaticLeeway(d time.Duration) StaticOption {
+	return func(v *staticVerifier) { v.params.leeway = d }
+}
+
+// WithStaticRejectFutureNBF — отклонять токены с nbf в будущем (как JWKSConfig.RejectFutureNBF).
+func WithStaticRejectFutureNBF() StaticOption {
+	return func(v *staticVerifier) { v.params.rejectFutureNBF = true }
+}
+
+// WithStaticAllowedTypes — допустимые значения typ (как JWKSConfig.AllowedTypes).
+func WithStaticAllowedTypes(types ...string) StaticOption {
+	return func(v *staticVerifier) { v.params.allowedTypes = types }
+}
+
+// WithStaticClock — источник времени для проверок exp/iat (по умолчанию time.Now).
+func WithStaticClock(now func() time.Time) StaticOption {
+	return func(v *staticVerifier) {
+		if now != nil {
+			v.params.clock = now
+		}
+	}
+}
+
+type staticVerifier struct {
+	keys   map[string]crypto.PublicKey
+	params verifyParams
+}
+
+// NewStaticVerifier создаёт Verifier с фиксированным набором ключей kid -> key
+// (*rsa.PublicKey или *ecdsa.PublicKey) без обращения к сети — для тестов и
+// изолированных окружений. Алгоритмы и проверки exp/iat/iss те же, что у
+// NewJWKSVerifier; неизвестный kid даёт ErrUnknownKID. Карта копируется.
+func NewStaticVerifier(keys map[string]crypto.PublicKey, opts ...StaticOption) (Verifier, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: no static keys")
+	}
+	v := &staticVerifier{
+		keys:   make(map[string]crypto.PublicKey, len(keys)),
+		params: verifyParams{clock: time.Now},
+	}
+	for kid, k := range keys {
+		if kid == "" {
+			return nil, errors.New("jwt: static key with empty kid")
+		}
+		switch k := k.(type) {
+		case *rsa.PublicKey:
+			if k == nil {
+				return nil, fmt.Errorf("jwt: nil key for kid %q", kid)
+			}
+		case *ecdsa.PublicKey:
+			if k == nil {
+				return nil, fmt.Errorf("jwt: nil key for kid %q", kid)
+			}
+		default:
+			return nil, fmt.Errorf("jwt: unsupported key type %T for kid %q", k, kid)
+		}
+		v.keys[kid] = k
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v, nil
+}
+
+func (v *staticVerifier) Verify(ctx context.Context, raw string) (*Claims, error) {
+	return verifyToken(ensureContext(ctx), raw, v.keyFor, v.params)
+}
+
+func (v *staticVerifier) keyFor(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if k, ok := v.keys[kid]; ok {
+		return k, nil
+	}
+	return nil, ErrUnknownKID
+}
@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStaticVerifier_RS256(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &key.PublicKey}, WithStaticIssuer("issuer"))
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+
+	raw, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	cl, err := v.Verify(context.Background(), raw)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if cl.Subject != "550e8400-e29b-41d4-a716-446655440000" {
+		t.Fatalf("unexpected subject %q", cl.Subject)
+	}
+}
+
+func TestStaticVerifier_ES256(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-ec": &key.PublicKey})
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+
+	raw, err := signedTokenES("ES256", "kid-ec", key)
+	if err != nil {
+		t.Fatalf("signedTokenES: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestStaticVerifier_UnknownKID(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &key.PublicKey})
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+
+	raw, err := signedTokenRS256("kid-b", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw); !errors.Is(err, ErrUnknownKID) {
+		t.Fatalf("expected ErrUnknownKID, got %v", err)
+	}
+}
+
+func TestStaticVerifier_SameChecksAsJWKS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	raw, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  crypto.PublicKey
+		opts []StaticOption
+		want error
+	}{
+		{"bad signature", &other.PublicKey, nil, ErrBadSignature},
+		{"issuer mismatch", &key.PublicKey, []StaticOption{WithStaticIssuer("other")}, ErrIssuerMismatch},
+		{"expired", &key.PublicKey, []StaticOption{WithStaticClock(func() time.Time { return time.Now().Add(2 * time.Hour) })}, ErrExpired},
+		{"iat in future", &key.PublicKey, []StaticOption{WithStaticClock(func() time.Time { return time.Now().Add(-time.Hour) }), WithStaticLeeway(time.Second)}, ErrIATInFuture},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": tt.key}, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewStaticVerifier: %v", err)
+			}
+			if _, err := v.Verify(context.Background(), raw); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewStaticVerifier_InvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	for name, keys := range map[string]map[string]crypto.PublicKey{
+		"empty":       nil,
+		"empty kid":   {"": &key.PublicKey},
+		"nil rsa key": {"kid": (*rsa.PublicKey)(nil)},
+		"unsupported": {"kid": []byte("secret")},
+	} {
+		if _, err := NewStaticVerifier(keys); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestStaticVerifier_RejectFutureNBF(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	raw, err := signedTokenRS256With("kid-a", key, map[string]any{"nbf": time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("signedTokenRS256With: %v", err)
+	}
+	keys := map[string]crypto.PublicKey{"kid-a": &key.PublicKey}
+
+	v, _ := NewStaticVerifier(keys)
+	if _, err := v.Verify(context.Background(), raw); err != nil {
+		t.Fatalf("expected nbf to be ignored by default, got %v", err)
+	}
+	v, _ = NewStaticVerifier(keys, WithStaticRejectFutureNBF())
+	if _, err := v.Verify(context.Background(), raw); !errors.Is(err, ErrNBFInFuture) {
+		t.Fatalf("expected ErrNBFInFuture, got %v", err)
+	}
+}
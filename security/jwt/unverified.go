@@ -0,0 +1,34 @@
+package jwt
+
+import "encoding/base64"
+
+// Header — поля JOSE-заголовка, которые разбирает пакет.
+type Header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// ParseUnverified разбирает токен БЕЗ проверки подписи, alg/kid/typ, времени и issuer.
+// Только для логов и отладки (посмотреть iss, kid, exp в инциденте); результату нельзя
+// доверять и нельзя использовать его для авторизации — для этого есть Verifier.Verify.
+// Некорректный токен (размер, число сегментов, base64, JSON) отклоняется так же,
+// как в Verify: errors.Is(err, ErrMalformed).
+func ParseUnverified(raw string) (header Header, claims *Claims, err error) {
+	parts, hdr, err := splitToken(raw)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	if _, err := base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return Header{}, nil, wrapVerifyError(ErrMalformed, err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Header{}, nil, wrapVerifyError(ErrMalformed, err)
+	}
+	cl, err := decodeClaims(payload)
+	if err != nil {
+		return Header{}, nil, wrapVerifyError(ErrMalformed, err)
+	}
+	return hdr, cl, nil
+}
@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseUnverified_WellFormed(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := time.Now().Add(-time.Hour).Unix() // просроченный: ParseUnverified время не проверяет
+	tok, err := signedTokenRS256With("kid-1", key, map[string]any{"exp": exp, "jti": "j-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hdr, cl, err := ParseUnverified(tok)
+	if err != nil {
+		t.Fatalf("ParseUnverified: %v", err)
+	}
+	if hdr != (Header{Alg: "RS256", Kid: "kid-1", Typ: "JWT"}) {
+		t.Fatalf("header = %+v", hdr)
+	}
+	if cl.Issuer != "issuer" || cl.Exp != exp || cl.Jti != "j-1" || len(cl.Audience) != 1 || cl.Audience[0] != "wallet" {
+		t.Fatalf("claims = %+v", cl)
+	}
+
+	// Подделанная подпись не мешает разбору: подпись не проверяется.
+	parts := strings.Split(tok, ".")
+	forged := parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString([]byte("forged"))
+	if _, _, err := ParseUnverified(forged); err != nil {
+		t.Fatalf("forged signature must still parse: %v", err)
+	}
+}
+
+func TestParseUnverified_RejectsMalformed(t *testing.T) {
+	t.Parallel()
+
+	hdr := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"k"}`))
+	cases := []struct {
+		name string
+		raw  string
+	}{
+		{"empty", ""},
+		{"two segments", "a.b"},
+		{"too large", strings.Repeat("a", 16*1024+1)},
+		{"bad header base64", "@@@.e30.c2ln"},
+		{"bad header json", base64.RawURLEncoding.EncodeToString([]byte("{")) + ".e30.c2ln"},
+		{"bad payload base64", hdr + ".@@@.c2ln"},
+		{"bad payload json", hdr + "." + base64.RawURLEncoding.EncodeToString([]byte("[")) + ".c2ln"},
+		{"bad signature base64", hdr + ".e30.@@@"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			hdr, cl, err := ParseUnverified(tc.raw)
+			if !errors.Is(err, ErrMalformed) {
+				t.Fatalf("expected ErrMalformed, got %v", err)
+			}
+			if cl != nil || hdr != (Header{}) {
+				t.Fatalf("expected zero results on error, got %+v %+v", hdr, cl)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ package jwt
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"time"
@@ -21,9 +23,11 @@ var (
 	ErrActorMismatch       = errors.New("jwt: actor mismatch")
 	ErrExpired             = errors.New("jwt: token expired")
 	ErrIATInFuture         = errors.New("jwt: iat in the future")
+	ErrNBFInFuture         = errors.New("jwt: nbf in the future")
 	ErrTTLTooLong          = errors.New("jwt: ttl too long")
 	ErrMissingJTI          = errors.New("jwt: missing jti")
 	ErrReplay              = errors.New("jwt: replay detected")
+	ErrReplayStore         = errors.New("jwt: replay store error")
 	ErrMTLSBindingMismatch = errors.New("jwt: mtls binding mismatch")
 	ErrMissingScopes       = errors.New("jwt: missing scopes")
 	ErrWalletMismatch      = errors.New("jwt: wallet mismatch")
@@ -55,6 +59,10 @@ type Claims struct {
 	// Скоупы (внутренний формат)
 	Scopes []string `json:"scopes,omitempty"` // ["wallet:read","payments:create"]
 
+	// Роли и группы (RBAC); в токене — строка через пробел или массив
+	Roles  []string `json:"roles,omitempty"`  // ["support","admin"]
+	Groups []string `json:"groups,omitempty"` // ["ops","eu-merchants"]
+
 	// Семантика OBO
 	Azp   string `json:"azp,omitempty"` // кто получил user access от SSO (напр. "vortex-web")
 	Act   *Actor `json:"act,omitempty"` // кто обменял токен (напр. "api-gateway")
@@ -83,6 +91,32 @@ func (c Claims) EffectiveScopes() []string {
 	return out
 }
 
+// ScopesForAudience — отсортированные scopes сервиса aud, т.е. с префиксом "<aud>:"
+// ("wallet:read" для "wallet"). Чужие ("payments:create") и глобальные ("*") отбрасываются.
+// Пустой aud — nil.
+func (c Claims) ScopesForAudience(aud string) []string {
+	if aud == "" {
+		return nil
+	}
+	return c.ScopesWithPrefix(aud + ":")
+}
+
+// ScopesWithPrefix — отсортированные scopes, начинающиеся с одного из prefixes.
+// Пустые префиксы игнорируются (иначе пропустили бы всё).
+func (c Claims) ScopesWithPrefix(prefixes ...string) []string {
+	var out []string
+	for _, s := range c.Scopes {
+		for _, p := range prefixes {
+			if p != "" && strings.HasPrefix(s, p) {
+				out = append(out, s)
+				break
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
 // HasScopes — required ⊆ Scopes.
 func (c Claims) HasScopes(required ...string) bool {
 	if len(required) == 0 {
@@ -100,11 +134,35 @@ func (c Claims) HasScopes(required ...string) bool {
 	return true
 }
 
+// HasRole — r есть в Roles (точное совпадение).
+func (c Claims) HasRole(r string) bool {
+	return r != "" && slices.Contains(c.Roles, r)
+}
+
+// HasAnyGroup — хотя бы одна из groups есть в Groups. Пустой список — false.
+func (c Claims) HasAnyGroup(groups ...string) bool {
+	for _, g := range groups {
+		if g != "" && slices.Contains(c.Groups, g) {
+			return true
+		}
+	}
+	return false
+}
+
 // Verifier — контракт верификации подписи/базовых временных полей.
 type Verifier interface {
 	Verify(ctx context.Context, rawToken string) (*Claims, error)
 }
 
+// CloseVerifier — закрывает v, если он реализует io.Closer (например, JWKS-верификатор).
+// Для остальных реализаций и nil — no-op.
+func CloseVerifier(v Verifier) error {
+	if c, ok := v.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // AudienceChecker — проверка совпадения aud.
 //
 // Nil-safe contract: если cl == nil, функция обязана вернуть false.
@@ -119,20 +177,29 @@ func DefaultAudienceChecker(cl *Claims, want string) bool {
 
 // OBOValidateOptions — усиленная проверка OBO-токена.
 type OBOValidateOptions struct {
-	WantAudience string   // обязательна
-	WantActor    string   // если задан — act.sub должен совпасть
-	WantWalletID string   // (опц.) cl.WalletID должен совпасть
-	AllowedAZP   []string // (опц.) белый список azp (если список задан — azp обязателен)
+	WantAudience  string   // обязательна (либо WantAudiences)
+	WantAudiences []string // (опц.) допустимые aud; объединяется с WantAudience
+	WantActor     string   // если задан — act.sub должен совпасть
+	WantWalletID  string   // (опц.) cl.WalletID должен совпасть
+	AllowedAZP    []string // (опц.) белый список azp (если список задан — azp обязателен)
 
 	Leeway         time.Duration
 	MaxTTL         time.Duration
 	MTLSThumbprint string // если непустой — PoP обязателен
 	SeenJTI        func(string) bool
+	ReplayGuard    ReplayGuard // если задан — используется вместо SeenJTI
 	RequireScopes  bool
 }
 
 // ValidateOBO — строгая валидация OBO.
 func ValidateOBO(now time.Time, cl *Claims, opt OBOValidateOptions) error {
+	return ValidateOBOContext(context.Background(), now, cl, opt)
+}
+
+// ValidateOBOContext — ValidateOBO с контекстом для ReplayGuard.
+func ValidateOBOContext(ctx context.Context, now time.Time, cl *Claims, opt OBOValidateOptions) error {
+	ctx = ensureContext(ctx)
+
 	if cl == nil {
 		return ErrNilClaims
 	}
@@ -142,12 +209,13 @@ func ValidateOBO(now time.Time, cl *Claims, opt OBOValidateOptions) error {
 		return ErrBadSubject
 	}
 
-	if strings.TrimSpace(opt.WantAudience) == "" {
+	want := wantAudiences(opt)
+	if len(want) == 0 {
 		return ErrAudienceRequired
 	}
 
-	// 1) aud: ровно один и тот, который ожидаем
-	if len(cl.Audience) != 1 || cl.Audience[0] != opt.WantAudience {
+	// 1) aud: ровно один и входит в ожидаемые
+	if len(cl.Audience) != 1 || !slices.Contains(want, cl.Audience[0]) {
 		return ErrAudMismatch
 	}
 
@@ -184,7 +252,7 @@ func ValidateOBO(now time.Time, cl *Claims, opt OBOValidateOptions) error {
 	if strings.TrimSpace(cl.Jti) == "" {
 		return ErrMissingJTI
 	}
-	if opt.SeenJTI != nil && opt.SeenJTI(cl.Jti) {
+	if opt.ReplayGuard == nil && opt.SeenJTI != nil && opt.SeenJTI(cl.Jti) {
 		return ErrReplay
 	}
 
@@ -205,9 +273,35 @@ func ValidateOBO(now time.Time, cl *Claims, opt OBOValidateOptions) error {
 		return ErrWalletMismatch
 	}
 
+	// 8) атомарный anti-replay: jti записывается только для токена,
+	// прошедшего все остальные проверки.
+	if opt.ReplayGuard != nil {
+		seen, err := opt.ReplayGuard.ObserveJTI(ctx, cl.Jti, time.Unix(cl.Exp, 0).Add(leeway))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrReplayStore, err)
+		}
+		if seen {
+			return ErrReplay
+		}
+	}
+
 	return nil
 }
 
+// wantAudiences — объединение WantAudience и WantAudiences без пустых значений.
+func wantAudiences(opt OBOValidateOptions) []string {
+	out := make([]string, 0, 1+len(opt.WantAudiences))
+	if strings.TrimSpace(opt.WantAudience) != "" {
+		out = append(out, opt.WantAudience)
+	}
+	for _, a := range opt.WantAudiences {
+		if strings.TrimSpace(a) != "" {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
 // RequireScopes — ValidateOBO + проверка конкретных скоупов.
 func RequireScopes(now time.Time, cl *Claims, opt OBOValidateOptions, required ...string) error {
 	if err := ValidateOBO(now, cl, opt); err != nil {
@@ -3,14 +3,24 @@ package jwt
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io"
 	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -61,6 +71,47 @@ func TestValidateOBO_AudMismatch(t *testing.T) {
 	}
 }
 
+func TestValidateOBO_WantAudiences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		aud       []string
+		want      string
+		wantMulti []string
+		wantErr   error
+	}{
+		{"single match via list", []string{"wallet"}, "", []string{"wallet"}, nil},
+		{"multi match", []string{"wallet-admin"}, "", []string{"wallet", "wallet-admin"}, nil},
+		{"union with WantAudience", []string{"wallet"}, "wallet", []string{"wallet-admin"}, nil},
+		{"union matches list entry", []string{"wallet-admin"}, "wallet", []string{"wallet-admin"}, nil},
+		{"no match", []string{"payments"}, "wallet", []string{"wallet-admin"}, ErrAudMismatch},
+		{"multiple token audiences rejected", []string{"wallet", "wallet-admin"}, "", []string{"wallet", "wallet-admin"}, ErrAudMismatch},
+		{"blank entries only", []string{"wallet"}, " ", []string{"", "  "}, ErrAudienceRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{
+				Subject:  "550e8400-e29b-41d4-a716-446655440000",
+				Audience: tt.aud,
+				Act:      &Actor{Sub: "api-gateway"},
+				Jti:      "jti-123",
+				Iat:      time.Now().Unix(),
+				Exp:      time.Now().Add(time.Hour).Unix(),
+			}
+
+			err := ValidateOBO(time.Now(), claims, OBOValidateOptions{
+				WantAudience:  tt.want,
+				WantAudiences: tt.wantMulti,
+			})
+			if err != tt.wantErr {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestValidateOBO_AudienceRequired(t *testing.T) {
 	t.Parallel()
 
@@ -448,6 +499,82 @@ func TestClaims_HasScopes(t *testing.T) {
 	}
 }
 
+func TestClaims_HasRoleAndAnyGroup(t *testing.T) {
+	t.Parallel()
+
+	claims := &Claims{Roles: []string{"support", "admin"}, Groups: []string{"ops", "eu"}}
+
+	if !claims.HasRole("admin") || claims.HasRole("Admin") || claims.HasRole("") {
+		t.Fatal("HasRole: expected exact, non-empty match")
+	}
+	if !claims.HasAnyGroup("finance", "eu") {
+		t.Fatal("expected HasAnyGroup(finance,eu) = true")
+	}
+	if claims.HasAnyGroup("finance", "") || claims.HasAnyGroup() {
+		t.Fatal("expected HasAnyGroup without a match = false")
+	}
+	if (Claims{}).HasRole("admin") || (Claims{}).HasAnyGroup("ops") {
+		t.Fatal("empty claims must not match")
+	}
+}
+
+func TestDecodeClaims_RolesAndGroups(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		payload    string
+		wantRoles  []string
+		wantGroups []string
+	}{
+		{"array", `{"roles":["admin"," support ","admin",""],"groups":["ops",1,"ops"]}`, []string{"admin", "support"}, []string{"ops"}},
+		{"string", `{"roles":"admin","groups":" ops  eu ops "}`, []string{"admin"}, []string{"ops", "eu"}},
+		{"absent", `{"scopes":["wallet:read"]}`, nil, nil},
+		{"empty", `{"roles":"","groups":[]}`, nil, nil},
+	}
+	for _, tc := range cases {
+		cl, err := decodeClaims([]byte(tc.payload))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !slices.Equal(cl.Roles, tc.wantRoles) || !slices.Equal(cl.Groups, tc.wantGroups) {
+			t.Fatalf("%s: roles=%q groups=%q, want %q %q", tc.name, cl.Roles, cl.Groups, tc.wantRoles, tc.wantGroups)
+		}
+	}
+
+	for _, payload := range []string{`{"roles":1}`, `{"groups":{"a":true}}`, `{"scopes":"wallet:read"}`} {
+		if _, err := decodeClaims([]byte(payload)); err == nil {
+			t.Fatalf("%s: expected error", payload)
+		}
+	}
+}
+
+func TestClaims_ScopesForAudience(t *testing.T) {
+	t.Parallel()
+
+	claims := &Claims{Scopes: []string{"payments:*", "wallet:write", "*", "walletx:read", "wallet:read", "payments:create"}}
+
+	if got, want := claims.ScopesForAudience("wallet"), []string{"wallet:read", "wallet:write"}; !slices.Equal(got, want) {
+		t.Fatalf("wallet: got %v, want %v", got, want)
+	}
+	if got, want := claims.ScopesForAudience("payments"), []string{"payments:*", "payments:create"}; !slices.Equal(got, want) {
+		t.Fatalf("payments: got %v, want %v", got, want)
+	}
+	if got := claims.ScopesForAudience("ledger"); got != nil {
+		t.Fatalf("ledger: expected nil, got %v", got)
+	}
+	if got := claims.ScopesForAudience(""); got != nil {
+		t.Fatalf("empty audience: expected nil, got %v", got)
+	}
+
+	if got, want := claims.ScopesWithPrefix("wallet:", "payments:c", ""), []string{"payments:create", "wallet:read", "wallet:write"}; !slices.Equal(got, want) {
+		t.Fatalf("prefixes: got %v, want %v", got, want)
+	}
+	if got := claims.ScopesWithPrefix(""); got != nil {
+		t.Fatalf("empty prefix must not match, got %v", got)
+	}
+}
+
 func TestClaims_EffectiveScopes(t *testing.T) {
 	t.Parallel()
 
@@ -697,56 +824,966 @@ func TestJWKSVerifier_KeepPreviousKeysOnEmptyRefresh(t *testing.T) {
 	}
 }
 
-func TestX5tS256FromCert_Nil(t *testing.T) {
+func TestJWKSVerifier_ES256AndES384(t *testing.T) {
 	t.Parallel()
 
-	if got := X5tS256FromCert(nil); got != "" {
-		t.Fatalf("expected empty thumbprint for nil cert, got %q", got)
+	key256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-256 key: %v", err)
+	}
+	key384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-384 key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				jwkFromKey("kid-rsa", &rsaKey.PublicKey),
+				jwkFromECKey("kid-es256", &key256.PublicKey),
+				jwkFromECKey("kid-es384", &key384.PublicKey),
+			},
+		})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+
+	raw256, err := signedTokenES("ES256", "kid-es256", key256)
+	if err != nil {
+		t.Fatalf("signedTokenES ES256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw256); err != nil {
+		t.Fatalf("ES256 Verify failed: %v", err)
+	}
+
+	raw384, err := signedTokenES("ES384", "kid-es384", key384)
+	if err != nil {
+		t.Fatalf("signedTokenES ES384: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw384); err != nil {
+		t.Fatalf("ES384 Verify failed: %v", err)
+	}
+
+	rawRSA, err := signedTokenRS256("kid-rsa", rsaKey)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), rawRSA); err != nil {
+		t.Fatalf("RS256 Verify failed alongside EC keys: %v", err)
 	}
 }
 
-func signedTokenRS256(kid string, key *rsa.PrivateKey) (string, error) {
-	header := map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}
-	payload := map[string]any{
-		"iss": "issuer",
-		"sub": "550e8400-e29b-41d4-a716-446655440000",
-		"aud": []string{"wallet"},
-		"iat": time.Now().Add(-time.Minute).Unix(),
-		"exp": time.Now().Add(time.Hour).Unix(),
+func TestJWKSVerifier_ES256_RejectsWrongKeyOrCurve(t *testing.T) {
+	t.Parallel()
+
+	key256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate P-256 key: %v", err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate other key: %v", err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
 	}
 
-	hb, err := json.Marshal(header)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				jwkFromKey("kid-rsa", &rsaKey.PublicKey),
+				jwkFromECKey("kid-es256", &key256.PublicKey),
+			},
+		})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+	})
 	if err != nil {
-		return "", err
+		t.Fatalf("NewJWKSVerifier: %v", err)
 	}
-	pb, err := json.Marshal(payload)
+
+	forged, err := signedTokenES("ES256", "kid-es256", other)
 	if err != nil {
-		return "", err
+		t.Fatalf("signedTokenES: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), forged); err == nil {
+		t.Fatal("expected signature by foreign key to be rejected")
 	}
 
-	hEnc := base64.RawURLEncoding.EncodeToString(hb)
-	pEnc := base64.RawURLEncoding.EncodeToString(pb)
-	msg := hEnc + "." + pEnc
-	h := sha256.Sum256([]byte(msg))
-	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	// ES256-заголовок с kid RSA-ключа: тип ключа не совпадает.
+	mismatched, err := signedTokenES("ES256", "kid-rsa", key256)
 	if err != nil {
-		return "", err
+		t.Fatalf("signedTokenES: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), mismatched); err == nil {
+		t.Fatal("expected EC alg with RSA key to be rejected")
 	}
 
-	return msg + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+	// ES384-заголовок с P-256 ключом.
+	wrongCurve, err := signedTokenES("ES384", "kid-es256", key256)
+	if err != nil {
+		t.Fatalf("signedTokenES: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), wrongCurve); err == nil {
+		t.Fatal("expected ES384 with P-256 key to be rejected")
+	}
 }
 
-func jwkFromKey(kid string, pub *rsa.PublicKey) map[string]string {
-	e := big.NewInt(int64(pub.E)).Bytes()
-	if len(e) == 0 {
-		e = []byte{1}
+func TestJWKSVerifier_Close(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
 	}
-	return map[string]string{
-		"kty": "RSA",
-		"kid": kid,
-		"alg": "RS256",
-		"use": "sig",
-		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
-		"e":   base64.RawURLEncoding.EncodeToString(e),
+
+	closedConns := make(chan struct{}, 16)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	srv.Config.ConnState = func(_ net.Conn, st http.ConnState) {
+		if st == http.StateClosed {
+			closedConns <- struct{}{}
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+	if _, ok := v.(io.Closer); !ok {
+		t.Fatal("expected JWKS verifier to implement io.Closer")
+	}
+
+	raw, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw); err != nil {
+		t.Fatalf("Verify before Close failed: %v", err)
+	}
+
+	if err := CloseVerifier(v); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := CloseVerifier(v); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	select {
+	case <-closedConns:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected idle JWKS connection to be closed")
+	}
+
+	if _, err := v.Verify(context.Background(), raw); !errors.Is(err, ErrVerifierClosed) {
+		t.Fatalf("expected ErrVerifierClosed, got %v", err)
+	}
+}
+
+func TestJWKSVerifier_BackgroundRefresh_VerifyDoesNotBlock(t *testing.T) {
+	t.Parallel()
+
+	keyA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate keyA: %v", err)
+	}
+	keyB, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate keyB: %v", err)
+	}
+
+	const slowJWKS = 300 * time.Millisecond
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := atomic.AddInt32(&calls, 1)
+		keys := []map[string]string{jwkFromKey("kid-a", &keyA.PublicKey)}
+		if call > 1 {
+			// Медленный JWKS: Verify не должен этого замечать.
+			time.Sleep(slowJWKS)
+			keys = append(keys, jwkFromKey("kid-b", &keyB.PublicKey))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:               srv.URL,
+		RefreshEvery:      30 * time.Millisecond,
+		Timeout:           2 * time.Second,
+		BackgroundRefresh: true,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+	defer func() { _ = CloseVerifier(v) }()
+
+	rawA, err := signedTokenRS256("kid-a", keyA)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * slowJWKS)
+	for time.Now().Before(deadline) {
+		start := time.Now()
+		if _, err := v.Verify(context.Background(), rawA); err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if d := time.Since(start); d > slowJWKS/2 {
+			t.Fatalf("Verify blocked on JWKS refresh: %v", d)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	rawB, err := signedTokenRS256("kid-b", keyB)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	rotated := false
+	for i := 0; i < 100 && !rotated; i++ {
+		if _, err := v.Verify(context.Background(), rawB); err == nil {
+			rotated = true
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !rotated {
+		t.Fatal("expected rotated key to be picked up by background refresh")
+	}
+
+	if err := CloseVerifier(v); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	after := atomic.LoadInt32(&calls)
+	time.Sleep(100 * time.Millisecond)
+	if got := atomic.LoadInt32(&calls); got != after {
+		t.Fatalf("expected background refresh to stop after Close, calls %d -> %d", after, got)
+	}
+}
+
+func TestCloseVerifier_NonCloser(t *testing.T) {
+	t.Parallel()
+
+	if err := CloseVerifier(nil); err != nil {
+		t.Fatalf("expected nil for nil verifier, got %v", err)
+	}
+}
+
+func TestJWKSVerifier_Clock_ExpiredWithFrozenTime(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	defer srv.Close()
+
+	// Токен валиден час от текущего момента; замороженные часы сдвинуты на 2 часа вперёд.
+	frozen := time.Now().Add(2 * time.Hour)
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+		Clock:        func() time.Time { return frozen },
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+
+	raw, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+
+	_, err = v.Verify(context.Background(), raw)
+	if !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired with frozen clock, got %v", err)
+	}
+}
+
+func TestJWKSVerifier_RejectFutureNBF(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	defer srv.Close()
+
+	future, err := signedTokenRS256With("kid-a", key, map[string]any{"nbf": time.Now().Add(10 * time.Minute).Unix()})
+	if err != nil {
+		t.Fatalf("signedTokenRS256With: %v", err)
+	}
+	past, err := signedTokenRS256With("kid-a", key, map[string]any{"nbf": float64(time.Now().Add(-time.Minute).Unix()) + 0.5})
+	if err != nil {
+		t.Fatalf("signedTokenRS256With: %v", err)
+	}
+
+	newVerifier := func(reject bool) Verifier {
+		v, err := NewJWKSVerifier(JWKSConfig{
+			URL:             srv.URL,
+			RefreshEvery:    time.Hour,
+			Timeout:         2 * time.Second,
+			RejectFutureNBF: reject,
+		})
+		if err != nil {
+			t.Fatalf("NewJWKSVerifier: %v", err)
+		}
+		return v
+	}
+
+	// По умолчанию nbf игнорируется (прежнее поведение).
+	if _, err := newVerifier(false).Verify(context.Background(), future); err != nil {
+		t.Fatalf("expected nbf to be ignored by default, got %v", err)
+	}
+
+	v := newVerifier(true)
+	if _, err := v.Verify(context.Background(), future); !errors.Is(err, ErrNBFInFuture) {
+		t.Fatalf("expected ErrNBFInFuture, got %v", err)
+	}
+	if _, err := v.Verify(context.Background(), past); err != nil {
+		t.Fatalf("expected past nbf to pass, got %v", err)
+	}
+
+	bad, err := signedTokenRS256With("kid-a", key, map[string]any{"nbf": "soon"})
+	if err != nil {
+		t.Fatalf("signedTokenRS256With: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), bad); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed for non-numeric nbf, got %v", err)
+	}
+}
+
+func TestVerifyParams_EffectiveLeeway(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		leeway, maxLeeway, want time.Duration
+	}{
+		{0, 0, 5 * time.Second},
+		{30 * time.Second, 0, 30 * time.Second},
+		{5 * time.Hour, 0, 5 * time.Minute},
+		{time.Minute, 10 * time.Second, 10 * time.Second},
+		{0, time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		p := verifyParams{leeway: tt.leeway, maxLeeway: tt.maxLeeway}
+		if got := p.effectiveLeeway(); got != tt.want {
+			t.Fatalf("leeway=%v max=%v: got %v, want %v", tt.leeway, tt.maxLeeway, got, tt.want)
+		}
+	}
+}
+
+func TestJWKSVerifier_MaxLeewayCapsExpiry(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	defer srv.Close()
+
+	// Токен истёк 10 минут назад; Leeway в 1h (ошибка конфигурации) ограничен 5m.
+	raw, err := signedTokenRS256With("kid-a", key, map[string]any{
+		"iat": time.Now().Add(-time.Hour).Unix(),
+		"exp": time.Now().Add(-10 * time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("signedTokenRS256With: %v", err)
+	}
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+		Leeway:       time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired with capped leeway, got %v", err)
+	}
+}
+
+func TestJWKSVerifier_ErrorSentinels(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate other key: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	defer srv.Close()
+
+	newVerifier := func(t *testing.T, cfg JWKSConfig) Verifier {
+		t.Helper()
+		cfg.URL = srv.URL
+		cfg.RefreshEvery = time.Hour
+		cfg.Timeout = 2 * time.Second
+		v, err := NewJWKSVerifier(cfg)
+		if err != nil {
+			t.Fatalf("NewJWKSVerifier: %v", err)
+		}
+		return v
+	}
+
+	valid, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	unknownKID, err := signedTokenRS256("kid-missing", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	badSig, err := signedTokenRS256("kid-a", other)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	hs256 := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","kid":"kid-a"}`)) + ".e30.c2ln"
+
+	tests := []struct {
+		name    string
+		cfg     JWKSConfig
+		raw     string
+		want    error
+		wantMsg string
+	}{
+		{"empty", JWKSConfig{}, "", ErrMalformed, "jwt: invalid size"},
+		{"two parts", JWKSConfig{}, "a.b", ErrMalformed, "jwt: malformed"},
+		{"bad header base64", JWKSConfig{}, "@@@.e30.c2ln", ErrMalformed, ""},
+		{"no kid", JWKSConfig{}, base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`)) + ".e30.c2ln", ErrMalformed, "jwt: no kid"},
+		{"unexpected alg", JWKSConfig{}, hs256, ErrUnexpectedAlg, "jwt: unexpected alg"},
+		{"unknown kid", JWKSConfig{}, unknownKID, ErrUnknownKID, "jwt: unknown kid"},
+		{"bad signature", JWKSConfig{}, badSig, ErrBadSignature, ""},
+		{"expired", JWKSConfig{Clock: func() time.Time { return time.Now().Add(2 * time.Hour) }}, valid, ErrExpired, "jwt: expired"},
+		{"iat in future", JWKSConfig{Clock: func() time.Time { return time.Now().Add(-time.Hour) }}, valid, ErrIATInFuture, "jwt: iat in the future"},
+		{"issuer mismatch", JWKSConfig{ExpectedIssuer: "other"}, valid, ErrIssuerMismatch, "jwt: unexpected iss"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newVerifier(t, tt.cfg)
+			defer func() { _ = CloseVerifier(v) }()
+
+			_, err := v.Verify(context.Background(), tt.raw)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+			if tt.wantMsg != "" && err.Error() != tt.wantMsg {
+				t.Fatalf("expected message %q, got %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestJWKSVerifier_X5C(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate other key: %v", err)
+	}
+
+	rsaCert := selfSignedCert(t, rsaKey.Public(), rsaKey)
+	ecCert := selfSignedCert(t, ecKey.Public(), ecKey)
+
+	// n/e другого ключа при x5c от rsaKey: запись должна быть пропущена.
+	mismatched := jwkFromKey("kid-mismatch", &otherKey.PublicKey)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{
+				{"kty": "RSA", "kid": "kid-rsa", "alg": "RS256", "use": "sig", "x5c": []string{base64.StdEncoding.EncodeToString(rsaCert.Raw)}},
+				{"kty": "EC", "kid": "kid-ec", "alg": "ES256", "use": "sig", "x5c": []string{base64.StdEncoding.EncodeToString(ecCert.Raw)}},
+				{"kty": "RSA", "kid": "kid-broken", "alg": "RS256", "use": "sig", "x5c": []string{"@@@"}},
+				{"kty": "RSA", "kid": "kid-mismatch", "alg": "RS256", "use": "sig", "n": mismatched["n"], "e": mismatched["e"], "x5c": []string{base64.StdEncoding.EncodeToString(rsaCert.Raw)}},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+
+	rawRSA, err := signedTokenRS256("kid-rsa", rsaKey)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), rawRSA); err != nil {
+		t.Fatalf("RSA x5c Verify failed: %v", err)
+	}
+
+	rawEC, err := signedTokenES("ES256", "kid-ec", ecKey)
+	if err != nil {
+		t.Fatalf("signedTokenES: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), rawEC); err != nil {
+		t.Fatalf("EC x5c Verify failed: %v", err)
+	}
+
+	rawMismatch, err := signedTokenRS256("kid-mismatch", otherKey)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), rawMismatch); !errors.Is(err, ErrUnknownKID) {
+		t.Fatalf("expected entry with n/e not matching x5c to be skipped, got %v", err)
+	}
+
+	src, ok := v.(X5tS256Source)
+	if !ok {
+		t.Fatal("expected JWKS verifier to implement X5tS256Source")
+	}
+	if got, ok := src.X5tS256("kid-rsa"); !ok || got != X5tS256FromCert(rsaCert) {
+		t.Fatalf("unexpected x5t#S256 for kid-rsa: %q, %v", got, ok)
+	}
+	if got, ok := src.X5tS256("kid-ec"); !ok || got != X5tS256FromCert(ecCert) {
+		t.Fatalf("unexpected x5t#S256 for kid-ec: %q, %v", got, ok)
+	}
+	if _, ok := src.X5tS256("kid-broken"); ok {
+		t.Fatal("expected no thumbprint for skipped entry")
+	}
+}
+
+func TestX5tS256FromCert_Nil(t *testing.T) {
+	t.Parallel()
+
+	if got := X5tS256FromCert(nil); got != "" {
+		t.Fatalf("expected empty thumbprint for nil cert, got %q", got)
+	}
+}
+
+func signedTokenRS256(kid string, key *rsa.PrivateKey) (string, error) {
+	return signedTokenRS256With(kid, key, nil)
+}
+
+// signedTokenRS256With подписывает стандартный payload, дополненный/переопределённый extra.
+func signedTokenRS256With(kid string, key *rsa.PrivateKey, extra map[string]any) (string, error) {
+	return signedTokenRS256Header(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid}, key, extra)
+}
+
+// signedTokenRS256Header подписывает RS256 с произвольным заголовком (alg в нём не влияет на подпись).
+func signedTokenRS256Header(header map[string]string, key *rsa.PrivateKey, extra map[string]any) (string, error) {
+	payload := map[string]any{
+		"iss": "issuer",
+		"sub": "550e8400-e29b-41d4-a716-446655440000",
+		"aud": []string{"wallet"},
+		"iat": time.Now().Add(-time.Minute).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range extra {
+		payload[k] = v
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	hEnc := base64.RawURLEncoding.EncodeToString(hb)
+	pEnc := base64.RawURLEncoding.EncodeToString(pb)
+	msg := hEnc + "." + pEnc
+	h := sha256.Sum256([]byte(msg))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, h[:])
+	if err != nil {
+		return "", err
+	}
+
+	return msg + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func jwkFromKey(kid string, pub *rsa.PublicKey) map[string]string {
+	e := big.NewInt(int64(pub.E)).Bytes()
+	if len(e) == 0 {
+		e = []byte{1}
+	}
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(e),
+	}
+}
+
+func signedTokenES(alg, kid string, key *ecdsa.PrivateKey) (string, error) {
+	header := map[string]string{"alg": alg, "typ": "JWT", "kid": kid}
+	payload := map[string]any{
+		"iss": "issuer",
+		"sub": "550e8400-e29b-41d4-a716-446655440000",
+		"aud": []string{"wallet"},
+		"iat": time.Now().Add(-time.Minute).Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	msg := base64.RawURLEncoding.EncodeToString(hb) + "." + base64.RawURLEncoding.EncodeToString(pb)
+	var digest []byte
+	if alg == "ES384" {
+		h := sha512.Sum384([]byte(msg))
+		digest = h[:]
+	} else {
+		h := sha256.Sum256([]byte(msg))
+		digest = h[:]
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return "", err
+	}
+
+	size := (key.Curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+
+	return msg + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func jwkFromECKey(kid string, pub *ecdsa.PublicKey) map[string]string {
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	crv, alg := "P-256", "ES256"
+	if size == 48 {
+		crv, alg = "P-384", "ES384"
+	}
+	x := make([]byte, size)
+	y := make([]byte, size)
+	pub.X.FillBytes(x)
+	pub.Y.FillBytes(y)
+	return map[string]string{
+		"kty": "EC",
+		"kid": kid,
+		"alg": alg,
+		"use": "sig",
+		"crv": crv,
+		"x":   base64.RawURLEncoding.EncodeToString(x),
+		"y":   base64.RawURLEncoding.EncodeToString(y),
+	}
+}
+
+func selfSignedCert(t *testing.T, pub crypto.PublicKey, priv crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "jwks-test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, pub, priv)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return cert
+}
+
+func TestJWKSVerifier_TLSClientConfig_MTLS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate client key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "jwks-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &clientKey.PublicKey, clientKey)
+	if err != nil {
+		t.Fatalf("create client cert: %v", err)
+	}
+	clientCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse client cert: %v", err)
+	}
+
+	var calls int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	// Без клиентского сертификата сервер рвёт handshake.
+	if _, err := NewJWKSVerifier(JWKSConfig{
+		URL:             srv.URL,
+		Timeout:         2 * time.Second,
+		TLSClientConfig: &tls.Config{RootCAs: roots},
+	}); err == nil {
+		t.Fatal("expected refresh to fail without client certificate")
+	}
+
+	tlsCfg := &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: clientKey, Leaf: clientCert}},
+	}
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:             srv.URL,
+		RefreshEvery:    time.Hour,
+		Timeout:         2 * time.Second,
+		TLSClientConfig: tlsCfg,
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier over mTLS: %v", err)
+	}
+	defer func() { _ = CloseVerifier(v) }()
+
+	raw, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("signedTokenRS256: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), raw); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected one successful JWKS fetch, got %d", calls)
+	}
+}
+
+func TestVerify_TypHeader(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &key.PublicKey})
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+
+	tests := []struct {
+		typ     string
+		wantErr bool
+	}{
+		{"JWT", false},
+		{"jwt", false},
+		{"at+jwt", false},
+		{"AT+JWT", false},
+		{"", false},
+		{"JOSE", true},
+		{"dpop+jwt", true},
+	}
+	for _, tt := range tests {
+		hdr := map[string]string{"alg": "RS256", "kid": "kid-a"}
+		if tt.typ != "" {
+			hdr["typ"] = tt.typ
+		}
+		raw, err := signedTokenRS256Header(hdr, key, nil)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		_, err = v.Verify(context.Background(), raw)
+		if tt.wantErr {
+			if !errors.Is(err, ErrUnexpectedTyp) {
+				t.Fatalf("typ=%q: expected ErrUnexpectedTyp, got %v", tt.typ, err)
+			}
+		} else if err != nil {
+			t.Fatalf("typ=%q: Verify: %v", tt.typ, err)
+		}
+	}
+}
+
+func TestVerify_AlgNone(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	v, err := NewStaticVerifier(map[string]crypto.PublicKey{"kid-a": &key.PublicKey})
+	if err != nil {
+		t.Fatalf("NewStaticVerifier: %v", err)
+	}
+
+	for _, alg := range []string{"none", "None", "NONE"} {
+		hb, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+		pb, _ := json.Marshal(map[string]any{"sub": "550e8400-e29b-41d4-a716-446655440000", "exp": time.Now().Add(time.Hour).Unix()})
+		raw := base64.RawURLEncoding.EncodeToString(hb) + "." + base64.RawURLEncoding.EncodeToString(pb) + "."
+
+		_, err := v.Verify(context.Background(), raw)
+		if !errors.Is(err, ErrAlgNone) {
+			t.Fatalf("alg=%q: expected ErrAlgNone, got %v", alg, err)
+		}
+		if !errors.Is(err, ErrUnexpectedAlg) {
+			t.Fatalf("alg=%q: expected ErrAlgNone to also match ErrUnexpectedAlg", alg)
+		}
+	}
+}
+
+func TestJWKSVerifier_AllowedTypes(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{jwkFromKey("kid-a", &key.PublicKey)},
+		})
+	}))
+	defer srv.Close()
+
+	v, err := NewJWKSVerifier(JWKSConfig{
+		URL:          srv.URL,
+		RefreshEvery: time.Hour,
+		Timeout:      2 * time.Second,
+		AllowedTypes: []string{"at+jwt"},
+	})
+	if err != nil {
+		t.Fatalf("NewJWKSVerifier: %v", err)
+	}
+	defer func() { _ = CloseVerifier(v) }()
+
+	jwtTyp, err := signedTokenRS256("kid-a", key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), jwtTyp); !errors.Is(err, ErrUnexpectedTyp) {
+		t.Fatalf("expected ErrUnexpectedTyp for typ JWT, got %v", err)
+	}
+
+	atTyp, err := signedTokenRS256Header(map[string]string{"alg": "RS256", "typ": "at+jwt", "kid": "kid-a"}, key, nil)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), atTyp); err != nil {
+		t.Fatalf("Verify at+jwt: %v", err)
+	}
+
+	noTyp, err := signedTokenRS256Header(map[string]string{"alg": "RS256", "kid": "kid-a"}, key, nil)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), noTyp); !errors.Is(err, ErrUnexpectedTyp) {
+		t.Fatalf("expected ErrUnexpectedTyp for missing typ with explicit AllowedTypes, got %v", err)
+	}
+}
+
+func TestRefreshBackoff_DoublesUpToLimit(t *testing.T) {
+	t.Parallel()
+
+	limit := 5 * time.Second
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	cur := time.Second
+	for i, w := range want {
+		cur = refreshBackoff(cur, limit)
+		if cur != w {
+			t.Fatalf("step %d: expected %v, got %v", i, w, cur)
+		}
 	}
 }
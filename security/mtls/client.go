@@ -22,10 +22,25 @@ func TLSConfigClient(c Config) (*tls.Config, *Reloader, error) {
 	state := &atomic.Pointer[bundle]{}
 	state.Store(b)
 
+	tlsConf := clientTLSConfig(c, state.Load)
+
+	var r *Reloader
+	if c.ReloadInterval > 0 {
+		r = NewReloader(c, func(nb *bundle) {
+			state.Store(nb)
+		})
+		r.Start(time.NewTicker(c.ReloadInterval))
+	}
+
+	return tlsConf, r, nil
+}
+
+// clientTLSConfig builds the client tls.Config; load returns the bundle to use for each handshake.
+func clientTLSConfig(c Config, load func() *bundle) *tls.Config {
 	tlsConf := &tls.Config{
 		MinVersion: tls.VersionTLS13,
 		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
-			current := state.Load()
+			current := load()
 			return &current.cert, nil
 		},
 	}
@@ -46,7 +61,7 @@ func TLSConfigClient(c Config) (*tls.Config, *Reloader, error) {
 		}
 
 		opts := x509.VerifyOptions{
-			Roots:         state.Load().rootPool,
+			Roots:         load().rootPool,
 			Intermediates: intermediates,
 			CurrentTime:   time.Now(),
 		}
@@ -58,13 +73,5 @@ func TLSConfigClient(c Config) (*tls.Config, *Reloader, error) {
 		return err
 	}
 
-	var r *Reloader
-	if c.ReloadInterval > 0 {
-		r = NewReloader(c, func(nb *bundle) {
-			state.Store(nb)
-		})
-		r.Start(time.NewTicker(c.ReloadInterval))
-	}
-
-	return tlsConf, r, nil
+	return tlsConf
 }
@@ -62,15 +62,7 @@ func (r *Reloader) Start(t *time.Ticker) {
 		for {
 			select {
 			case <-t.C:
-				if r.changed() {
-					if nb, err := loadBundle(r.cfg); err == nil {
-						r.snap()
-						r.apply(nb)
-						r.log(ReloadEvent{})
-					} else {
-						r.log(ReloadEvent{Err: err})
-					}
-				}
+				r.reloadIfChanged()
 			case <-r.stopCh:
 				t.Stop()
 				return
@@ -88,6 +80,22 @@ func (r *Reloader) Stop() {
 	})
 }
 
+// reloadIfChanged reloads and applies the bundle when any file mtime changed.
+// On failure the previous bundle stays in use and the next call retries.
+func (r *Reloader) reloadIfChanged() {
+	if !r.changed() {
+		return
+	}
+	nb, err := loadBundle(r.cfg)
+	if err != nil {
+		r.log(ReloadEvent{Err: err})
+		return
+	}
+	r.snap()
+	r.apply(nb)
+	r.log(ReloadEvent{})
+}
+
 func (r *Reloader) snap() {
 	r.lastCA = mtime(r.cfg.CACertPath)
 	r.lastCrt = mtime(r.cfg.CertPath)
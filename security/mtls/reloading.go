@@ -0,0 +1,66 @@
+package mtls
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultLazyReloadInterval = 30 * time.Second
+
+// ReloadingTLSConfigClient is TLSConfigClient without a background goroutine:
+// on a handshake, at most once per reload interval, the CA/cert/key mtimes are
+// checked and the files are re-read if they changed. Between checks and after a
+// failed reload (logged via DefaultReloadLogger) the cached bundle is served.
+// Config.ReloadInterval is ignored; reload <= 0 means 30s.
+func ReloadingTLSConfigClient(c Config, reload time.Duration) (*tls.Config, error) {
+	return ReloadingTLSConfigClientWithLogger(c, reload, DefaultReloadLogger)
+}
+
+func ReloadingTLSConfigClientWithLogger(c Config, reload time.Duration, log ReloadLogger) (*tls.Config, error) {
+	b, err := loadBundle(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ServerName == "" {
+		slog.Warn("mtls: ServerName is empty, hostname verification disabled")
+	}
+
+	if reload <= 0 {
+		reload = defaultLazyReloadInterval
+	}
+
+	l := &lazyBundle{every: reload, now: time.Now}
+	l.cur.Store(b)
+	l.r = NewReloaderWithLogger(c, func(nb *bundle) { l.cur.Store(nb) }, log)
+	l.r.snap()
+	l.next = l.now().Add(reload)
+
+	return clientTLSConfig(c, l.load), nil
+}
+
+// lazyBundle re-checks files on access instead of on a ticker.
+type lazyBundle struct {
+	r     *Reloader
+	cur   atomic.Pointer[bundle]
+	every time.Duration
+	now   func() time.Time
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// load never blocks on a reload in progress: concurrent handshakes get the cached bundle.
+func (l *lazyBundle) load() *bundle {
+	if l.mu.TryLock() {
+		if now := l.now(); !now.Before(l.next) {
+			l.next = now.Add(l.every)
+			l.r.reloadIfChanged()
+		}
+		l.mu.Unlock()
+	}
+	return l.cur.Load()
+}
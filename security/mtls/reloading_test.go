@@ -0,0 +1,164 @@
+package mtls
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+func clientLeaf(t *testing.T, conf interface {
+	GetClientCertificate() ([]byte, error)
+}) []byte {
+	t.Helper()
+	der, err := conf.GetClientCertificate()
+	if err != nil {
+		t.Fatalf("GetClientCertificate: %v", err)
+	}
+	return der
+}
+
+type leafGetter struct{ get func() ([]byte, error) }
+
+func (g leafGetter) GetClientCertificate() ([]byte, error) { return g.get() }
+
+func replaceFile(t *testing.T, dst, src string, mtime time.Time) {
+	t.Helper()
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("read %s: %v", src, err)
+	}
+	if err := os.WriteFile(dst, b, 0o600); err != nil {
+		t.Fatalf("write %s: %v", dst, err)
+	}
+	if err := os.Chtimes(dst, mtime, mtime); err != nil {
+		t.Fatalf("chtimes %s: %v", dst, err)
+	}
+}
+
+func newLeafGetter(t *testing.T, c Config, reload time.Duration, log ReloadLogger) leafGetter {
+	t.Helper()
+	conf, err := ReloadingTLSConfigClientWithLogger(c, reload, log)
+	if err != nil {
+		t.Fatalf("ReloadingTLSConfigClient: %v", err)
+	}
+	if conf.GetClientCertificate == nil || conf.VerifyConnection == nil {
+		t.Fatalf("tls.Config callbacks are missing")
+	}
+	return leafGetter{get: func() ([]byte, error) {
+		crt, err := conf.GetClientCertificate(nil)
+		if err != nil {
+			return nil, err
+		}
+		return crt.Certificate[0], nil
+	}}
+}
+
+func TestReloadingTLSConfigClient_ServesNewLeafAfterRewrite(t *testing.T) {
+	tc := createTempCerts(t)
+	defer os.RemoveAll(tc.Dir)
+	next := createTempCerts(t)
+	defer os.RemoveAll(next.Dir)
+
+	var mu sync.Mutex
+	var events []ReloadEvent
+	g := newLeafGetter(t, Config{
+		CACertPath: tc.CAPath,
+		CertPath:   tc.ClientCert,
+		KeyPath:    tc.ClientKey,
+		ServerName: "server.test.internal",
+	}, time.Nanosecond, func(ev ReloadEvent) {
+		mu.Lock()
+		events = append(events, ev)
+		mu.Unlock()
+	})
+
+	before := clientLeaf(t, g)
+	if again := clientLeaf(t, g); !bytes.Equal(before, again) {
+		t.Fatalf("unchanged files must serve the cached leaf")
+	}
+
+	mtime := time.Now().Add(time.Minute)
+	replaceFile(t, tc.ClientCert, next.ClientCert, mtime)
+	replaceFile(t, tc.ClientKey, next.ClientKey, mtime)
+
+	after := clientLeaf(t, g)
+	want, _ := loadBundle(Config{CACertPath: next.CAPath, CertPath: next.ClientCert, KeyPath: next.ClientKey})
+	if bytes.Equal(before, after) || !bytes.Equal(after, want.cert.Certificate[0]) {
+		t.Fatalf("expected the rewritten leaf to be served")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 || events[0].Err != nil {
+		t.Fatalf("expected one successful reload event, got %+v", events)
+	}
+}
+
+func TestReloadingTLSConfigClient_KeepsPreviousOnFailure(t *testing.T) {
+	tc := createTempCerts(t)
+	defer os.RemoveAll(tc.Dir)
+
+	var mu sync.Mutex
+	var failed int
+	g := newLeafGetter(t, Config{
+		CACertPath: tc.CAPath,
+		CertPath:   tc.ClientCert,
+		KeyPath:    tc.ClientKey,
+	}, time.Nanosecond, func(ev ReloadEvent) {
+		mu.Lock()
+		if ev.Err != nil {
+			failed++
+		}
+		mu.Unlock()
+	})
+
+	before := clientLeaf(t, g)
+
+	if err := os.WriteFile(tc.ClientCert, []byte("not a pem"), 0o600); err != nil {
+		t.Fatalf("write bad cert: %v", err)
+	}
+	mtime := time.Now().Add(time.Minute)
+	if err := os.Chtimes(tc.ClientCert, mtime, mtime); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	if after := clientLeaf(t, g); !bytes.Equal(before, after) {
+		t.Fatalf("expected previous leaf after failed reload")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if failed != 1 {
+		t.Fatalf("expected one logged failure, got %d", failed)
+	}
+}
+
+func TestReloadingTLSConfigClient_ChecksAtMostOncePerInterval(t *testing.T) {
+	tc := createTempCerts(t)
+	defer os.RemoveAll(tc.Dir)
+	next := createTempCerts(t)
+	defer os.RemoveAll(next.Dir)
+
+	g := newLeafGetter(t, Config{
+		CACertPath: tc.CAPath,
+		CertPath:   tc.ClientCert,
+		KeyPath:    tc.ClientKey,
+	}, time.Hour, nil)
+
+	before := clientLeaf(t, g)
+	mtime := time.Now().Add(time.Minute)
+	replaceFile(t, tc.ClientCert, next.ClientCert, mtime)
+	replaceFile(t, tc.ClientKey, next.ClientKey, mtime)
+
+	if after := clientLeaf(t, g); !bytes.Equal(before, after) {
+		t.Fatalf("files must not be re-checked before the reload interval elapses")
+	}
+}
+
+func TestReloadingTLSConfigClient_InvalidConfig(t *testing.T) {
+	if _, err := ReloadingTLSConfigClient(Config{}, time.Second); err == nil {
+		t.Fatalf("expected error for empty config")
+	}
+}
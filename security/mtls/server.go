@@ -41,3 +41,21 @@ func TLSConfigServer(c Config) (*tls.Config, *Reloader, error) {
 
 	return tlsConf, r, nil
 }
+
+// ServerTLSConfig builds a static server tls.Config that requires and verifies
+// client certificates against Config.CACertPath (TLS 1.2+). Unlike TLSConfigServer
+// it never reloads files; Config.ReloadInterval is ignored.
+func ServerTLSConfig(c Config) (*tls.Config, error) {
+	b, err := loadBundle(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:             tls.VersionTLS12,
+		ClientAuth:             tls.RequireAndVerifyClientCert,
+		ClientCAs:              b.rootPool,
+		Certificates:           []tls.Certificate{b.cert},
+		SessionTicketsDisabled: true,
+	}, nil
+}
@@ -1,6 +1,8 @@
 package mtls
 
 import (
+	"crypto/tls"
+	"net"
 	"os"
 	"testing"
 )
@@ -37,3 +39,72 @@ func TestTLSConfigServer_OK(t *testing.T) {
 		t.Fatalf("GetConfigForClient callback is nil")
 	}
 }
+
+func TestServerTLSConfig_OK(t *testing.T) {
+	tc := createTempCerts(t)
+	defer os.RemoveAll(tc.Dir)
+
+	conf, err := ServerTLSConfig(Config{
+		CACertPath: tc.CAPath,
+		CertPath:   tc.ServerCert,
+		KeyPath:    tc.ServerKey,
+	})
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	if conf.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Fatalf("ClientAuth = %v, want RequireAndVerifyClientCert", conf.ClientAuth)
+	}
+	if conf.ClientCAs == nil {
+		t.Fatalf("ClientCAs is nil")
+	}
+	if conf.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("MinVersion = %x, want TLS 1.2", conf.MinVersion)
+	}
+	if len(conf.Certificates) != 1 {
+		t.Fatalf("expected one server certificate, got %d", len(conf.Certificates))
+	}
+}
+
+func TestServerTLSConfig_Handshake(t *testing.T) {
+	tc := createTempCerts(t)
+	defer os.RemoveAll(tc.Dir)
+
+	srvConf, err := ServerTLSConfig(Config{CACertPath: tc.CAPath, CertPath: tc.ServerCert, KeyPath: tc.ServerKey})
+	if err != nil {
+		t.Fatalf("ServerTLSConfig: %v", err)
+	}
+	cliConf, _, err := TLSConfigClient(Config{
+		CACertPath: tc.CAPath,
+		CertPath:   tc.ClientCert,
+		KeyPath:    tc.ClientKey,
+		ServerName: "server.test.internal",
+	})
+	if err != nil {
+		t.Fatalf("TLSConfigClient: %v", err)
+	}
+
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	srv := tls.Server(c1, srvConf)
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Handshake() }()
+
+	if err := tls.Client(c2, cliConf).Handshake(); err != nil {
+		t.Fatalf("client handshake: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("server handshake: %v", err)
+	}
+	if peers := srv.ConnectionState().PeerCertificates; len(peers) == 0 {
+		t.Fatalf("server did not receive a client certificate")
+	}
+}
+
+func TestServerTLSConfig_MissingFiles(t *testing.T) {
+	if _, err := ServerTLSConfig(Config{CACertPath: "/nonexistent/ca.pem"}); err == nil {
+		t.Fatalf("expected error for missing files")
+	}
+}
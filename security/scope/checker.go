@@ -1,5 +1,7 @@
 package scope
 
+import "strings"
+
 // Simple helpers to evaluate scopes declared in JWT claims.
 
 func Index(scopes []string) map[string]struct{} {
@@ -35,3 +37,62 @@ func HasAny(scopes []string, any ...string) bool {
 	}
 	return false
 }
+
+// Matcher reports whether a granted scope satisfies a required one.
+type Matcher func(granted, required string) bool
+
+// Exact is the Matcher used by HasAll/HasAny.
+func Exact(granted, required string) bool { return granted == required }
+
+// MatchPattern treats granted "*" as match-all and a trailing ":*" as a prefix
+// wildcard ("wallet:*" satisfies "wallet:read" and "wallet:tx:read", not "wallet").
+// Any other granted scope must match exactly; required scopes are never patterns.
+func MatchPattern(granted, required string) bool {
+	if granted == required || granted == "*" {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(granted, "*")
+	return ok && strings.HasSuffix(prefix, ":") && len(required) > len(prefix) && strings.HasPrefix(required, prefix)
+}
+
+// HasAllFunc is HasAll with a custom Matcher.
+func HasAllFunc(scopes []string, match Matcher, need ...string) bool {
+	for _, n := range need {
+		if !grants(scopes, match, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAnyFunc is HasAny with a custom Matcher.
+func HasAnyFunc(scopes []string, match Matcher, any ...string) bool {
+	if len(any) == 0 {
+		return true
+	}
+	for _, n := range any {
+		if grants(scopes, match, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAllPattern is HasAll with wildcard scopes (see MatchPattern).
+func HasAllPattern(scopes []string, need ...string) bool {
+	return HasAllFunc(scopes, MatchPattern, need...)
+}
+
+// HasAnyPattern is HasAny with wildcard scopes (see MatchPattern).
+func HasAnyPattern(scopes []string, any ...string) bool {
+	return HasAnyFunc(scopes, MatchPattern, any...)
+}
+
+func grants(scopes []string, match Matcher, required string) bool {
+	for _, s := range scopes {
+		if match(s, required) {
+			return true
+		}
+	}
+	return false
+}
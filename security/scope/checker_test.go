@@ -100,3 +100,84 @@ func TestHasAny(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchPattern(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		granted, required string
+		want              bool
+	}{
+		{"wallet:read", "wallet:read", true},
+		{"wallet:*", "wallet:read", true},
+		{"wallet:*", "wallet:tx:read", true},
+		{"wallet:*", "wallet:*", true},
+		{"wallet:*", "payments:create", false},
+		{"wallet:*", "wallet", false},
+		{"wallet:*", "wallet:", false},
+		{"wallet:*", "walletx:read", false},
+		{"*", "payments:create", true},
+		{"wallet*", "wallet:read", false},
+		{"wallet:read", "wallet:*", false},
+	}
+	for _, tt := range tests {
+		if got := scope.MatchPattern(tt.granted, tt.required); got != tt.want {
+			t.Fatalf("MatchPattern(%q, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestHasAllPattern(t *testing.T) {
+	t.Parallel()
+
+	scopes := []string{"wallet:*", "profile:read"}
+	if !scope.HasAllPattern(scopes, "wallet:read", "wallet:write", "profile:read") {
+		t.Fatal("wallet:* must satisfy wallet:read and wallet:write")
+	}
+	if scope.HasAllPattern(scopes, "wallet:read", "payments:create") {
+		t.Fatal("wallet:* must not satisfy payments:create")
+	}
+	if !scope.HasAllPattern(scopes) {
+		t.Fatal("empty need must be satisfied")
+	}
+	if !scope.HasAllPattern([]string{"*"}, "payments:create", "wallet:read") {
+		t.Fatal("* must satisfy everything")
+	}
+}
+
+func TestHasAnyPattern(t *testing.T) {
+	t.Parallel()
+
+	scopes := []string{"wallet:*"}
+	if !scope.HasAnyPattern(scopes, "payments:create", "wallet:read") {
+		t.Fatal("wallet:* must satisfy wallet:read")
+	}
+	if scope.HasAnyPattern(scopes, "payments:create") {
+		t.Fatal("wallet:* must not satisfy payments:create")
+	}
+	if !scope.HasAnyPattern(nil) {
+		t.Fatal("empty any must be satisfied")
+	}
+}
+
+func TestHasAll_StaysExact(t *testing.T) {
+	t.Parallel()
+
+	if scope.HasAll([]string{"wallet:*"}, "wallet:read") {
+		t.Fatal("HasAll must not expand wildcards")
+	}
+	if scope.HasAny([]string{"*"}, "wallet:read") {
+		t.Fatal("HasAny must not expand wildcards")
+	}
+}
+
+func TestHasAllFunc_Exact(t *testing.T) {
+	t.Parallel()
+
+	if !scope.HasAllFunc([]string{"a", "b"}, scope.Exact, "a", "b") {
+		t.Fatal("expected exact match")
+	}
+	if scope.HasAnyFunc([]string{"a:*"}, scope.Exact, "a:b") {
+		t.Fatal("Exact must not expand wildcards")
+	}
+}
@@ -3,7 +3,9 @@ package creds
 import (
 	"crypto/tls"
 	"errors"
+	"time"
 
+	"github.com/vortex-fintech/go-lib/security/mtls"
 	"google.golang.org/grpc/credentials"
 )
 
@@ -55,3 +57,15 @@ func ClientTransportCredentials(tlsConf *tls.Config, opt ClientOptions) (credent
 
 	return credentials.NewTLS(tlsConf), nil
 }
+
+// ReloadingCredentials returns client mTLS credentials that pick up rotated
+// CA/cert/key files on the next handshake, checking at most once per reload
+// (see mtls.ReloadingTLSConfigClient). No background goroutine is started.
+func ReloadingCredentials(cfg mtls.Config, reload time.Duration) (credentials.TransportCredentials, error) {
+	tlsConf, err := mtls.ReloadingTLSConfigClient(cfg, reload)
+	if err != nil {
+		return nil, err
+	}
+	// Roots are verified in VerifyConnection against the current bundle.
+	return ClientTransportCredentials(tlsConf, ClientOptions{SkipRootCAValidation: true})
+}
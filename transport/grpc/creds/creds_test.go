@@ -4,7 +4,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"testing"
+	"time"
 
+	"github.com/vortex-fintech/go-lib/security/mtls"
 	"github.com/vortex-fintech/go-lib/transport/grpc/creds"
 )
 
@@ -167,3 +169,12 @@ func TestClientTransportCredentials(t *testing.T) {
 		})
 	}
 }
+
+func TestReloadingCredentials_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	c, err := creds.ReloadingCredentials(mtls.Config{CACertPath: "/nonexistent/ca.pem"}, time.Second)
+	if err == nil || c != nil {
+		t.Fatalf("expected error for unreadable files, got (%v, %v)", c, err)
+	}
+}
@@ -2,12 +2,14 @@ package dial
 
 import (
 	"context"
+	"time"
 
 	"github.com/vortex-fintech/go-lib/security/mtls"
 	"github.com/vortex-fintech/go-lib/transport/grpc/creds"
 
 	"google.golang.org/grpc"
 	gbackoff "google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/keepalive"
 )
 
 type Options struct {
@@ -19,8 +21,20 @@ type Options struct {
 
 	MaxRecvMsgSize int
 	MaxSendMsgSize int
+
+	// Keepalive pings idle connections to detect dead peers. Zero Time uses DefaultKeepalive().
+	Keepalive keepalive.ClientParameters
+	// DialTimeout bounds a single connection attempt (gRPC MinConnectTimeout). Zero means 3s.
+	DialTimeout time.Duration
+
+	// UnaryInterceptors and StreamInterceptors run in slice order: the first one is
+	// the outermost and sees the call first.
+	UnaryInterceptors  []grpc.UnaryClientInterceptor
+	StreamInterceptors []grpc.StreamClientInterceptor
 }
 
+const defaultDialTimeout = 3 * time.Second
+
 func DefaultBackoff() gbackoff.Config {
 	return gbackoff.Config{
 		BaseDelay:  100e6,
@@ -30,6 +44,15 @@ func DefaultBackoff() gbackoff.Config {
 	}
 }
 
+// DefaultKeepalive pings every 5m (the default server EnforcementPolicy.MinTime)
+// and drops the connection if no ack arrives within 20s.
+func DefaultKeepalive() keepalive.ClientParameters {
+	return keepalive.ClientParameters{
+		Time:    5 * time.Minute,
+		Timeout: 20 * time.Second,
+	}
+}
+
 func NewClient(ctx context.Context, target string, opt Options) (*grpc.ClientConn, error) {
 	tlsConf, _, err := mtls.TLSConfigClient(opt.MTLS)
 	if err != nil {
@@ -57,12 +80,27 @@ func NewClient(ctx context.Context, target string, opt Options) (*grpc.ClientCon
 		maxSend = 16 << 20
 	}
 
+	ka := opt.Keepalive
+	if ka.Time == 0 {
+		def := DefaultKeepalive()
+		ka.Time = def.Time
+		if ka.Timeout == 0 {
+			ka.Timeout = def.Timeout
+		}
+	}
+
+	dialTimeout := opt.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = defaultDialTimeout
+	}
+
 	opts := []grpc.DialOption{
 		grpc.WithTransportCredentials(cred),
 		grpc.WithConnectParams(grpc.ConnectParams{
 			Backoff:           bc,
-			MinConnectTimeout: 3e9,
+			MinConnectTimeout: dialTimeout,
 		}),
+		grpc.WithKeepaliveParams(ka),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(maxRecv),
 			grpc.MaxCallSendMsgSize(maxSend),
@@ -74,6 +112,12 @@ func NewClient(ctx context.Context, target string, opt Options) (*grpc.ClientCon
 	if opt.InitialConn > 0 {
 		opts = append(opts, grpc.WithInitialConnWindowSize(opt.InitialConn))
 	}
+	if len(opt.UnaryInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(opt.UnaryInterceptors...))
+	}
+	if len(opt.StreamInterceptors) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(opt.StreamInterceptors...))
+	}
 
 	return grpc.NewClient(target, opts...)
 }
@@ -15,7 +15,11 @@ import (
 
 	"github.com/vortex-fintech/go-lib/security/mtls"
 	"github.com/vortex-fintech/go-lib/transport/grpc/dial"
+	"google.golang.org/grpc"
 	gbackoff "google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 type testCerts struct {
@@ -301,3 +305,145 @@ func TestDefaultBackoff(t *testing.T) {
 		t.Fatalf("MaxDelay: got %v, want %v", bc.MaxDelay, 2e9)
 	}
 }
+
+func TestNewClient_WithKeepaliveAndDialTimeout(t *testing.T) {
+	t.Parallel()
+
+	certs := createTempCerts(t)
+	defer os.RemoveAll(certs.Dir)
+
+	opt := dial.Options{
+		MTLS: mtls.Config{
+			CACertPath: certs.CAPath,
+			CertPath:   certs.ClientCert,
+			KeyPath:    certs.ClientKey,
+			ServerName: "server.test.internal",
+		},
+		Keepalive: keepalive.ClientParameters{
+			Time:                30 * time.Second,
+			Timeout:             5 * time.Second,
+			PermitWithoutStream: true,
+		},
+		DialTimeout: 500 * time.Millisecond,
+	}
+
+	conn, err := dial.NewClient(context.Background(), "passthrough:///localhost:0", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+}
+
+func TestNewClient_KeepaliveTimeoutOnly(t *testing.T) {
+	t.Parallel()
+
+	certs := createTempCerts(t)
+	defer os.RemoveAll(certs.Dir)
+
+	opt := dial.Options{
+		MTLS: mtls.Config{
+			CACertPath: certs.CAPath,
+			CertPath:   certs.ClientCert,
+			KeyPath:    certs.ClientKey,
+			ServerName: "server.test.internal",
+		},
+		Keepalive: keepalive.ClientParameters{Timeout: 3 * time.Second},
+	}
+
+	conn, err := dial.Dial("passthrough:///localhost:0", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+}
+
+func TestDefaultKeepalive(t *testing.T) {
+	t.Parallel()
+
+	ka := dial.DefaultKeepalive()
+	if ka.Time != 5*time.Minute {
+		t.Fatalf("Time: got %v, want %v", ka.Time, 5*time.Minute)
+	}
+	if ka.Timeout != 20*time.Second {
+		t.Fatalf("Timeout: got %v, want %v", ka.Timeout, 20*time.Second)
+	}
+	if ka.PermitWithoutStream {
+		t.Fatalf("PermitWithoutStream: got true, want false")
+	}
+}
+
+func TestNewClient_UnaryInterceptorsInOrder(t *testing.T) {
+	t.Parallel()
+
+	certs := createTempCerts(t)
+	defer os.RemoveAll(certs.Dir)
+
+	var calls []string
+	record := func(name string, stop bool) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			calls = append(calls, name+":"+method)
+			if stop {
+				return status.Error(codes.Unavailable, "stopped by "+name)
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+
+	opt := dial.Options{
+		MTLS: mtls.Config{
+			CACertPath: certs.CAPath,
+			CertPath:   certs.ClientCert,
+			KeyPath:    certs.ClientKey,
+			ServerName: "server.test.internal",
+		},
+		UnaryInterceptors: []grpc.UnaryClientInterceptor{record("first", false), record("second", true)},
+	}
+
+	conn, err := dial.NewClient(context.Background(), "passthrough:///localhost:0", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	err = conn.Invoke(context.Background(), "/svc.Test/Ping", &struct{}{}, &struct{}{})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected interceptor error, got %v", err)
+	}
+	if len(calls) != 2 || calls[0] != "first:/svc.Test/Ping" || calls[1] != "second:/svc.Test/Ping" {
+		t.Fatalf("unexpected interceptor calls: %v", calls)
+	}
+}
+
+func TestNewClient_StreamInterceptor(t *testing.T) {
+	t.Parallel()
+
+	certs := createTempCerts(t)
+	defer os.RemoveAll(certs.Dir)
+
+	var called string
+	opt := dial.Options{
+		MTLS: mtls.Config{
+			CACertPath: certs.CAPath,
+			CertPath:   certs.ClientCert,
+			KeyPath:    certs.ClientKey,
+			ServerName: "server.test.internal",
+		},
+		StreamInterceptors: []grpc.StreamClientInterceptor{
+			func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+				called = method
+				return nil, status.Error(codes.Unavailable, "stopped")
+			},
+		},
+	}
+
+	conn, err := dial.NewClient(context.Background(), "passthrough:///localhost:0", opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+
+	_, err = conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/svc.Test/Watch")
+	if status.Code(err) != codes.Unavailable || called != "/svc.Test/Watch" {
+		t.Fatalf("expected stream interceptor to run, got method=%q err=%v", called, err)
+	}
+}
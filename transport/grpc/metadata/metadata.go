@@ -4,13 +4,22 @@ import (
 	"context"
 	"strings"
 
+	"github.com/google/uuid"
 	gmd "google.golang.org/grpc/metadata"
 )
 
 const (
-	HeaderAuthorization = "authorization" // "Bearer <token>"
-	HeaderPoP           = "x-pop"         // x5t#S256 клиента (mTLS PoP)
-	HeaderAZP           = "x-azp"         // authorized party (источник клиента)
+	HeaderAuthorization = "authorization"             // "Bearer <token>"
+	HeaderGatewayAuth   = "grpcgateway-authorization" // Authorization, проброшенный grpc-gateway
+	HeaderPoP           = "x-pop"                     // x5t#S256 клиента (mTLS PoP)
+	HeaderAZP           = "x-azp"                     // authorized party (источник клиента)
+	HeaderUserID        = "x-user-id"                 // UUID пользователя (sub)
+	HeaderWalletID      = "x-wallet-id"               // wallet_id из OBO-claims
+	HeaderScopes        = "x-scopes"                  // скоупы через пробел
+	HeaderSessionID     = "x-session-id"              // sid
+	HeaderDeviceID      = "x-device-id"               // device_id
+	HeaderRequestID     = "x-request-id"              // id запроса (один вызов)
+	HeaderCorrelationID = "x-correlation-id"          // сквозной id цепочки (BaseEvent.CorrelationID)
 )
 
 // WithBearer добавляет/заменяет Authorization: Bearer <token>.
@@ -43,6 +52,111 @@ func WithAZP(ctx context.Context, azp string) context.Context {
 	return mergeOutgoing(ctx, map[string]string{HeaderAZP: a})
 }
 
+// WithUserID добавляет X-User-ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return withValue(ctx, HeaderUserID, userID)
+}
+
+// WithWalletID добавляет X-Wallet-ID.
+func WithWalletID(ctx context.Context, walletID string) context.Context {
+	return withValue(ctx, HeaderWalletID, walletID)
+}
+
+// WithScopes добавляет X-Scopes (скоупы через пробел, пустые отбрасываются).
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	out := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return withValue(ctx, HeaderScopes, strings.Join(out, " "))
+}
+
+// WithSessionID добавляет X-Session-ID.
+func WithSessionID(ctx context.Context, sid string) context.Context {
+	return withValue(ctx, HeaderSessionID, sid)
+}
+
+// WithDeviceID добавляет X-Device-ID.
+func WithDeviceID(ctx context.Context, deviceID string) context.Context {
+	return withValue(ctx, HeaderDeviceID, deviceID)
+}
+
+// WithRequestID добавляет X-Request-ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return withValue(ctx, HeaderRequestID, id)
+}
+
+// WithCorrelationID добавляет X-Correlation-ID.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return withValue(ctx, HeaderCorrelationID, id)
+}
+
+// RequestID читает X-Request-ID (приоритет incoming).
+func RequestID(ctx context.Context) string {
+	return strings.TrimSpace(Get(ctx, HeaderRequestID))
+}
+
+// CorrelationID читает X-Correlation-ID (приоритет incoming).
+func CorrelationID(ctx context.Context) string {
+	return strings.TrimSpace(Get(ctx, HeaderCorrelationID))
+}
+
+// EnsureRequestID берёт X-Request-ID из контекста или генерирует UUID
+// и кладёт его в outgoing MD, чтобы он ушёл дальше по цепочке.
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	id := RequestID(ctx)
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return WithRequestID(ctx, id), id
+}
+
+// Scopes читает X-Scopes и разбивает по пробелам.
+func Scopes(ctx context.Context) []string {
+	v := strings.Fields(Get(ctx, HeaderScopes))
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// GetBearer возвращает токен из входящего Authorization: Bearer <token>
+// (фолбэк: grpcgateway-authorization). Схема проверяется без учёта регистра.
+// Читается только incoming MD: токен аутентифицирует вызывающего.
+func GetBearer(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	md, ok := gmd.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	vals := md.Get(HeaderAuthorization)
+	if len(vals) == 0 {
+		vals = md.Get(HeaderGatewayAuth)
+	}
+	if len(vals) == 0 {
+		return "", false
+	}
+	return ParseBearer(vals[0])
+}
+
+// ParseBearer отрезает схему "Bearer " (без учёта регистра) и возвращает токен.
+func ParseBearer(v string) (string, bool) {
+	v = strings.TrimSpace(v)
+	const prefix = "bearer "
+	if len(v) <= len(prefix) || !strings.EqualFold(v[:len(prefix)], prefix) {
+		return "", false
+	}
+	tok := strings.TrimSpace(v[len(prefix):])
+	if tok == "" {
+		return "", false
+	}
+	return tok, true
+}
+
 // Get читает одно значение ключа из incoming/outgoing MD (приоритет incoming).
 func Get(ctx context.Context, key string) string {
 	if ctx == nil {
@@ -61,6 +175,20 @@ func Get(ctx context.Context, key string) string {
 	return ""
 }
 
+// GetUUID читает ключ через Get и парсит его как UUID (пробелы по краям отбрасываются).
+// ok=false, если заголовка нет или значение не UUID.
+func GetUUID(ctx context.Context, key string) (uuid.UUID, bool) {
+	v := strings.TrimSpace(Get(ctx, key))
+	if v == "" {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(v)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
 func GetAll(ctx context.Context, key string) []string {
 	if ctx == nil {
 		return nil
@@ -78,6 +206,14 @@ func GetAll(ctx context.Context, key string) []string {
 	return nil
 }
 
+func withValue(ctx context.Context, key, value string) context.Context {
+	v := strings.TrimSpace(value)
+	if v == "" {
+		return ctx
+	}
+	return mergeOutgoing(ctx, map[string]string{key: v})
+}
+
 // mergeOutgoing мерджит ключи в OutgoingContext (перезаписывая одноимённые).
 func mergeOutgoing(ctx context.Context, kv map[string]string) context.Context {
 	if ctx == nil {
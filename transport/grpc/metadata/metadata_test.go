@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/vortex-fintech/go-lib/transport/grpc/metadata"
 	gmd "google.golang.org/grpc/metadata"
 )
@@ -247,6 +248,12 @@ func TestConstants(t *testing.T) {
 	if metadata.HeaderAZP != "x-azp" {
 		t.Fatalf("HeaderAZP: got %q", metadata.HeaderAZP)
 	}
+	if metadata.HeaderRequestID != "x-request-id" {
+		t.Fatalf("HeaderRequestID: got %q", metadata.HeaderRequestID)
+	}
+	if metadata.HeaderCorrelationID != "x-correlation-id" {
+		t.Fatalf("HeaderCorrelationID: got %q", metadata.HeaderCorrelationID)
+	}
 }
 
 func TestWithHelpers_NilContext(t *testing.T) {
@@ -278,3 +285,247 @@ func TestGetAndGetAll_NilContext(t *testing.T) {
 		t.Fatalf("expected nil slice, got %v", got)
 	}
 }
+
+func TestWithClaimHeaders(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.WithUserID(context.Background(), " 550e8400-e29b-41d4-a716-446655440000 ")
+	ctx = metadata.WithWalletID(ctx, "w-1")
+	ctx = metadata.WithSessionID(ctx, "sid-1")
+	ctx = metadata.WithDeviceID(ctx, "dev-1")
+	ctx = metadata.WithScopes(ctx, []string{"wallet:read", " ", "payments:create"})
+
+	md, ok := gmd.FromOutgoingContext(ctx)
+	if !ok {
+		t.Fatalf("expected outgoing metadata")
+	}
+	want := map[string]string{
+		"x-user-id":    "550e8400-e29b-41d4-a716-446655440000",
+		"x-wallet-id":  "w-1",
+		"x-session-id": "sid-1",
+		"x-device-id":  "dev-1",
+		"x-scopes":     "wallet:read payments:create",
+	}
+	for k, v := range want {
+		if got := md.Get(k); len(got) != 1 || got[0] != v {
+			t.Fatalf("%s: want %q, got %v", k, v, got)
+		}
+	}
+
+	scopes := metadata.Scopes(ctx)
+	if len(scopes) != 2 || scopes[0] != "wallet:read" || scopes[1] != "payments:create" {
+		t.Fatalf("unexpected scopes: %v", scopes)
+	}
+}
+
+func TestWithClaimHeaders_EmptyIgnored(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.WithUserID(context.Background(), "")
+	ctx = metadata.WithWalletID(ctx, "  ")
+	ctx = metadata.WithScopes(ctx, []string{" ", ""})
+	if _, ok := gmd.FromOutgoingContext(ctx); ok {
+		t.Fatalf("expected no metadata")
+	}
+	if got := metadata.Scopes(ctx); got != nil {
+		t.Fatalf("expected nil scopes, got %v", got)
+	}
+}
+
+func TestWithRequestAndCorrelationID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		id        string
+		wantEmpty bool
+		wantValue string
+	}{
+		{"empty string", "", true, ""},
+		{"whitespace only", "   ", true, ""},
+		{"plain id", "req-1", false, "req-1"},
+		{"id with whitespace", "  req-1  ", false, "req-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := metadata.WithRequestID(context.Background(), tt.id)
+			ctx = metadata.WithCorrelationID(ctx, tt.id)
+			if tt.wantEmpty {
+				if _, ok := gmd.FromOutgoingContext(ctx); ok {
+					t.Fatalf("expected no metadata, got some")
+				}
+				if got := metadata.RequestID(ctx); got != "" {
+					t.Fatalf("expected empty request id, got %q", got)
+				}
+				return
+			}
+
+			md, ok := gmd.FromOutgoingContext(ctx)
+			if !ok {
+				t.Fatalf("expected metadata, got none")
+			}
+			for _, key := range []string{"x-request-id", "x-correlation-id"} {
+				if v := md.Get(key); len(v) != 1 || v[0] != tt.wantValue {
+					t.Fatalf("%s: got %v, want %q", key, v, tt.wantValue)
+				}
+			}
+			if got := metadata.RequestID(ctx); got != tt.wantValue {
+				t.Fatalf("RequestID: got %q, want %q", got, tt.wantValue)
+			}
+			if got := metadata.CorrelationID(ctx); got != tt.wantValue {
+				t.Fatalf("CorrelationID: got %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestRequestID_IncomingPriority(t *testing.T) {
+	t.Parallel()
+
+	ctx := gmd.NewIncomingContext(context.Background(), gmd.Pairs("x-request-id", "in-1", "x-correlation-id", "corr-in"))
+	ctx = metadata.WithRequestID(ctx, "out-1")
+	if got := metadata.RequestID(ctx); got != "in-1" {
+		t.Fatalf("expected incoming request id, got %q", got)
+	}
+	if got := metadata.CorrelationID(ctx); got != "corr-in" {
+		t.Fatalf("expected incoming correlation id, got %q", got)
+	}
+}
+
+func TestEnsureRequestID(t *testing.T) {
+	t.Parallel()
+
+	ctx, id := metadata.EnsureRequestID(context.Background())
+	if _, err := uuid.Parse(id); err != nil {
+		t.Fatalf("expected generated UUID, got %q: %v", id, err)
+	}
+	md, _ := gmd.FromOutgoingContext(ctx)
+	if v := md.Get("x-request-id"); len(v) != 1 || v[0] != id {
+		t.Fatalf("expected outgoing request id %q, got %v", id, v)
+	}
+
+	again, id2 := metadata.EnsureRequestID(ctx)
+	if id2 != id || metadata.RequestID(again) != id {
+		t.Fatalf("expected existing id %q to be kept, got %q", id, id2)
+	}
+
+	in := gmd.NewIncomingContext(context.Background(), gmd.Pairs("x-request-id", " req-in "))
+	out, id3 := metadata.EnsureRequestID(in)
+	if id3 != "req-in" {
+		t.Fatalf("expected incoming id, got %q", id3)
+	}
+	md, _ = gmd.FromOutgoingContext(out)
+	if v := md.Get("x-request-id"); len(v) != 1 || v[0] != "req-in" {
+		t.Fatalf("expected incoming id propagated to outgoing, got %v", v)
+	}
+}
+
+func TestRequestIDHelpers_NilContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.WithRequestID(nil, "req-1")
+	if got := metadata.Get(ctx, "x-request-id"); got != "req-1" {
+		t.Fatalf("unexpected x-request-id: %q", got)
+	}
+
+	ctx = metadata.WithCorrelationID(nil, "corr-1")
+	if got := metadata.Get(ctx, "x-correlation-id"); got != "corr-1" {
+		t.Fatalf("unexpected x-correlation-id: %q", got)
+	}
+
+	if got := metadata.RequestID(nil); got != "" {
+		t.Fatalf("expected empty request id, got %q", got)
+	}
+	if got := metadata.CorrelationID(nil); got != "" {
+		t.Fatalf("expected empty correlation id, got %q", got)
+	}
+
+	ctx, id := metadata.EnsureRequestID(nil)
+	if id == "" || metadata.RequestID(ctx) != id {
+		t.Fatalf("expected generated id in context, got %q", id)
+	}
+}
+
+func TestGetBearer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		md     gmd.MD
+		want   string
+		wantOK bool
+	}{
+		{"valid bearer", gmd.Pairs("authorization", "Bearer tok-1"), "tok-1", true},
+		{"case-insensitive scheme", gmd.Pairs("authorization", "bEaReR tok-1"), "tok-1", true},
+		{"surrounding whitespace", gmd.Pairs("authorization", "  Bearer   tok-1  "), "tok-1", true},
+		{"wrong scheme", gmd.Pairs("authorization", "Basic dXNlcjpwYXNz"), "", false},
+		{"scheme only", gmd.Pairs("authorization", "Bearer "), "", false},
+		{"no scheme", gmd.Pairs("authorization", "tok-1"), "", false},
+		{"missing header", gmd.Pairs("x-pop", "thumb"), "", false},
+		{"gateway fallback", gmd.Pairs("grpcgateway-authorization", "Bearer gw-tok"), "gw-tok", true},
+		{"authorization wins over gateway", gmd.Pairs("authorization", "Bearer direct", "grpcgateway-authorization", "Bearer gw-tok"), "direct", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := gmd.NewIncomingContext(context.Background(), tt.md)
+			got, ok := metadata.GetBearer(ctx)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("got (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetBearer_IgnoresOutgoingAndNil(t *testing.T) {
+	t.Parallel()
+
+	if tok, ok := metadata.GetBearer(nil); ok || tok != "" {
+		t.Fatalf("expected no token for nil context, got %q", tok)
+	}
+	if tok, ok := metadata.GetBearer(metadata.WithBearer(context.Background(), "tok-1")); ok || tok != "" {
+		t.Fatalf("outgoing metadata must be ignored, got %q", tok)
+	}
+}
+
+func TestGetUUID(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	tests := []struct {
+		name   string
+		md     gmd.MD
+		want   uuid.UUID
+		wantOK bool
+	}{
+		{"valid", gmd.Pairs(metadata.HeaderUserID, id.String()), id, true},
+		{"surrounding whitespace", gmd.Pairs(metadata.HeaderUserID, "  "+id.String()+"  "), id, true},
+		{"malformed", gmd.Pairs(metadata.HeaderUserID, "not-a-uuid"), uuid.Nil, false},
+		{"whitespace only", gmd.Pairs(metadata.HeaderUserID, "   "), uuid.Nil, false},
+		{"missing", gmd.Pairs(metadata.HeaderWalletID, id.String()), uuid.Nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := gmd.NewIncomingContext(context.Background(), tt.md)
+			got, ok := metadata.GetUUID(ctx, metadata.HeaderUserID)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("got (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if got, ok := metadata.GetUUID(nil, metadata.HeaderUserID); ok || got != uuid.Nil {
+		t.Fatalf("nil context: got (%v, %v)", got, ok)
+	}
+	if got, ok := metadata.GetUUID(metadata.WithUserID(context.Background(), id.String()), metadata.HeaderUserID); !ok || got != id {
+		t.Fatalf("outgoing: got (%v, %v), want %v", got, ok, id)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	errs "github.com/vortex-fintech/go-lib/foundation/errors"
 	libjwt "github.com/vortex-fintech/go-lib/security/jwt"
+	grpcmd "github.com/vortex-fintech/go-lib/transport/grpc/metadata"
 )
 
 // тип для ключей контекста (не экспортируем, чтобы избежать коллизий)
@@ -112,3 +113,24 @@ func RequireWalletMatch(ctx context.Context, want string) error {
 	}
 	return nil
 }
+
+// OutgoingContextFromIdentity копирует Identity (и wallet_id/azp из claims, если есть)
+// в исходящие метаданные для вызова downstream-сервисов. Токен не пробрасывается.
+func OutgoingContextFromIdentity(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if id, ok := IdentityFrom(ctx); ok {
+		if id.UserID != uuid.Nil {
+			ctx = grpcmd.WithUserID(ctx, id.UserID.String())
+		}
+		ctx = grpcmd.WithScopes(ctx, id.Scopes)
+		ctx = grpcmd.WithSessionID(ctx, id.SID)
+		ctx = grpcmd.WithDeviceID(ctx, id.DeviceID)
+	}
+	if cl, ok := ClaimsFrom(ctx); ok && cl != nil {
+		ctx = grpcmd.WithWalletID(ctx, cl.WalletID)
+		ctx = grpcmd.WithAZP(ctx, cl.Azp)
+	}
+	return ctx
+}
@@ -0,0 +1,75 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	grpcmd "github.com/vortex-fintech/go-lib/transport/grpc/metadata"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestOutgoingContextFromIdentity(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+	cl := validClaims("thumb")
+	cl.Azp = "vortex-web"
+
+	ctx := WithIdentity(context.Background(), Identity{
+		UserID:   uid,
+		Scopes:   []string{"wallet:read", "payments:create"},
+		SID:      "sid-1",
+		DeviceID: "dev-1",
+	})
+	ctx = WithClaims(ctx, cl)
+
+	out := OutgoingContextFromIdentity(ctx)
+	md, ok := metadata.FromOutgoingContext(out)
+	if !ok {
+		t.Fatalf("expected outgoing metadata")
+	}
+	want := map[string]string{
+		grpcmd.HeaderUserID:    uid.String(),
+		grpcmd.HeaderScopes:    "wallet:read payments:create",
+		grpcmd.HeaderSessionID: "sid-1",
+		grpcmd.HeaderDeviceID:  "dev-1",
+		grpcmd.HeaderWalletID:  "w-1",
+		grpcmd.HeaderAZP:       "vortex-web",
+	}
+	for k, v := range want {
+		if got := md.Get(k); len(got) != 1 || got[0] != v {
+			t.Fatalf("%s: want %q, got %v", k, v, got)
+		}
+	}
+	if got := md.Get(grpcmd.HeaderAuthorization); len(got) != 0 {
+		t.Fatalf("authorization must not be propagated, got %v", got)
+	}
+}
+
+func TestOutgoingContextFromIdentity_PreservesExistingMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1")
+	ctx = WithIdentity(ctx, Identity{UserID: uuid.New()})
+
+	md, _ := metadata.FromOutgoingContext(OutgoingContextFromIdentity(ctx))
+	if got := md.Get("x-request-id"); len(got) != 1 || got[0] != "req-1" {
+		t.Fatalf("expected x-request-id preserved, got %v", got)
+	}
+	if got := md.Get(grpcmd.HeaderScopes); len(got) != 0 {
+		t.Fatalf("expected no scopes header, got %v", got)
+	}
+}
+
+func TestOutgoingContextFromIdentity_NoIdentity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	if out := OutgoingContextFromIdentity(ctx); out != ctx {
+		t.Fatalf("expected context unchanged")
+	}
+	if out := OutgoingContextFromIdentity(nil); out == nil {
+		t.Fatalf("expected non-nil context")
+	}
+}
@@ -1,7 +1,13 @@
 // go-lib/authz/helpers.go
 package authz
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
 
 // MapResolver — резолвер политик по полному имени метода.
 func MapResolver(m map[string]Policy) PolicyResolver {
@@ -13,6 +19,75 @@ func MapResolver(m map[string]Policy) PolicyResolver {
 	}
 }
 
+const defaultPolicyRefresh = time.Minute
+
+// ErrNilPolicyLoader — CachingResolver вызван без функции загрузки.
+var ErrNilPolicyLoader = errors.New("authz: policy loader is nil")
+
+type policySnapshot struct {
+	m        map[string]Policy
+	loadedAt time.Time
+}
+
+// CachingResolver — резолвер поверх периодически перезагружаемой карты политик.
+// Первая загрузка синхронная: если она не удалась, резолвер не создаётся и
+// возвращается ошибка. Дальше, когда снимок старше refresh, перезагрузка
+// запускается в фоне (не больше одной одновременно), а запросы обслуживаются из кеша.
+// При ошибке load остаётся последний удачный снимок. refresh <= 0 — одна минута.
+func CachingResolver(load func() (map[string]Policy, error), refresh time.Duration) (PolicyResolver, error) {
+	if load == nil {
+		return nil, ErrNilPolicyLoader
+	}
+	if refresh <= 0 {
+		refresh = defaultPolicyRefresh
+	}
+
+	snapshot := func() (*policySnapshot, error) {
+		m, err := load()
+		if err != nil {
+			return nil, err
+		}
+		cp := make(map[string]Policy, len(m))
+		for k, v := range m {
+			cp[k] = v
+		}
+		return &policySnapshot{m: cp, loadedAt: time.Now()}, nil
+	}
+
+	first, err := snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("authz: initial policy load: %w", err)
+	}
+
+	var (
+		cur     atomic.Pointer[policySnapshot]
+		loading atomic.Bool
+	)
+	cur.Store(first)
+
+	reload := func() {
+		defer loading.Store(false)
+		next, err := snapshot()
+		if err != nil {
+			prev := cur.Load()
+			cur.Store(&policySnapshot{m: prev.m, loadedAt: time.Now()})
+			return
+		}
+		cur.Store(next)
+	}
+
+	return func(fullMethod string) Policy {
+		snap := cur.Load()
+		if time.Since(snap.loadedAt) >= refresh && loading.CompareAndSwap(false, true) {
+			go reload()
+		}
+		if p, ok := snap.m[fullMethod]; ok {
+			return p
+		}
+		return Policy{}
+	}, nil
+}
+
 // MapSkipAuth — пропустить аутентификацию для методов из карты.
 func MapSkipAuth(allow map[string]struct{}) SkipAuthFunc {
 	return func(fullMethod string) bool {
@@ -0,0 +1,148 @@
+package authz
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type policyLoader struct {
+	mu    sync.Mutex
+	m     map[string]Policy
+	err   error
+	calls int
+}
+
+func (l *policyLoader) set(m map[string]Policy, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.m, l.err = m, err
+}
+
+func (l *policyLoader) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.calls
+}
+
+func (l *policyLoader) load() (map[string]Policy, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls++
+	return l.m, l.err
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met before deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCachingResolver_ServesInitialSnapshot(t *testing.T) {
+	t.Parallel()
+
+	l := &policyLoader{m: map[string]Policy{"/svc.A": {All: []string{"a:read"}}}}
+	r, err := CachingResolver(l.load, time.Hour)
+	if err != nil {
+		t.Fatalf("CachingResolver: %v", err)
+	}
+
+	if p := r("/svc.A"); len(p.All) != 1 || p.All[0] != "a:read" {
+		t.Fatalf("unexpected policy: %+v", p)
+	}
+	if p := r("/svc.Unknown"); len(p.All) != 0 || len(p.Any) != 0 {
+		t.Fatalf("expected zero policy, got %+v", p)
+	}
+	if l.count() != 1 {
+		t.Fatalf("expected single load, got %d", l.count())
+	}
+}
+
+func TestCachingResolver_RefreshesInBackground(t *testing.T) {
+	t.Parallel()
+
+	l := &policyLoader{m: map[string]Policy{"/svc.A": {All: []string{"a:read"}}}}
+	r, err := CachingResolver(l.load, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("CachingResolver: %v", err)
+	}
+
+	l.set(map[string]Policy{"/svc.A": {All: []string{"a:write"}}}, nil)
+	time.Sleep(20 * time.Millisecond)
+
+	waitFor(t, func() bool {
+		p := r("/svc.A")
+		return len(p.All) == 1 && p.All[0] == "a:write"
+	})
+}
+
+func TestCachingResolver_KeepsLastGoodOnError(t *testing.T) {
+	t.Parallel()
+
+	l := &policyLoader{m: map[string]Policy{"/svc.A": {Any: []string{"a:read"}}}}
+	r, err := CachingResolver(l.load, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("CachingResolver: %v", err)
+	}
+
+	l.set(nil, errors.New("control plane down"))
+	time.Sleep(20 * time.Millisecond)
+
+	r("/svc.A")
+	waitFor(t, func() bool { return l.count() >= 2 })
+	time.Sleep(20 * time.Millisecond)
+
+	if p := r("/svc.A"); len(p.Any) != 1 || p.Any[0] != "a:read" {
+		t.Fatalf("expected last good policy, got %+v", p)
+	}
+}
+
+func TestCachingResolver_InitialLoadError(t *testing.T) {
+	t.Parallel()
+
+	loadErr := errors.New("boom")
+	l := &policyLoader{err: loadErr}
+	r, err := CachingResolver(l.load, 10*time.Millisecond)
+	if !errors.Is(err, loadErr) {
+		t.Fatalf("expected initial load error, got %v", err)
+	}
+	if r != nil {
+		t.Fatal("expected nil resolver on initial load error")
+	}
+	if l.count() != 1 {
+		t.Fatalf("expected single load, got %d", l.count())
+	}
+}
+
+func TestCachingResolver_CopiesLoadedMap(t *testing.T) {
+	t.Parallel()
+
+	m := map[string]Policy{"/svc.A": {All: []string{"a:read"}}}
+	r, err := CachingResolver(func() (map[string]Policy, error) { return m, nil }, time.Hour)
+	if err != nil {
+		t.Fatalf("CachingResolver: %v", err)
+	}
+
+	delete(m, "/svc.A")
+	if p := r("/svc.A"); len(p.All) != 1 {
+		t.Fatalf("expected cached policy after source mutation, got %+v", p)
+	}
+}
+
+func TestCachingResolver_NilLoad(t *testing.T) {
+	t.Parallel()
+
+	r, err := CachingResolver(nil, time.Second)
+	if !errors.Is(err, ErrNilPolicyLoader) {
+		t.Fatalf("expected ErrNilPolicyLoader, got %v", err)
+	}
+	if r != nil {
+		t.Fatal("expected nil resolver")
+	}
+}
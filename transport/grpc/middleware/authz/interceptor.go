@@ -3,12 +3,15 @@ package authz
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	libjwt "github.com/vortex-fintech/go-lib/security/jwt"
 	scope "github.com/vortex-fintech/go-lib/security/scope"
+	grpcmd "github.com/vortex-fintech/go-lib/transport/grpc/metadata"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -17,9 +20,12 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Policy — требования метода: All/Any к скоупам, RequireRoles — роли, которые
+// должны быть все (Claims.HasRole) независимо от скоупов.
 type Policy struct {
-	All []string
-	Any []string
+	All          []string
+	Any          []string
+	RequireRoles []string
 }
 
 type PolicyResolver func(fullMethod string) Policy
@@ -37,12 +43,32 @@ type Config struct {
 	RequireScopes  bool
 	SeenJTI        func(string) bool
 	RequirePoP     bool
+	AllowNoPoP     bool
 	MTLSThumbprint func(ctx context.Context) string
 
 	RequiredScopes []string
 	ResolvePolicy  PolicyResolver
+	// ScopeMatcher decides whether a granted scope satisfies a required one.
+	// nil means exact matching; use scope.MatchPattern for "wallet:*" / "*" wildcards.
+	ScopeMatcher scope.Matcher
+	// RestrictScopesToAudience keeps only the scopes of Audience ("<Audience>:" prefix, see
+	// Claims.ScopesForAudience) for policy checks and Identity.Scopes, so a token carrying
+	// "payments:*" cannot satisfy a wallet policy. AudienceScopePrefixes overrides the default
+	// prefix; setting it also enables the restriction.
+	RestrictScopesToAudience bool
+	AudienceScopePrefixes    []string
 
 	SkipAuth SkipAuthFunc
+	// OptionalAuth — методы, где токен не обязателен: без authorization вызов идёт дальше
+	// анонимно (без Identity/Claims), а присланный токен проверяется полностью, и невалидный
+	// отклоняется. SkipAuth имеет приоритет.
+	OptionalAuth SkipAuthFunc
+
+	// VerifyCache skips Verifier.Verify for tokens verified earlier and not yet expired.
+	// nil (default) verifies every call.
+	VerifyCache *VerifyCache
+
+	IncludeErrorDetails bool
 }
 
 type AuthzResult struct {
@@ -52,6 +78,12 @@ type AuthzResult struct {
 
 var ErrInvalidConfig = errors.New("authz: invalid config")
 
+const (
+	ErrorDomain                  = "authz"
+	ErrorReasonInsufficientScope = "INSUFFICIENT_SCOPE"
+	ErrorReasonInsufficientRole  = "INSUFFICIENT_ROLE"
+)
+
 type ConfigValidationError struct {
 	Field string
 	Err   error
@@ -84,6 +116,9 @@ func ValidateConfig(cfg Config) error {
 	if strings.TrimSpace(cfg.Audience) == "" {
 		return &ConfigValidationError{Field: "Audience", Err: errors.New("must be set")}
 	}
+	if cfg.RequirePoP && cfg.AllowNoPoP {
+		return &ConfigValidationError{Field: "AllowNoPoP", Err: errors.New("conflicts with RequirePoP")}
+	}
 	return nil
 }
 
@@ -99,10 +134,14 @@ func Authorize(ctx context.Context, fullMethod string, cfg Config) (*AuthzResult
 
 	raw, err := bearerFromMD(ctx)
 	if err != nil {
+		noToken := err == errMissingMetadata || err == errMissingAuthorization
+		if noToken && cfg.OptionalAuth != nil && cfg.OptionalAuth(fullMethod) {
+			return nil, nil
+		}
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
-	cl, err := cfg.Verifier.Verify(ctx, raw)
+	cl, err := verify(ctx, cfg, raw)
 	if err != nil {
 		return nil, status.Error(codes.Unauthenticated, "invalid token")
 	}
@@ -111,9 +150,14 @@ func Authorize(ctx context.Context, fullMethod string, cfg Config) (*AuthzResult
 	if cfg.MTLSThumbprint != nil {
 		thumb = cfg.MTLSThumbprint(ctx)
 	}
-	if cfg.RequirePoP && thumb == "" {
+	// AllowNoPoP пропускает только токены без cnf; привязанный токен проверяется всегда.
+	bound := cl.Cnf != nil && cl.Cnf.X5tS256 != ""
+	if (cfg.RequirePoP || cfg.AllowNoPoP && bound) && thumb == "" {
 		return nil, status.Error(codes.Unauthenticated, "missing mTLS client certificate")
 	}
+	if cfg.AllowNoPoP && !bound {
+		thumb = ""
+	}
 
 	if err := libjwt.ValidateOBO(time.Now(), cl, libjwt.OBOValidateOptions{
 		WantAudience:   cfg.Audience,
@@ -138,14 +182,17 @@ func Authorize(ctx context.Context, fullMethod string, cfg Config) (*AuthzResult
 		return nil, status.Error(codes.Unauthenticated, libjwt.ErrBadSubject.Error())
 	}
 
-	sc := cl.EffectiveScopes()
+	sc := grantedScopes(cl, cfg)
 
 	var p Policy
 	if cfg.ResolvePolicy != nil {
 		p = cfg.ResolvePolicy(fullMethod)
 	}
-	if !satisfies(sc, p, cfg.RequiredScopes) {
-		return nil, status.Error(codes.PermissionDenied, "insufficient scope")
+	if !satisfies(sc, p, cfg.RequiredScopes, cfg.ScopeMatcher) {
+		return nil, insufficientScopeError(fullMethod, sc, p, cfg)
+	}
+	if missing := missingRoles(cl, p.RequireRoles); len(missing) > 0 {
+		return nil, insufficientRoleError(fullMethod, cl, p, missing, cfg)
 	}
 
 	return &AuthzResult{
@@ -204,36 +251,139 @@ type serverStream struct {
 
 func (s *serverStream) Context() context.Context { return s.ctx }
 
-func satisfies(have []string, p Policy, globalAll []string) bool {
-	if len(globalAll) > 0 && !scope.HasAll(have, globalAll...) {
+func grantedScopes(cl *libjwt.Claims, cfg Config) []string {
+	switch {
+	case len(cfg.AudienceScopePrefixes) > 0:
+		return cl.ScopesWithPrefix(cfg.AudienceScopePrefixes...)
+	case cfg.RestrictScopesToAudience:
+		return cl.ScopesForAudience(cfg.Audience)
+	default:
+		return cl.EffectiveScopes()
+	}
+}
+
+func satisfies(have []string, p Policy, globalAll []string, match scope.Matcher) bool {
+	if len(globalAll) > 0 && !hasAll(have, match, globalAll...) {
 		return false
 	}
-	if len(p.All) > 0 && !scope.HasAll(have, p.All...) {
+	if len(p.All) > 0 && !hasAll(have, match, p.All...) {
 		return false
 	}
-	if len(p.Any) > 0 && !scope.HasAny(have, p.Any...) {
+	if len(p.Any) > 0 && !hasAny(have, match, p.Any...) {
 		return false
 	}
 	return true
 }
 
+func hasAll(have []string, match scope.Matcher, need ...string) bool {
+	if match == nil {
+		return scope.HasAll(have, need...)
+	}
+	return scope.HasAllFunc(have, match, need...)
+}
+
+func hasAny(have []string, match scope.Matcher, any ...string) bool {
+	if match == nil {
+		return scope.HasAny(have, any...)
+	}
+	return scope.HasAnyFunc(have, match, any...)
+}
+
+func insufficientScopeError(fullMethod string, have []string, p Policy, cfg Config) error {
+	st := status.New(codes.PermissionDenied, "insufficient scope")
+	if !cfg.IncludeErrorDetails {
+		return st.Err()
+	}
+
+	requiredAll := make([]string, 0, len(cfg.RequiredScopes)+len(p.All))
+	requiredAll = append(requiredAll, cfg.RequiredScopes...)
+	requiredAll = append(requiredAll, p.All...)
+
+	var missing []string
+	seen := make(map[string]struct{}, len(requiredAll))
+	for _, s := range requiredAll {
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		if !hasAll(have, cfg.ScopeMatcher, s) {
+			missing = append(missing, s)
+		}
+	}
+
+	md := map[string]string{
+		"method":       fullMethod,
+		"required_all": strings.Join(requiredAll, " "),
+		"required_any": strings.Join(p.Any, " "),
+		"present":      strings.Join(have, " "),
+		"missing":      strings.Join(missing, " "),
+	}
+	if !hasAny(have, cfg.ScopeMatcher, p.Any...) {
+		md["missing_any"] = strings.Join(p.Any, " ")
+	}
+
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   ErrorReasonInsufficientScope,
+		Domain:   ErrorDomain,
+		Metadata: md,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+func missingRoles(cl *libjwt.Claims, required []string) []string {
+	var missing []string
+	for _, r := range required {
+		if !cl.HasRole(r) && !slices.Contains(missing, r) {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+func insufficientRoleError(fullMethod string, cl *libjwt.Claims, p Policy, missing []string, cfg Config) error {
+	st := status.New(codes.PermissionDenied, "insufficient role")
+	if !cfg.IncludeErrorDetails {
+		return st.Err()
+	}
+
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: ErrorReasonInsufficientRole,
+		Domain: ErrorDomain,
+		Metadata: map[string]string{
+			"method":         fullMethod,
+			"required_roles": strings.Join(p.RequireRoles, " "),
+			"present_roles":  strings.Join(cl.Roles, " "),
+			"missing_roles":  strings.Join(missing, " "),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// Токена нет вовсе (в отличие от присланного, но кривого) — для OptionalAuth.
+var (
+	errMissingMetadata      = errors.New("missing metadata")
+	errMissingAuthorization = errors.New("missing authorization")
+)
+
 func bearerFromMD(ctx context.Context) (string, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return "", errors.New("missing metadata")
+		return "", errMissingMetadata
 	}
-	vals := md.Get("authorization")
-	if len(vals) == 0 {
-		vals = md.Get("grpcgateway-authorization")
+	if len(md.Get(grpcmd.HeaderAuthorization)) == 0 && len(md.Get(grpcmd.HeaderGatewayAuth)) == 0 {
+		return "", errMissingAuthorization
 	}
-	if len(vals) == 0 {
-		return "", errors.New("missing authorization")
-	}
-	parts := strings.SplitN(strings.TrimSpace(vals[0]), " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+	tok, ok := grpcmd.GetBearer(ctx)
+	if !ok {
 		return "", errors.New("invalid authorization")
 	}
-	return parts[1], nil
+	return tok, nil
 }
 
 func MTLSThumbprintFromPeer(ctx context.Context) string {
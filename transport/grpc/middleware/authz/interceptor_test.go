@@ -7,6 +7,8 @@ import (
 	"time"
 
 	libjwt "github.com/vortex-fintech/go-lib/security/jwt"
+	"github.com/vortex-fintech/go-lib/security/scope"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -151,6 +153,75 @@ func TestUnaryServerInterceptor_SkipAuth(t *testing.T) {
 	}
 }
 
+func TestUnaryServerInterceptor_OptionalAuth(t *testing.T) {
+	t.Parallel()
+
+	newInterceptor := func(v *verifierStub) grpc.UnaryServerInterceptor {
+		return UnaryServerInterceptor(Config{
+			Verifier:       v,
+			Audience:       "wallet",
+			MTLSThumbprint: func(context.Context) string { return "thumb" },
+			OptionalAuth:   SliceSkipAuth("/svc.PublicRead"),
+		})
+	}
+	public := &grpc.UnaryServerInfo{FullMethod: "/svc.PublicRead"}
+	withToken := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+
+	var gotID, gotClaims bool
+	capture := func(ctx context.Context, req any) (any, error) {
+		_, gotID = IdentityFrom(ctx)
+		_, gotClaims = ClaimsFrom(ctx)
+		return req, nil
+	}
+
+	t.Run("no token proceeds anonymously", func(t *testing.T) {
+		v := &verifierStub{claims: validClaims("thumb")}
+		for _, ctx := range []context.Context{
+			context.Background(),
+			metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "r-1")),
+		} {
+			if _, err := newInterceptor(v)(ctx, struct{}{}, public, capture); err != nil {
+				t.Fatalf("expected anonymous call, got %v", err)
+			}
+			if gotID || gotClaims {
+				t.Fatal("anonymous call must not carry identity or claims")
+			}
+		}
+		if v.called != 0 {
+			t.Fatalf("verifier must not be called without a token, called %d", v.called)
+		}
+	})
+
+	t.Run("valid token sets identity", func(t *testing.T) {
+		v := &verifierStub{claims: validClaims("thumb")}
+		if _, err := newInterceptor(v)(withToken, struct{}{}, public, capture); err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if !gotID || !gotClaims || v.called != 1 {
+			t.Fatalf("expected verified identity, id=%v claims=%v called=%d", gotID, gotClaims, v.called)
+		}
+	})
+
+	t.Run("invalid token rejected", func(t *testing.T) {
+		v := &verifierStub{err: errors.New("bad signature")}
+		if _, err := newInterceptor(v)(withToken, struct{}{}, public, passHandler); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %v", err)
+		}
+		basic := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"))
+		if _, err := newInterceptor(v)(basic, struct{}{}, public, passHandler); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("malformed authorization must be rejected, got %v", err)
+		}
+	})
+
+	t.Run("other methods still require a token", func(t *testing.T) {
+		v := &verifierStub{claims: validClaims("thumb")}
+		_, err := newInterceptor(v)(context.Background(), struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Private"}, passHandler)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %v", err)
+		}
+	})
+}
+
 func TestUnaryServerInterceptor_InsufficientScope(t *testing.T) {
 	t.Parallel()
 
@@ -175,6 +246,112 @@ func TestUnaryServerInterceptor_InsufficientScope(t *testing.T) {
 	}
 }
 
+func TestUnaryServerInterceptor_InsufficientScope_NoDetailsByDefault(t *testing.T) {
+	t.Parallel()
+
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:       &verifierStub{claims: validClaims("thumb")},
+		Audience:       "wallet",
+		MTLSThumbprint: func(context.Context) string { return "thumb" },
+		RequiredScopes: []string{"admin:write"},
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", st.Code())
+	}
+	if len(st.Details()) != 0 {
+		t.Fatalf("expected no details, got %v", st.Details())
+	}
+}
+
+func TestUnaryServerInterceptor_InsufficientScope_IncludesErrorInfo(t *testing.T) {
+	t.Parallel()
+
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:       &verifierStub{claims: validClaims("thumb")},
+		Audience:       "wallet",
+		MTLSThumbprint: func(context.Context) string { return "thumb" },
+		RequiredScopes: []string{"wallet:read"},
+		ResolvePolicy: MapResolver(map[string]Policy{
+			"/svc.Method": {All: []string{"admin:write"}, Any: []string{"audit:read", "audit:admin"}},
+		}),
+		IncludeErrorDetails: true,
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", st.Code())
+	}
+	if st.Message() != "insufficient scope" {
+		t.Fatalf("unexpected message: %q", st.Message())
+	}
+
+	var info *errdetails.ErrorInfo
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil {
+		t.Fatalf("expected ErrorInfo detail, got %v", st.Details())
+	}
+	if info.GetReason() != ErrorReasonInsufficientScope || info.GetDomain() != ErrorDomain {
+		t.Fatalf("unexpected reason/domain: %q/%q", info.GetReason(), info.GetDomain())
+	}
+
+	md := info.GetMetadata()
+	want := map[string]string{
+		"method":       "/svc.Method",
+		"required_all": "wallet:read admin:write",
+		"required_any": "audit:read audit:admin",
+		"present":      "payments:create wallet:read",
+		"missing":      "admin:write",
+		"missing_any":  "audit:read audit:admin",
+	}
+	for k, v := range want {
+		if md[k] != v {
+			t.Fatalf("metadata[%q]: want %q, got %q", k, v, md[k])
+		}
+	}
+}
+
+func TestStreamServerInterceptor_InsufficientScope_IncludesErrorInfo(t *testing.T) {
+	t.Parallel()
+
+	interceptor := StreamServerInterceptor(Config{
+		Verifier:            &verifierStub{claims: validClaims("thumb")},
+		Audience:            "wallet",
+		MTLSThumbprint:      func(context.Context) string { return "thumb" },
+		RequiredScopes:      []string{"admin:write"},
+		IncludeErrorDetails: true,
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	err := interceptor(struct{}{}, &streamStub{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/svc.Stream"}, func(any, grpc.ServerStream) error { return nil })
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", st.Code())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected one detail, got %v", st.Details())
+	}
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	if !ok {
+		t.Fatalf("expected ErrorInfo, got %T", st.Details()[0])
+	}
+	if got := info.GetMetadata()["missing"]; got != "admin:write" {
+		t.Fatalf("expected missing admin:write, got %q", got)
+	}
+	if _, ok := info.GetMetadata()["missing_any"]; ok {
+		t.Fatalf("missing_any must be absent when Any policy is empty")
+	}
+}
+
 func TestUnaryServerInterceptor_SetsIdentityAndClaims(t *testing.T) {
 	t.Parallel()
 
@@ -273,6 +450,109 @@ func TestUnaryServerInterceptor_RequirePoPDisabled_AllowsMissingPoP(t *testing.T
 	}
 }
 
+func TestValidateConfig_AllowNoPoPConflictsWithRequirePoP(t *testing.T) {
+	t.Parallel()
+
+	err := ValidateConfig(Config{Verifier: &verifierStub{}, Audience: "wallet", RequirePoP: true, AllowNoPoP: true})
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	var cfgErr *ConfigValidationError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "AllowNoPoP" {
+		t.Fatalf("expected AllowNoPoP field error, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptor_TokenWithoutCnf_RejectedByDefault(t *testing.T) {
+	t.Parallel()
+
+	cl := validClaims("")
+	cl.Cnf = nil
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:       &verifierStub{claims: cl},
+		Audience:       "wallet",
+		Actor:          "api-gateway",
+		MTLSThumbprint: func(context.Context) string { return "thumb" },
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for unbound token on mTLS connection, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptor_AllowNoPoP_AcceptsTokenWithoutCnf(t *testing.T) {
+	t.Parallel()
+
+	cl := validClaims("")
+	cl.Cnf = nil
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:       &verifierStub{claims: cl},
+		Audience:       "wallet",
+		Actor:          "api-gateway",
+		AllowNoPoP:     true,
+		MTLSThumbprint: func(context.Context) string { return "thumb" },
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+	if err != nil {
+		t.Fatalf("expected no error with AllowNoPoP=true, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptor_AllowNoPoP_StillChecksCnfBinding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		thumb string
+		want  codes.Code
+	}{
+		{"matching certificate", "thumb", codes.OK},
+		{"mismatched certificate", "other-thumb", codes.PermissionDenied},
+		{"no certificate", "", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			interceptor := UnaryServerInterceptor(Config{
+				Verifier:       &verifierStub{claims: validClaims("thumb")},
+				Audience:       "wallet",
+				Actor:          "api-gateway",
+				AllowNoPoP:     true,
+				MTLSThumbprint: func(context.Context) string { return tt.thumb },
+			})
+
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+			_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+			if status.Code(err) != tt.want {
+				t.Fatalf("expected %v for cnf-bound token, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor_AllowNoPoP_WithRequirePoP_ReturnsInternal(t *testing.T) {
+	t.Parallel()
+
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:   &verifierStub{claims: validClaims("thumb")},
+		Audience:   "wallet",
+		RequirePoP: true,
+		AllowNoPoP: true,
+	})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	_, err := interceptor(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}, passHandler)
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal for conflicting PoP config, got %v", err)
+	}
+}
+
 func TestStreamServerInterceptor_SetsIdentityAndClaims(t *testing.T) {
 	t.Parallel()
 
@@ -341,6 +621,152 @@ func TestStreamServerInterceptor_InsufficientScope(t *testing.T) {
 	}
 }
 
+func TestUnaryServerInterceptor_ScopeMatcher(t *testing.T) {
+	t.Parallel()
+
+	cl := validClaims("thumb")
+	cl.Scopes = []string{"wallet:*"}
+	newInterceptor := func(match scope.Matcher, required ...string) grpc.UnaryServerInterceptor {
+		return UnaryServerInterceptor(Config{
+			Verifier:            &verifierStub{claims: cl},
+			Audience:            "wallet",
+			MTLSThumbprint:      func(context.Context) string { return "thumb" },
+			RequiredScopes:      required,
+			ScopeMatcher:        match,
+			IncludeErrorDetails: true,
+		})
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}
+
+	if _, err := newInterceptor(nil, "wallet:read")(ctx, struct{}{}, info, passHandler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("exact matching by default: expected PermissionDenied, got %v", err)
+	}
+	if _, err := newInterceptor(scope.MatchPattern, "wallet:read")(ctx, struct{}{}, info, passHandler); err != nil {
+		t.Fatalf("wallet:* must satisfy wallet:read, got %v", err)
+	}
+
+	_, err := newInterceptor(scope.MatchPattern, "wallet:read", "payments:create")(ctx, struct{}{}, info, passHandler)
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("wallet:* must not satisfy payments:create, got %v", st.Code())
+	}
+	for _, d := range st.Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			if got := ei.GetMetadata()["missing"]; got != "payments:create" {
+				t.Fatalf("missing: want %q, got %q", "payments:create", got)
+			}
+			return
+		}
+	}
+	t.Fatalf("expected ErrorInfo detail, got %v", st.Details())
+}
+
+func TestUnaryServerInterceptor_RestrictScopesToAudience(t *testing.T) {
+	t.Parallel()
+
+	cl := validClaims("thumb")
+	cl.Scopes = []string{"payments:*", "wallet:read", "*"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}
+
+	newInterceptor := func(restrict bool, prefixes []string, required ...string) grpc.UnaryServerInterceptor {
+		return UnaryServerInterceptor(Config{
+			Verifier:                 &verifierStub{claims: cl},
+			Audience:                 "wallet",
+			MTLSThumbprint:           func(context.Context) string { return "thumb" },
+			RequiredScopes:           required,
+			ScopeMatcher:             scope.MatchPattern,
+			RestrictScopesToAudience: restrict,
+			AudienceScopePrefixes:    prefixes,
+		})
+	}
+
+	if _, err := newInterceptor(false, nil, "wallet:write")(ctx, struct{}{}, info, passHandler); err != nil {
+		t.Fatalf("without restriction the global * grants wallet:write, got %v", err)
+	}
+	if _, err := newInterceptor(true, nil, "wallet:write")(ctx, struct{}{}, info, passHandler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("restricted: * and payments:* must not grant wallet:write, got %v", err)
+	}
+	if _, err := newInterceptor(true, nil, "payments:create")(ctx, struct{}{}, info, passHandler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("restricted: payments:* must not satisfy a payments policy on the wallet service, got %v", err)
+	}
+
+	var got Identity
+	capture := func(ctx context.Context, req any) (any, error) {
+		got, _ = IdentityFrom(ctx)
+		return req, nil
+	}
+	if _, err := newInterceptor(true, nil, "wallet:read")(ctx, struct{}{}, info, capture); err != nil {
+		t.Fatalf("restricted: wallet:read must pass, got %v", err)
+	}
+	if len(got.Scopes) != 1 || got.Scopes[0] != "wallet:read" {
+		t.Fatalf("identity scopes must be filtered, got %v", got.Scopes)
+	}
+
+	if _, err := newInterceptor(false, []string{"payments:"}, "payments:create")(ctx, struct{}{}, info, passHandler); err != nil {
+		t.Fatalf("AudienceScopePrefixes override: payments:* must satisfy payments:create, got %v", err)
+	}
+	if _, err := newInterceptor(false, []string{"payments:"}, "wallet:read")(ctx, struct{}{}, info, passHandler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("AudienceScopePrefixes override: wallet:read must be dropped, got %v", err)
+	}
+}
+
+func TestUnaryServerInterceptor_RequireRoles(t *testing.T) {
+	t.Parallel()
+
+	newInterceptor := func(roles []string, include bool) grpc.UnaryServerInterceptor {
+		cl := validClaims("thumb")
+		cl.Roles = roles
+		return UnaryServerInterceptor(Config{
+			Verifier:       &verifierStub{claims: cl},
+			Audience:       "wallet",
+			MTLSThumbprint: func(context.Context) string { return "thumb" },
+			ResolvePolicy: MapResolver(map[string]Policy{
+				"/svc.Admin": {All: []string{"wallet:read"}, RequireRoles: []string{"support", "admin"}},
+			}),
+			IncludeErrorDetails: include,
+		})
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	admin := &grpc.UnaryServerInfo{FullMethod: "/svc.Admin"}
+
+	if _, err := newInterceptor([]string{"admin", "support"}, false)(ctx, struct{}{}, admin, passHandler); err != nil {
+		t.Fatalf("expected all roles to pass, got %v", err)
+	}
+	if _, err := newInterceptor(nil, false)(ctx, struct{}{}, &grpc.UnaryServerInfo{FullMethod: "/svc.Other"}, passHandler); err != nil {
+		t.Fatalf("method without RequireRoles must pass, got %v", err)
+	}
+
+	_, err := newInterceptor([]string{"support"}, false)(ctx, struct{}{}, admin, passHandler)
+	st := status.Convert(err)
+	if st.Code() != codes.PermissionDenied || st.Message() != "insufficient role" || len(st.Details()) != 0 {
+		t.Fatalf("expected bare PermissionDenied insufficient role, got %v %v", st, st.Details())
+	}
+
+	_, err = newInterceptor([]string{"support"}, true)(ctx, struct{}{}, admin, passHandler)
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil || info.GetReason() != ErrorReasonInsufficientRole || info.GetDomain() != ErrorDomain {
+		t.Fatalf("expected %s ErrorInfo, got %v", ErrorReasonInsufficientRole, info)
+	}
+	want := map[string]string{
+		"method":         "/svc.Admin",
+		"required_roles": "support admin",
+		"present_roles":  "support",
+		"missing_roles":  "admin",
+	}
+	for k, v := range want {
+		if info.GetMetadata()[k] != v {
+			t.Fatalf("metadata[%q]: want %q, got %q", k, v, info.GetMetadata()[k])
+		}
+	}
+}
+
 func validClaims(thumb string) *libjwt.Claims {
 	now := time.Now()
 	return &libjwt.Claims{
@@ -0,0 +1,114 @@
+package authz
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	libjwt "github.com/vortex-fintech/go-lib/security/jwt"
+)
+
+// DefaultVerifyCacheSize is used by NewVerifyCache for a non-positive size.
+const DefaultVerifyCacheSize = 10_000
+
+// VerifyCache is a size-bounded LRU of verified claims keyed by the SHA-256 of the raw token.
+// Entries expire at the token's exp. Only Verifier.Verify is skipped on a hit; OBO, PoP,
+// anti-replay and scope checks still run on every call. Safe for concurrent use; share one
+// cache between the unary and stream interceptors.
+type VerifyCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type verifyCacheEntry struct {
+	key    [sha256.Size]byte
+	claims libjwt.Claims
+	exp    time.Time
+}
+
+func NewVerifyCache(size int) *VerifyCache {
+	if size <= 0 {
+		size = DefaultVerifyCacheSize
+	}
+	return &VerifyCache{
+		max:     size,
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Len returns the number of cached tokens, including expired ones not yet evicted.
+func (c *VerifyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *VerifyCache) get(raw string) (*libjwt.Claims, bool) {
+	key := sha256.Sum256([]byte(raw))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*verifyCacheEntry)
+	if !c.now().Before(e.exp) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	cl := e.claims
+	return &cl, true
+}
+
+func (c *VerifyCache) put(raw string, cl *libjwt.Claims) {
+	exp := cl.ExpiresAt()
+	if cl.Exp <= 0 || !c.now().Before(exp) {
+		return
+	}
+	key := sha256.Sum256([]byte(raw))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*verifyCacheEntry)
+		e.claims, e.exp = *cl, exp
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&verifyCacheEntry{key: key, claims: *cl, exp: exp})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*verifyCacheEntry).key)
+	}
+}
+
+// verify returns claims from cfg.VerifyCache or calls cfg.Verifier and caches the result.
+func verify(ctx context.Context, cfg Config, raw string) (*libjwt.Claims, error) {
+	if cfg.VerifyCache == nil {
+		return cfg.Verifier.Verify(ctx, raw)
+	}
+	if cl, ok := cfg.VerifyCache.get(raw); ok {
+		return cl, nil
+	}
+	cl, err := cfg.Verifier.Verify(ctx, raw)
+	if err != nil {
+		return nil, err
+	}
+	if cl != nil {
+		cfg.VerifyCache.put(raw, cl)
+	}
+	return cl, nil
+}
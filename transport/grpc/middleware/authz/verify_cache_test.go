@@ -0,0 +1,153 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	libjwt "github.com/vortex-fintech/go-lib/security/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func bearerCtx(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestUnaryServerInterceptor_VerifyCache(t *testing.T) {
+	t.Parallel()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}
+	newInterceptor := func(v *verifierStub, cache *VerifyCache) grpc.UnaryServerInterceptor {
+		return UnaryServerInterceptor(Config{
+			Verifier:       v,
+			Audience:       "wallet",
+			MTLSThumbprint: func(context.Context) string { return "thumb" },
+			VerifyCache:    cache,
+		})
+	}
+
+	cached := &verifierStub{claims: validClaims("thumb")}
+	cache := NewVerifyCache(0)
+	interceptor := newInterceptor(cached, cache)
+	for i := 0; i < 3; i++ {
+		if _, err := interceptor(bearerCtx("token-a"), struct{}{}, info, passHandler); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if cached.called != 1 {
+		t.Fatalf("with cache: expected 1 Verify call, got %d", cached.called)
+	}
+	if _, err := interceptor(bearerCtx("token-b"), struct{}{}, info, passHandler); err != nil {
+		t.Fatalf("second token: %v", err)
+	}
+	if cached.called != 2 || cache.Len() != 2 {
+		t.Fatalf("a different token must be verified: called=%d len=%d", cached.called, cache.Len())
+	}
+
+	uncached := &verifierStub{claims: validClaims("thumb")}
+	interceptor = newInterceptor(uncached, nil)
+	for i := 0; i < 3; i++ {
+		if _, err := interceptor(bearerCtx("token-a"), struct{}{}, info, passHandler); err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+	if uncached.called != 3 {
+		t.Fatalf("without cache: expected 3 Verify calls, got %d", uncached.called)
+	}
+}
+
+func TestVerifyCache_PolicyChecksStillRun(t *testing.T) {
+	t.Parallel()
+
+	v := &verifierStub{claims: validClaims("thumb")}
+	thumb := "thumb"
+	interceptor := UnaryServerInterceptor(Config{
+		Verifier:       v,
+		Audience:       "wallet",
+		MTLSThumbprint: func(context.Context) string { return thumb },
+		VerifyCache:    NewVerifyCache(10),
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/svc.Method"}
+
+	if _, err := interceptor(bearerCtx("token"), struct{}{}, info, passHandler); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	thumb = "other-cert"
+	if _, err := interceptor(bearerCtx("token"), struct{}{}, info, passHandler); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("PoP mismatch must be rejected on a cache hit, got %v", err)
+	}
+	if v.called != 1 {
+		t.Fatalf("expected cache hit, got %d Verify calls", v.called)
+	}
+}
+
+func TestVerifyCache_ErrorsNotCached(t *testing.T) {
+	t.Parallel()
+
+	v := &verifierStub{err: errors.New("bad signature")}
+	cache := NewVerifyCache(10)
+	cfg := Config{Verifier: v, Audience: "wallet", VerifyCache: cache}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Authorize(bearerCtx("token"), "/svc.Method", cfg); status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("expected Unauthenticated, got %v", err)
+		}
+	}
+	if v.called != 2 || cache.Len() != 0 {
+		t.Fatalf("failed verification must not be cached: called=%d len=%d", v.called, cache.Len())
+	}
+}
+
+func TestVerifyCache_ExpiryAndEviction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_000, 0)
+	cache := NewVerifyCache(2)
+	cache.now = func() time.Time { return now }
+
+	claims := func(exp int64) *libjwt.Claims { return &libjwt.Claims{Subject: "s", Exp: exp} }
+
+	cache.put("a", claims(1_100))
+	if cl, ok := cache.get("a"); !ok || cl.Exp != 1_100 {
+		t.Fatalf("expected hit for a, got %v %v", cl, ok)
+	}
+
+	now = time.Unix(1_100, 0)
+	if _, ok := cache.get("a"); ok {
+		t.Fatal("entry must expire at exp")
+	}
+	if cache.Len() != 0 {
+		t.Fatalf("expired entry must be evicted, len=%d", cache.Len())
+	}
+
+	cache.put("expired", claims(1_050))
+	cache.put("no-exp", claims(0))
+	if cache.Len() != 0 {
+		t.Fatalf("expired or exp-less claims must not be cached, len=%d", cache.Len())
+	}
+
+	cache.put("b", claims(2_000))
+	cache.put("c", claims(2_000))
+	cache.get("b") // b is now most recently used
+	cache.put("d", claims(2_000))
+	if cache.Len() != 2 {
+		t.Fatalf("cache must stay bounded, len=%d", cache.Len())
+	}
+	if _, ok := cache.get("c"); ok {
+		t.Fatal("least recently used entry c must be evicted")
+	}
+	if _, ok := cache.get("b"); !ok {
+		t.Fatal("b must survive eviction")
+	}
+
+	// Returned claims are copies: mutating them does not change the cache.
+	cl, _ := cache.get("b")
+	cl.Subject = "mutated"
+	if again, _ := cache.get("b"); again.Subject != "s" {
+		t.Fatalf("cached claims mutated: %q", again.Subject)
+	}
+}
@@ -0,0 +1,56 @@
+package circuitbreaker
+
+import "time"
+
+// windowBuckets — на сколько корзин делится окно WithFailureRate.
+// Вызовы выпадают из окна целой корзиной, т.е. с точностью window/windowBuckets.
+const windowBuckets = 10
+
+type windowBucket struct {
+	start    int64 // номер интервала (unix nanos / width), к которому относятся счётчики
+	total    int
+	failures int
+}
+
+// rollingWindow — скользящее окно фиксированного размера. Не потокобезопасно:
+// вызывается под Interceptor.mu.
+type rollingWindow struct {
+	width   int64 // длина корзины в наносекундах
+	buckets [windowBuckets]windowBucket
+}
+
+func newRollingWindow(o CBOptions) *rollingWindow {
+	if o.FailureRate <= 0 || o.FailureRateWindow <= 0 {
+		return nil
+	}
+	return &rollingWindow{width: max(int64(o.FailureRateWindow)/windowBuckets, 1)}
+}
+
+func (w *rollingWindow) add(now time.Time, failed bool) {
+	idx := now.UnixNano() / w.width
+	b := &w.buckets[idx%windowBuckets]
+	if b.start != idx {
+		*b = windowBucket{start: idx}
+	}
+	b.total++
+	if failed {
+		b.failures++
+	}
+}
+
+func (w *rollingWindow) counts(now time.Time) (total, failures int) {
+	idx := now.UnixNano() / w.width
+	for _, b := range w.buckets {
+		if b.start > idx-windowBuckets && b.start <= idx {
+			total += b.total
+			failures += b.failures
+		}
+	}
+	return total, failures
+}
+
+func (w *rollingWindow) reset() {
+	if w != nil {
+		w.buckets = [windowBuckets]windowBucket{}
+	}
+}
@@ -26,21 +26,56 @@ func (nopLogger) Error(string) {}
 
 /* ---------- публичные опции ---------- */
 
+type Metrics interface {
+	IncTrips(method string)
+	ObserveOpenDuration(method string, d time.Duration)
+}
+
 type CBOptions struct {
-	FailureThreshold int                     // N подряд критичных ошибок ⇒ OPEN
-	RecoveryTimeout  time.Duration           // пауза OPEN → HALF-OPEN
-	HalfOpenSuccess  int                     // M успешных тест-RPC ⇒ CLOSED
-	TripFunc         func(c codes.Code) bool // какие коды считаем «сбоем»
-	Logger           Logger                  // опционально
-	Now              func() time.Time        // инъекция времени (для тестов)
+	FailureThreshold  int                     // N подряд критичных ошибок ⇒ OPEN
+	RecoveryTimeout   time.Duration           // пауза OPEN → HALF-OPEN
+	HalfOpenSuccess   int                     // M успешных тест-RPC ⇒ CLOSED
+	TripFunc          func(c codes.Code) bool // какие коды считаем «сбоем»
+	Logger            Logger                  // опционально
+	Now               func() time.Time        // инъекция времени (для тестов)
+	PerMethod         bool
+	MethodIdleTTL     time.Duration
+	OnStateChange     func(method, from, to string)
+	Metrics           Metrics
+	HalfOpenMaxProbes int
+
+	// Режим по доле ошибок (WithFailureRate): взаимоисключающий с FailureThreshold.
+	FailureRate            float64       // доля критичных ошибок в окне ⇒ OPEN; 0 — режим выключен
+	FailureRateMinRequests int           // минимум вызовов в окне, прежде чем считать долю
+	FailureRateWindow      time.Duration // длина скользящего окна
 }
 
 /* functional options */
 
 type Option func(*CBOptions)
 
+// WithFailureThreshold включает режим «N подряд» (по умолчанию) и отключает WithFailureRate.
 func WithFailureThreshold(n int) Option {
-	return func(o *CBOptions) { o.FailureThreshold = n }
+	return func(o *CBOptions) {
+		o.FailureThreshold = n
+		o.FailureRate, o.FailureRateMinRequests, o.FailureRateWindow = 0, 0, 0
+	}
+}
+
+// WithFailureRate переключает breaker на долю ошибок: OPEN, когда за последние window
+// было не меньше minRequests вызовов и доля критичных ошибок среди них ≥ ratio.
+// Отключает WithFailureThreshold; из двух опций действует последняя.
+// ratio вне (0, 1] или window <= 0 оставляют режим «N подряд».
+func WithFailureRate(minRequests int, ratio float64, window time.Duration) Option {
+	return func(o *CBOptions) {
+		if ratio <= 0 || ratio > 1 || window <= 0 {
+			return
+		}
+		o.FailureThreshold = 0
+		o.FailureRate = ratio
+		o.FailureRateMinRequests = max(minRequests, 1)
+		o.FailureRateWindow = window
+	}
 }
 func WithRecoveryTimeout(d time.Duration) Option {
 	return func(o *CBOptions) { o.RecoveryTimeout = d }
@@ -48,6 +83,9 @@ func WithRecoveryTimeout(d time.Duration) Option {
 func WithHalfOpenSuccess(n int) Option {
 	return func(o *CBOptions) { o.HalfOpenSuccess = n }
 }
+func WithHalfOpenMaxProbes(n int) Option {
+	return func(o *CBOptions) { o.HalfOpenMaxProbes = n }
+}
 func WithTripCodes(codesToTrip ...codes.Code) Option {
 	set := make(map[codes.Code]struct{}, len(codesToTrip))
 	for _, c := range codesToTrip {
@@ -63,6 +101,18 @@ func WithTripCodes(codesToTrip ...codes.Code) Option {
 func WithTripFunc(f func(codes.Code) bool) Option {
 	return func(o *CBOptions) { o.TripFunc = f }
 }
+func WithPerMethod(enabled bool) Option {
+	return func(o *CBOptions) { o.PerMethod = enabled }
+}
+func WithMethodIdleTTL(d time.Duration) Option {
+	return func(o *CBOptions) { o.MethodIdleTTL = d }
+}
+func WithOnStateChange(fn func(method, from, to string)) Option {
+	return func(o *CBOptions) { o.OnStateChange = fn }
+}
+func WithMetrics(m Metrics) Option {
+	return func(o *CBOptions) { o.Metrics = m }
+}
 func WithLogger(l Logger) Option {
 	return func(o *CBOptions) { o.Logger = l }
 }
@@ -92,6 +142,9 @@ func New(opts ...Option) *Interceptor {
 	if o.HalfOpenSuccess < 1 {
 		o.HalfOpenSuccess = 1
 	}
+	if o.HalfOpenMaxProbes < 1 {
+		o.HalfOpenMaxProbes = 1
+	}
 	if o.RecoveryTimeout == 0 {
 		o.RecoveryTimeout = 10 * time.Second
 	}
@@ -101,12 +154,15 @@ func New(opts ...Option) *Interceptor {
 	if o.Now == nil {
 		o.Now = time.Now
 	}
+	if o.MethodIdleTTL <= 0 {
+		o.MethodIdleTTL = 10 * time.Minute
+	}
 
 	return &Interceptor{
-		log:   o.Logger,
-		opt:   o,
-		state: stateClosed,
-		now:   o.Now,
+		log:    o.Logger,
+		opt:    o,
+		global: breaker{state: stateClosed, window: newRollingWindow(o)},
+		now:    o.Now,
 	}
 }
 
@@ -120,16 +176,32 @@ const (
 	stateHalfOpen
 )
 
-type Interceptor struct {
-	log Logger
-	opt CBOptions
-
-	mu            sync.Mutex
+type breaker struct {
 	state         cbState
 	failures      int       // подряд критичных ошибок (CLOSED)
 	openSince     time.Time // тайм-штамп входа в OPEN
-	inflight      bool      // true ⇒ тестовый RPC уже идёт (HALF-OPEN)
+	inflight      int       // тестовых RPC в полёте (HALF-OPEN)
 	successInHalf int       // успешных RPC в HALF-OPEN
+	lastUsed      time.Time
+	trippedAt     time.Time
+	window        *rollingWindow // только в режиме WithFailureRate
+}
+
+type transition struct {
+	method   string
+	from, to cbState
+	openFor  time.Duration
+}
+
+type Interceptor struct {
+	log Logger
+	opt CBOptions
+
+	mu        sync.Mutex
+	global    breaker
+	methods   map[string]*breaker
+	lastSweep time.Time
+	pending   []transition
 
 	now func() time.Time
 }
@@ -144,46 +216,52 @@ func (cb *Interceptor) Unary() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (any, error) {
 
+		var method string
+		if info != nil {
+			method = info.FullMethod
+		}
+
 		// Решаем судьбу вызова
 		cb.mu.Lock()
+		b := cb.breakerFor(method)
 		var wasHalfOpen bool
 
-		switch cb.state {
+		switch b.state {
 		case stateOpen:
-			if cb.now().Sub(cb.openSince) >= cb.opt.RecoveryTimeout {
-				cb.state = stateHalfOpen
-				cb.inflight = true
-				cb.successInHalf = 0
-				cb.openSince = cb.now() // защита от зависания тест-RPC
+			if cb.now().Sub(b.openSince) >= cb.opt.RecoveryTimeout {
+				cb.setState(b, method, stateHalfOpen)
+				b.inflight = 1
+				b.successInHalf = 0
+				b.openSince = cb.now() // защита от зависания тест-RPC
 				wasHalfOpen = true
-				cb.log.Info("circuit breaker → HALF-OPEN")
+				cb.logf(cb.log.Info, "circuit breaker → HALF-OPEN", method)
 			} else {
-				cb.mu.Unlock()
+				cb.unlock()
 				return nil, status.Error(codes.Unavailable, "circuit breaker open")
 			}
 
 		case stateHalfOpen:
-			if cb.inflight {
-				cb.mu.Unlock()
+			if b.inflight >= cb.opt.HalfOpenMaxProbes {
+				cb.unlock()
 				return nil, status.Error(codes.Unavailable, "circuit breaker half-open")
 			}
-			cb.inflight = true
-			cb.openSince = cb.now()
+			b.inflight++
+			b.openSince = cb.now()
 			wasHalfOpen = true
 
 		case stateClosed:
 			// обычная работа
 		}
-		cb.mu.Unlock()
+		cb.unlock()
 
 		// выполняем бизнес-логику
 		resp, err := handler(ctx, req)
 
 		// пост-обработка
 		if wasHalfOpen {
-			cb.finishHalfOpen(err)
+			cb.finishHalfOpen(b, method, err)
 		} else {
-			cb.afterCall(err)
+			cb.afterCall(b, method, err)
 		}
 
 		return resp, err
@@ -194,7 +272,47 @@ func (cb *Interceptor) Unary() grpc.UnaryServerInterceptor {
 func (cb *Interceptor) State() string {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
-	switch cb.state {
+	if !cb.opt.PerMethod {
+		return cb.global.state.String()
+	}
+	worst := stateClosed
+	for _, b := range cb.methods {
+		if b.state == stateOpen {
+			return stateOpen.String()
+		}
+		if b.state == stateHalfOpen {
+			worst = stateHalfOpen
+		}
+	}
+	return worst.String()
+}
+
+func (cb *Interceptor) StateOf(method string) string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if !cb.opt.PerMethod {
+		return cb.global.state.String()
+	}
+	if b, ok := cb.methods[method]; ok {
+		return b.state.String()
+	}
+	return stateClosed.String()
+}
+
+// Сброс в CLOSED (например, из админки)
+func (cb *Interceptor) Reset() {
+	cb.mu.Lock()
+	defer cb.unlock()
+	cb.setState(&cb.global, "", stateClosed)
+	for method, b := range cb.methods {
+		cb.setState(b, method, stateClosed)
+	}
+	cb.global = breaker{state: stateClosed, window: newRollingWindow(cb.opt)}
+	cb.methods = nil
+}
+
+func (s cbState) String() string {
+	switch s {
 	case stateClosed:
 		return "closed"
 	case stateOpen:
@@ -206,25 +324,91 @@ func (cb *Interceptor) State() string {
 	}
 }
 
-// Сброс в CLOSED (например, из админки)
-func (cb *Interceptor) Reset() {
-	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	cb.state = stateClosed
-	cb.failures = 0
-	cb.inflight = false
-	cb.successInHalf = 0
-	cb.openSince = time.Time{}
+/* ---------- вспомогательные методы ---------- */
+
+func (cb *Interceptor) breakerFor(method string) *breaker {
+	if !cb.opt.PerMethod {
+		return &cb.global
+	}
+
+	now := cb.now()
+	if now.Sub(cb.lastSweep) >= cb.opt.MethodIdleTTL {
+		cb.lastSweep = now
+		for m, b := range cb.methods {
+			if b.inflight == 0 && now.Sub(b.lastUsed) >= cb.opt.MethodIdleTTL {
+				delete(cb.methods, m)
+			}
+		}
+	}
+
+	b, ok := cb.methods[method]
+	if !ok {
+		if cb.methods == nil {
+			cb.methods = make(map[string]*breaker)
+		}
+		b = &breaker{state: stateClosed, window: newRollingWindow(cb.opt)}
+		cb.methods[method] = b
+	}
+	b.lastUsed = now
+	return b
 }
 
-/* ---------- вспомогательные методы ---------- */
+func (cb *Interceptor) setState(b *breaker, method string, to cbState) {
+	from := b.state
+	if from == to {
+		return
+	}
+
+	t := transition{method: method, from: from, to: to}
+	now := cb.now()
+	switch {
+	case from == stateClosed && to == stateOpen:
+		b.trippedAt = now
+	case to == stateClosed && !b.trippedAt.IsZero():
+		t.openFor = now.Sub(b.trippedAt)
+		b.trippedAt = time.Time{}
+	}
+	b.state = to
+	cb.pending = append(cb.pending, t)
+}
+
+func (cb *Interceptor) unlock() {
+	events := cb.pending
+	cb.pending = nil
+	cb.mu.Unlock()
+
+	for _, e := range events {
+		if cb.opt.Metrics != nil {
+			if e.to == stateOpen {
+				cb.opt.Metrics.IncTrips(e.method)
+			}
+			if e.to == stateClosed && e.openFor > 0 {
+				cb.opt.Metrics.ObserveOpenDuration(e.method, e.openFor)
+			}
+		}
+		if cb.opt.OnStateChange != nil {
+			cb.opt.OnStateChange(e.method, e.from.String(), e.to.String())
+		}
+	}
+}
+
+func (cb *Interceptor) logf(log func(string), msg, method string) {
+	if cb.opt.PerMethod {
+		msg += " [" + method + "]"
+	}
+	log(msg)
+}
 
 // Обработка результата в фазе CLOSED
-func (cb *Interceptor) afterCall(err error) {
+func (cb *Interceptor) afterCall(b *breaker, method string, err error) {
+	if b.window != nil {
+		cb.afterCallRate(b, method, err)
+		return
+	}
 	if err == nil {
 		cb.mu.Lock()
-		cb.failures = 0
-		cb.mu.Unlock()
+		b.failures = 0
+		cb.unlock()
 		return
 	}
 	st, ok := status.FromError(err)
@@ -232,36 +416,71 @@ func (cb *Interceptor) afterCall(err error) {
 		return // бизнес-ошибка — игнорируем
 	}
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
-	cb.failures++
-	if cb.failures >= cb.opt.FailureThreshold && cb.state == stateClosed {
-		cb.state = stateOpen
-		cb.openSince = cb.now()
-		cb.log.Error("circuit breaker OPENED")
+	defer cb.unlock()
+	b.failures++
+	if b.failures >= cb.opt.FailureThreshold && b.state == stateClosed {
+		cb.trip(b, method)
+	}
+}
+
+// Обработка результата в фазе CLOSED в режиме WithFailureRate.
+// Бизнес-ошибки считаются вызовами, но не сбоями.
+func (cb *Interceptor) afterCallRate(b *breaker, method string, err error) {
+	failed := false
+	if err != nil {
+		st, ok := status.FromError(err)
+		failed = ok && cb.opt.TripFunc(st.Code())
+	}
+
+	cb.mu.Lock()
+	defer cb.unlock()
+	if b.state != stateClosed {
+		return // пока вызов шёл, breaker уже открылся
+	}
+	now := cb.now()
+	b.window.add(now, failed)
+	if !failed {
+		return
+	}
+	total, failures := b.window.counts(now)
+	if total >= cb.opt.FailureRateMinRequests && float64(failures) >= cb.opt.FailureRate*float64(total) {
+		cb.trip(b, method)
 	}
 }
 
+func (cb *Interceptor) trip(b *breaker, method string) {
+	cb.setState(b, method, stateOpen)
+	b.openSince = cb.now()
+	cb.logf(cb.log.Error, "circuit breaker OPENED", method)
+}
+
 // Обработка результата тестового RPC в фазе HALF-OPEN
-func (cb *Interceptor) finishHalfOpen(err error) {
+func (cb *Interceptor) finishHalfOpen(b *breaker, method string, err error) {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
+	defer cb.unlock()
 
-	cb.inflight = false // тестовый вызов завершён
+	if b.inflight > 0 {
+		b.inflight-- // тестовый вызов завершён
+	}
+	if b.state != stateHalfOpen {
+		return
+	}
 
 	if err == nil {
-		cb.successInHalf++
-		if cb.successInHalf >= cb.opt.HalfOpenSuccess {
-			cb.state = stateClosed
-			cb.failures = 0
-			cb.log.Info("circuit breaker CLOSED — service recovered")
+		b.successInHalf++
+		if b.successInHalf >= cb.opt.HalfOpenSuccess {
+			cb.setState(b, method, stateClosed)
+			b.failures = 0
+			b.window.reset()
+			cb.logf(cb.log.Info, "circuit breaker CLOSED — service recovered", method)
 		}
 		return
 	}
 
 	if st, ok := status.FromError(err); ok && cb.opt.TripFunc(st.Code()) {
-		cb.state = stateOpen
-		cb.openSince = cb.now()
-		cb.failures = 1
-		cb.log.Warn("circuit breaker RE-OPENED from half-open")
+		cb.setState(b, method, stateOpen)
+		b.openSince = cb.now()
+		b.failures = 1
+		cb.logf(cb.log.Warn, "circuit breaker RE-OPENED from half-open", method)
 	}
 }
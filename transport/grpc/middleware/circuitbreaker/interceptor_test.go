@@ -266,3 +266,471 @@ func Test_HALF_OPEN_allows_only_one_inflight_concurrently(t *testing.T) {
 		t.Fatalf("second concurrent call should be Unavailable, got %v", err2)
 	}
 }
+
+func callMethod(t *testing.T, itc grpc.UnaryServerInterceptor, method string, h grpc.UnaryHandler) error {
+	t.Helper()
+	_, err := itc(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, h)
+	return err
+}
+
+func Test_Global_mode_opening_one_method_blocks_others(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk)
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	}
+	if err := callMethod(t, itc, "/svc/B", okHandler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable for B in global mode, got %v", err)
+	}
+	if s := cb.StateOf("/svc/B"); s != "open" {
+		t.Fatalf("expected StateOf(B)=open in global mode, got %s", s)
+	}
+}
+
+func Test_PerMethod_opening_A_does_not_block_B(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithPerMethod(true))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	}
+	if err := callMethod(t, itc, "/svc/A", okHandler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable for A, got %v", err)
+	}
+	if err := callMethod(t, itc, "/svc/B", okHandler); err != nil {
+		t.Fatalf("expected B to pass while A is open, got %v", err)
+	}
+
+	if s := cb.StateOf("/svc/A"); s != "open" {
+		t.Fatalf("expected StateOf(A)=open, got %s", s)
+	}
+	if s := cb.StateOf("/svc/B"); s != "closed" {
+		t.Fatalf("expected StateOf(B)=closed, got %s", s)
+	}
+	if s := cb.StateOf("/svc/Unknown"); s != "closed" {
+		t.Fatalf("expected StateOf(unknown)=closed, got %s", s)
+	}
+	if s := cb.State(); s != "open" {
+		t.Fatalf("expected aggregate State()=open, got %s", s)
+	}
+}
+
+func Test_PerMethod_failures_counted_per_method(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithPerMethod(true))
+	itc := cb.Unary()
+
+	for i := 0; i < 2; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Internal))
+		_ = callMethod(t, itc, "/svc/B", errHandler(codes.Internal))
+	}
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("expected closed with 2 failures per method, got %s", s)
+	}
+
+	_ = callMethod(t, itc, "/svc/A", errHandler(codes.Internal))
+	if cb.StateOf("/svc/A") != "open" || cb.StateOf("/svc/B") != "closed" {
+		t.Fatalf("expected only A open, got A=%s B=%s", cb.StateOf("/svc/A"), cb.StateOf("/svc/B"))
+	}
+}
+
+func Test_PerMethod_idle_methods_evicted(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithPerMethod(true), WithMethodIdleTTL(time.Minute))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	}
+	_ = callMethod(t, itc, "/svc/B", okHandler)
+
+	clk.advance(time.Minute)
+	_ = callMethod(t, itc, "/svc/C", okHandler)
+
+	cb.mu.Lock()
+	_, hasA := cb.methods["/svc/A"]
+	_, hasB := cb.methods["/svc/B"]
+	n := len(cb.methods)
+	cb.mu.Unlock()
+
+	if hasA || hasB || n != 1 {
+		t.Fatalf("expected idle methods evicted, have %d entries (A=%v B=%v)", n, hasA, hasB)
+	}
+	if s := cb.StateOf("/svc/A"); s != "closed" {
+		t.Fatalf("expected evicted method to report closed, got %s", s)
+	}
+}
+
+func Test_PerMethod_Reset_clears_all_methods(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithPerMethod(true))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	}
+	cb.Reset()
+
+	if s := cb.StateOf("/svc/A"); s != "closed" {
+		t.Fatalf("expected closed after reset, got %s", s)
+	}
+	if err := callMethod(t, itc, "/svc/A", okHandler); err != nil {
+		t.Fatalf("expected call to pass after reset, got %v", err)
+	}
+}
+
+type stateChange struct {
+	method, from, to string
+}
+
+type recordingMetrics struct {
+	mu        sync.Mutex
+	trips     map[string]int
+	durations []time.Duration
+}
+
+func (m *recordingMetrics) IncTrips(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.trips == nil {
+		m.trips = map[string]int{}
+	}
+	m.trips[method]++
+}
+
+func (m *recordingMetrics) ObserveOpenDuration(_ string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations = append(m.durations, d)
+}
+
+func Test_OnStateChange_open_half_open_closed(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	var changes []stateChange
+	var cb *Interceptor
+	cb = makeCB(t, clk, WithOnStateChange(func(method, from, to string) {
+		if got := cb.State(); got != to {
+			t.Errorf("expected State()=%s inside callback, got %s", to, got)
+		}
+		changes = append(changes, stateChange{method, from, to})
+	}))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Internal))
+	}
+	clk.advance(5 * time.Second)
+	_ = callUnary(t, itc, okHandler)
+	_ = callUnary(t, itc, okHandler)
+
+	want := []stateChange{
+		{"/svc/Method", "closed", "open"},
+		{"/svc/Method", "open", "half-open"},
+		{"/svc/Method", "half-open", "closed"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d transitions, got %+v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("transition %d: expected %+v, got %+v", i, want[i], changes[i])
+		}
+	}
+}
+
+func Test_OnStateChange_reopen_and_reset(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	var changes []stateChange
+	cb := makeCB(t, clk, WithPerMethod(true), WithOnStateChange(func(method, from, to string) {
+		changes = append(changes, stateChange{method, from, to})
+	}))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	}
+	clk.advance(5 * time.Second)
+	_ = callMethod(t, itc, "/svc/A", errHandler(codes.Unavailable))
+	cb.Reset()
+
+	want := []stateChange{
+		{"/svc/A", "closed", "open"},
+		{"/svc/A", "open", "half-open"},
+		{"/svc/A", "half-open", "open"},
+		{"/svc/A", "open", "closed"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d transitions, got %+v", len(want), changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("transition %d: expected %+v, got %+v", i, want[i], changes[i])
+		}
+	}
+}
+
+func Test_Metrics_trips_and_open_duration(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	m := &recordingMetrics{}
+	cb := makeCB(t, clk, WithMetrics(m), WithHalfOpenSuccess(1))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Internal))
+	}
+	clk.advance(5 * time.Second)
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	clk.advance(5 * time.Second)
+	_ = callUnary(t, itc, okHandler)
+
+	if cb.State() != "closed" {
+		t.Fatalf("expected closed, got %s", cb.State())
+	}
+	if m.trips["/svc/Method"] != 2 {
+		t.Fatalf("expected 2 trips, got %v", m.trips)
+	}
+	if len(m.durations) != 1 || m.durations[0] != 10*time.Second {
+		t.Fatalf("expected one open duration of 10s, got %v", m.durations)
+	}
+}
+
+func Test_HALF_OPEN_admits_max_probes_concurrently(t *testing.T) {
+	const probes = 3
+
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithHalfOpenMaxProbes(probes), WithHalfOpenSuccess(probes))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Unavailable))
+	}
+	clk.advance(5 * time.Second)
+
+	var started sync.WaitGroup
+	release := make(chan struct{})
+	blockingHandler := func(ctx context.Context, req any) (any, error) {
+		started.Done()
+		<-release
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, probes)
+	started.Add(probes)
+	for i := 0; i < probes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = callUnary(t, itc, blockingHandler)
+		}(i)
+	}
+	started.Wait()
+
+	if err := callUnary(t, itc, okHandler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected probe %d to be rejected with Unavailable, got %v", probes+1, err)
+	}
+
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("probe %d should pass, got %v", i+1, err)
+		}
+	}
+	if cb.State() != "closed" {
+		t.Fatalf("expected closed after %d successful probes, got %s", probes, cb.State())
+	}
+}
+
+func Test_HALF_OPEN_failed_probe_reopens_with_others_inflight(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	var changes []stateChange
+	cb := makeCB(t, clk, WithHalfOpenMaxProbes(2), WithOnStateChange(func(method, from, to string) {
+		changes = append(changes, stateChange{method, from, to})
+	}))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Unavailable))
+	}
+	clk.advance(5 * time.Second)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- callUnary(t, itc, func(ctx context.Context, req any) (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+
+	if err := callUnary(t, itc, errHandler(codes.Unavailable)); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected failing probe error, got %v", err)
+	}
+	if cb.State() != "open" {
+		t.Fatalf("expected open after first failed probe, got %s", cb.State())
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("expected slow probe to pass, got %v", err)
+	}
+	if cb.State() != "open" {
+		t.Fatalf("expected late successful probe to keep breaker open, got %s", cb.State())
+	}
+	if last := changes[len(changes)-1]; last.from != "half-open" || last.to != "open" {
+		t.Fatalf("expected last transition half-open→open, got %+v", last)
+	}
+}
+
+/* ---------- failure rate ---------- */
+
+func Test_FailureRate_trips_on_interleaved_errors(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	seq := func(cb *Interceptor) {
+		itc := cb.Unary()
+		// ошибка, успех, ошибка, ошибка, успех, ... — подряд не больше двух ошибок
+		for i := 0; i < 10; i++ {
+			h := errHandler(codes.Unavailable)
+			if i%3 == 1 {
+				h = okHandler
+			}
+			_ = callUnary(t, itc, h)
+			clk.advance(100 * time.Millisecond)
+		}
+	}
+
+	byCount := makeCB(t, clk) // FailureThreshold=3
+	seq(byCount)
+	if s := byCount.State(); s != "closed" {
+		t.Fatalf("count mode: expected closed, got %s", s)
+	}
+
+	byRate := makeCB(t, clk, WithFailureRate(5, 0.5, 10*time.Second))
+	seq(byRate)
+	if s := byRate.State(); s != "open" {
+		t.Fatalf("rate mode: expected open, got %s", s)
+	}
+}
+
+func Test_FailureRate_needs_min_requests_and_ratio(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithFailureRate(10, 0.5, 10*time.Second))
+	itc := cb.Unary()
+
+	// 4 ошибки подряд: больше FailureThreshold, но меньше minRequests
+	for i := 0; i < 4; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Internal))
+	}
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("below minRequests: expected closed, got %s", s)
+	}
+
+	// 6 успехов: 4/10 < 0.5
+	for i := 0; i < 6; i++ {
+		_ = callUnary(t, itc, okHandler)
+	}
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("4/10 failures: expected closed, got %s", s)
+	}
+
+	// бизнес-ошибка — вызов, но не сбой: 4/11
+	_ = callUnary(t, itc, bizErrHandler())
+	_ = callUnary(t, itc, errHandler(codes.Internal)) // 5/12
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("5/12 failures: expected closed, got %s", s)
+	}
+	_ = callUnary(t, itc, errHandler(codes.Internal)) // 6/13
+	_ = callUnary(t, itc, errHandler(codes.Internal)) // 7/14 = 0.5
+	if s := cb.State(); s != "open" {
+		t.Fatalf("7/14 failures: expected open, got %s", s)
+	}
+}
+
+func Test_FailureRate_old_calls_leave_window(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithFailureRate(4, 0.5, time.Second))
+	itc := cb.Unary()
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, errHandler(codes.Internal))
+	}
+	clk.advance(2 * time.Second) // ошибки вышли из окна
+
+	for i := 0; i < 3; i++ {
+		_ = callUnary(t, itc, okHandler)
+	}
+	_ = callUnary(t, itc, errHandler(codes.Internal)) // 1/4 в окне
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("expected closed after window slid, got %s", s)
+	}
+}
+
+func Test_FailureRate_recovers_via_half_open_with_clean_window(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithFailureRate(2, 0.5, time.Minute))
+	itc := cb.Unary()
+
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	if s := cb.State(); s != "open" {
+		t.Fatalf("expected open, got %s", s)
+	}
+
+	clk.advance(5 * time.Second)
+	for i := 0; i < 2; i++ { // HalfOpenSuccess=2
+		if err := callUnary(t, itc, okHandler); err != nil {
+			t.Fatalf("probe %d: %v", i, err)
+		}
+	}
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("expected closed, got %s", s)
+	}
+
+	// окно очищено при закрытии: одна ошибка после закрытия — 1/1, но < minRequests
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	if s := cb.State(); s != "closed" {
+		t.Fatalf("window must be reset on close, got %s", s)
+	}
+}
+
+func Test_FailureRate_and_threshold_last_option_wins(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithFailureRate(100, 0.5, time.Minute), WithFailureThreshold(2))
+	if cb.opt.FailureRate != 0 || cb.global.window != nil {
+		t.Fatal("WithFailureThreshold after WithFailureRate must disable rate mode")
+	}
+	itc := cb.Unary()
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	_ = callUnary(t, itc, errHandler(codes.Internal))
+	if s := cb.State(); s != "open" {
+		t.Fatalf("expected open by count, got %s", s)
+	}
+
+	invalid := makeCB(t, clk, WithFailureRate(5, 1.5, time.Minute))
+	if invalid.global.window != nil || invalid.opt.FailureThreshold != 3 {
+		t.Fatal("invalid ratio must keep count mode")
+	}
+}
+
+func Test_FailureRate_per_method(t *testing.T) {
+	clk := &fakeClock{t: time.Unix(1, 0)}
+	cb := makeCB(t, clk, WithPerMethod(true), WithFailureRate(2, 0.5, time.Minute))
+	itc := cb.Unary()
+
+	_ = callMethod(t, itc, "/svc/A", okHandler)
+	_ = callMethod(t, itc, "/svc/B", okHandler)
+	_ = callMethod(t, itc, "/svc/B", okHandler)
+	_ = callMethod(t, itc, "/svc/B", errHandler(codes.Internal)) // B: 1/3
+	_ = callMethod(t, itc, "/svc/A", errHandler(codes.Internal)) // A: 1/2, общий счёт был бы 2/5
+	if cb.StateOf("/svc/A") != "open" || cb.StateOf("/svc/B") != "closed" {
+		t.Fatalf("A=%s B=%s", cb.StateOf("/svc/A"), cb.StateOf("/svc/B"))
+	}
+}
@@ -2,9 +2,12 @@ package deadlinemw
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Config struct {
@@ -31,9 +34,9 @@ func Unary(cfg Config) grpc.UnaryServerInterceptor {
 			if limit > 0 && remaining > limit {
 				nctx, cancel := context.WithTimeout(ctx, limit)
 				defer cancel()
-				return handler(nctx, req)
+				return call(nctx, req, handler)
 			}
-			return handler(ctx, req)
+			return call(ctx, req, handler)
 		}
 
 		apply := wanted
@@ -43,8 +46,21 @@ func Unary(cfg Config) grpc.UnaryServerInterceptor {
 		if apply > 0 {
 			nctx, cancel := context.WithTimeout(ctx, apply)
 			defer cancel()
-			return handler(nctx, req)
+			return call(nctx, req, handler)
 		}
 		return handler(ctx, req)
 	}
 }
+
+// call runs handler and reports an overrun as codes.DeadlineExceeded: without it a handler
+// returning ctx.Err() reaches the client as codes.Unknown. Status errors set by the handler are kept.
+func call(ctx context.Context, req any, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return resp, err
+	}
+	if _, ok := status.FromError(err); ok && !errors.Is(err, context.DeadlineExceeded) {
+		return resp, err
+	}
+	return resp, status.Error(codes.DeadlineExceeded, "deadline exceeded")
+}
@@ -2,10 +2,13 @@ package deadlinemw
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestUnary_SetsDefaultWhenNoDeadline(t *testing.T) {
@@ -139,3 +142,67 @@ func TestUnary_NoDeadlineWhenNoConfig(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestUnary_DefaultTimeoutShortensLongerClientDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	i := Unary(Config{DefaultTimeout: 50 * time.Millisecond})
+	_, err := i(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req any) (any, error) {
+		dl, ok := ctx.Deadline()
+		if !ok {
+			t.Fatalf("expected deadline")
+		}
+		if remaining := time.Until(dl); remaining > 100*time.Millisecond {
+			t.Fatalf("expected longer client deadline shortened to DefaultTimeout, got %v", remaining)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUnary_OverrunReturnsDeadlineExceeded(t *testing.T) {
+	i := Unary(Config{DefaultTimeout: 10 * time.Millisecond})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req any) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUnary_OverrunWrappedErrorReturnsDeadlineExceeded(t *testing.T) {
+	i := Unary(Config{DefaultTimeout: 10 * time.Millisecond})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req any) (any, error) {
+		<-ctx.Done()
+		return nil, errors.New("query failed: canceling statement")
+	})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestUnary_OverrunKeepsHandlerStatus(t *testing.T) {
+	i := Unary(Config{DefaultTimeout: 10 * time.Millisecond})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req any) (any, error) {
+		<-ctx.Done()
+		return nil, status.Error(codes.Unavailable, "db down")
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected handler status to be kept, got %v", err)
+	}
+}
+
+func TestUnary_HandlerErrorWithinDeadlineUnchanged(t *testing.T) {
+	want := errors.New("boom")
+	i := Unary(Config{DefaultTimeout: time.Second})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/svc/m"}, func(ctx context.Context, req any) (any, error) {
+		return nil, want
+	})
+	if !errors.Is(err, want) {
+		t.Fatalf("expected handler error unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package idempotencymw
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vortex-fintech/go-lib/data/idempotency"
+	pg "github.com/vortex-fintech/go-lib/data/postgres"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type runnerStub struct{ pg.Runner }
+
+type storeStub struct {
+	mu   sync.Mutex
+	recs map[string]*idempotency.Record
+}
+
+func newStoreStub() *storeStub { return &storeStub{recs: map[string]*idempotency.Record{}} }
+
+func stubKey(principal, grpcMethod, idemKey string) string {
+	return principal + "|" + grpcMethod + "|" + idemKey
+}
+
+func (s *storeStub) Reserve(_ context.Context, _ pg.Runner, rec idempotency.Record) (idempotency.ReserveResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := stubKey(rec.Principal, rec.GRPCMethod, rec.IdempotencyKey)
+	if cur, ok := s.recs[k]; ok {
+		if cur.RequestHash != rec.RequestHash {
+			return idempotency.ReserveResult{}, idempotency.ErrRequestHashMismatch
+		}
+		cp := *cur
+		return idempotency.ReserveResult{Record: &cp}, nil
+	}
+	rec.Status = idempotency.StatusInProgress
+	rec.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	s.recs[k] = &rec
+	cp := rec
+	return idempotency.ReserveResult{Reserved: true, Record: &cp}, nil
+}
+
+func (s *storeStub) Get(_ context.Context, _ pg.Runner, principal, grpcMethod, idemKey string) (*idempotency.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recs[stubKey(principal, grpcMethod, idemKey)], nil
+}
+
+func (s *storeStub) ReacquireRetryable(_ context.Context, _ pg.Runner, principal, grpcMethod, idemKey, requestHash string, updatedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s.recs[stubKey(principal, grpcMethod, idemKey)]
+	if !ok || cur.Status != idempotency.StatusFailedRetry || cur.RequestHash != requestHash || !cur.UpdatedAt.Before(updatedAt) {
+		return false, nil
+	}
+	cur.Status = idempotency.StatusInProgress
+	cur.UpdatedAt = updatedAt
+	return true, nil
+}
+
+func (s *storeStub) Complete(_ context.Context, _ pg.Runner, principal, grpcMethod, idemKey string, done idempotency.Completion) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s.recs[stubKey(principal, grpcMethod, idemKey)]
+	if !ok || cur.Status != idempotency.StatusInProgress || !cur.UpdatedAt.Equal(done.UpdatedAt) {
+		return false, nil
+	}
+	cur.Status = done.Status
+	cur.ResponseCode = done.ResponseCode
+	cur.ResponsePayload = done.ResponsePayload
+	cur.ErrorMessage = done.ErrorMessage
+	return true, nil
+}
+
+func (s *storeStub) DeleteExpired(context.Context, pg.Runner, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func enforcingUnary(store idempotency.Store) grpc.UnaryServerInterceptor {
+	return Unary(Config{
+		Store:  store,
+		Runner: runnerStub{},
+		ResolvePrincipal: func(context.Context, metadata.MD) string {
+			return "principal-1"
+		},
+		NewResponse: func(string) (proto.Message, error) { return &wrapperspb.StringValue{}, nil },
+	})
+}
+
+func keyCtx(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", key))
+}
+
+var enforceInfo = &grpc.UnaryServerInfo{FullMethod: "/payments.v1.Payments/Capture"}
+
+func TestUnary_StoreDuplicateSkipsHandlerAndReplays(t *testing.T) {
+	store := newStoreStub()
+	i := enforcingUnary(store)
+
+	calls := 0
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		return wrapperspb.String("captured"), nil
+	}
+
+	first, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler)
+	if err != nil {
+		t.Fatalf("unexpected replay error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("handler must run once, ran %d times", calls)
+	}
+	if !proto.Equal(first.(proto.Message), second.(proto.Message)) {
+		t.Fatalf("replayed response differs: %v != %v", first, second)
+	}
+	if got := second.(*wrapperspb.StringValue).GetValue(); got != "captured" {
+		t.Fatalf("unexpected replayed value %q", got)
+	}
+}
+
+func TestUnary_StoreReplaysFinalError(t *testing.T) {
+	store := newStoreStub()
+	i := enforcingUnary(store)
+
+	calls := 0
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		return nil, status.Error(codes.FailedPrecondition, "insufficient funds")
+	}
+
+	_, _ = i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler)
+	_, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler)
+
+	if calls != 1 {
+		t.Fatalf("handler must run once, ran %d times", calls)
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.FailedPrecondition || st.Message() != "insufficient funds" {
+		t.Fatalf("unexpected replayed error: %v", err)
+	}
+}
+
+func TestUnary_StoreRetryableErrorReexecutes(t *testing.T) {
+	store := newStoreStub()
+	i := enforcingUnary(store)
+
+	calls := 0
+	handler := func(context.Context, any) (any, error) {
+		calls++
+		if calls == 1 {
+			return nil, status.Error(codes.Unavailable, "upstream down")
+		}
+		return wrapperspb.String("captured"), nil
+	}
+
+	if _, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	resp, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, handler)
+	if err != nil {
+		t.Fatalf("unexpected error on retry: %v", err)
+	}
+	if calls != 2 || resp.(*wrapperspb.StringValue).GetValue() != "captured" {
+		t.Fatalf("expected re-execution, calls=%d resp=%v", calls, resp)
+	}
+
+	rec, _ := store.Get(context.Background(), nil, "principal-1", enforceInfo.FullMethod, "k-1")
+	if rec.Status != idempotency.StatusSucceeded {
+		t.Fatalf("expected SUCCEEDED after retry, got %s", rec.Status)
+	}
+}
+
+func TestUnary_StoreHashMismatchIsInvalidArgument(t *testing.T) {
+	store := newStoreStub()
+	i := enforcingUnary(store)
+	handler := func(context.Context, any) (any, error) { return wrapperspb.String("ok"), nil }
+
+	if _, err := i(keyCtx("k-1"), wrapperspb.String("a"), enforceInfo, handler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err := i(keyCtx("k-1"), wrapperspb.String("b"), enforceInfo, handler)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestUnary_StoreInProgressIsAborted(t *testing.T) {
+	store := newStoreStub()
+	i := enforcingUnary(store)
+
+	_, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, func(ctx context.Context, req any) (any, error) {
+		_, err := i(keyCtx("k-1"), req, enforceInfo, func(context.Context, any) (any, error) {
+			t.Fatalf("concurrent duplicate must not run the handler")
+			return nil, nil
+		})
+		if status.Code(err) != codes.Aborted {
+			t.Fatalf("expected Aborted for in-progress duplicate, got %v", err)
+		}
+		return wrapperspb.String("ok"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestUnary_StoreWithoutRunnerIsInternal(t *testing.T) {
+	i := Unary(Config{Store: newStoreStub()})
+	_, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, func(context.Context, any) (any, error) {
+		t.Fatalf("handler must not run without runner")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+}
+
+// acceptingRedis answers every RedisStore script with success: Reserve and Complete
+// always apply, which is enough for a single first execution.
+type acceptingRedis struct {
+	redis.UniversalClient
+	evals int
+}
+
+func (r *acceptingRedis) EvalSha(context.Context, string, []string, ...any) *redis.Cmd {
+	r.evals++
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func TestUnary_RunnerlessStoreWithoutRunner(t *testing.T) {
+	rdb := &acceptingRedis{}
+	i := Unary(Config{
+		Store: idempotency.NewRedisStore(rdb),
+		ResolvePrincipal: func(context.Context, metadata.MD) string {
+			return "principal-1"
+		},
+	})
+
+	called := false
+	_, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, func(context.Context, any) (any, error) {
+		called = true
+		return wrapperspb.String("captured"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Fatal("handler must run")
+	}
+	if rdb.evals != 2 {
+		t.Fatalf("expected reserve and complete scripts, got %d calls", rdb.evals)
+	}
+}
+
+func TestUnary_StoreRunnerFromContextAndFinishError(t *testing.T) {
+	store := newStoreStub()
+	var resolved, finishErr bool
+	i := Unary(Config{
+		Store: store,
+		RunnerFromContext: func(context.Context) pg.Runner {
+			resolved = true
+			return runnerStub{}
+		},
+		OnFinishError: func(_ context.Context, meta Metadata, err error) {
+			finishErr = meta.IdempotencyKey == "k-1" && err != nil
+		},
+	})
+
+	_, err := i(keyCtx("k-1"), wrapperspb.String("req"), enforceInfo, func(context.Context, any) (any, error) {
+		store.mu.Lock()
+		store.recs[stubKey("unknown", enforceInfo.FullMethod, "k-1")].UpdatedAt = time.Now().Add(time.Hour)
+		store.mu.Unlock()
+		return wrapperspb.String("ok"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resolved {
+		t.Fatalf("expected RunnerFromContext to be used")
+	}
+	if !finishErr {
+		t.Fatalf("expected OnFinishError for lost lease")
+	}
+}
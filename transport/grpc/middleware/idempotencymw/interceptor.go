@@ -4,16 +4,27 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
 	"strings"
+	"time"
 
+	"github.com/vortex-fintech/go-lib/data/idempotency"
+	pg "github.com/vortex-fintech/go-lib/data/postgres"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
-const defaultHeader = "idempotency-key"
+const (
+	defaultHeader = "idempotency-key"
+	defaultTTL    = 24 * time.Hour
+)
 
 type Metadata struct {
 	Principal      string
@@ -28,6 +39,17 @@ type Config struct {
 	MaxKeyLength     int
 	IsMethodEnabled  func(fullMethod string) bool
 	ResolvePrincipal func(ctx context.Context, md metadata.MD) string
+	HashFields       func(msg proto.Message) []byte
+	Hasher           func() hash.Hash
+
+	Store             idempotency.Store
+	Runner            pg.Runner
+	RunnerFromContext func(ctx context.Context) pg.Runner
+	TTL               time.Duration
+	NewResponse       func(fullMethod string) (proto.Message, error)
+	IsRetryableCode   func(c codes.Code) bool
+	OnFinishError     func(ctx context.Context, meta Metadata, err error)
+	Now               func() time.Time
 }
 
 type ctxKey struct{}
@@ -49,6 +71,15 @@ func Unary(cfg Config) grpc.UnaryServerInterceptor {
 	if resolve == nil {
 		resolve = func(context.Context, metadata.MD) string { return "unknown" }
 	}
+	hashFields := marshalDeterministic
+	if cfg.HashFields != nil {
+		hashFields = func(msg proto.Message) ([]byte, error) { return cfg.HashFields(msg), nil }
+	}
+	newHash := cfg.Hasher
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	enf := newEnforcer(cfg)
 
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		if !enabled(info.FullMethod) {
@@ -71,22 +102,31 @@ func Unary(cfg Config) grpc.UnaryServerInterceptor {
 		if !ok {
 			return nil, status.Error(codes.Internal, "request is not a protobuf message")
 		}
-		bytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		bytes, err := hashFields(msg)
 		if err != nil {
 			return nil, status.Error(codes.Internal, "failed to hash request payload")
 		}
-		h := sha256.Sum256(bytes)
+		h := newHash()
+		_, _ = h.Write(bytes)
 
-		ctx = context.WithValue(ctx, ctxKey{}, Metadata{
+		meta := Metadata{
 			Principal:      resolve(ctx, md),
 			GRPCMethod:     info.FullMethod,
 			IdempotencyKey: key,
-			RequestHash:    hex.EncodeToString(h[:]),
-		})
-		return handler(ctx, req)
+			RequestHash:    hex.EncodeToString(h.Sum(nil)),
+		}
+		ctx = context.WithValue(ctx, ctxKey{}, meta)
+		if enf == nil {
+			return handler(ctx, req)
+		}
+		return enf.handle(ctx, req, meta, handler)
 	}
 }
 
+func marshalDeterministic(msg proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+}
+
 func FromContext(ctx context.Context) (Metadata, bool) {
 	v := ctx.Value(ctxKey{})
 	m, ok := v.(Metadata)
@@ -100,3 +140,197 @@ func firstMD(md metadata.MD, key string) string {
 	}
 	return vals[0]
 }
+
+type enforcer struct {
+	store       idempotency.Store
+	runner      func(context.Context) pg.Runner
+	ttl         time.Duration
+	newResponse func(string) (proto.Message, error)
+	retryable   func(codes.Code) bool
+	onFinishErr func(context.Context, Metadata, error)
+	now         func() time.Time
+}
+
+func newEnforcer(cfg Config) *enforcer {
+	if cfg.Store == nil {
+		return nil
+	}
+	e := &enforcer{
+		store:       cfg.Store,
+		runner:      cfg.RunnerFromContext,
+		ttl:         cfg.TTL,
+		newResponse: cfg.NewResponse,
+		retryable:   cfg.IsRetryableCode,
+		onFinishErr: cfg.OnFinishError,
+		now:         cfg.Now,
+	}
+	if e.runner == nil {
+		run := cfg.Runner
+		e.runner = func(context.Context) pg.Runner { return run }
+	}
+	if e.ttl <= 0 {
+		e.ttl = defaultTTL
+	}
+	if e.newResponse == nil {
+		e.newResponse = responseFromRegistry
+	}
+	if e.retryable == nil {
+		e.retryable = isRetryableCode
+	}
+	if e.now == nil {
+		e.now = time.Now
+	}
+	return e
+}
+
+func (e *enforcer) handle(ctx context.Context, req any, meta Metadata, handler grpc.UnaryHandler) (any, error) {
+	run := e.runner(ctx)
+	if run == nil && idempotency.NeedsRunner(e.store) {
+		return nil, status.Error(codes.Internal, "idempotency runner is not configured")
+	}
+
+	now := e.now().UTC().Truncate(time.Microsecond)
+	begin, err := idempotency.Begin(ctx, e.store, run, idempotency.BeginInput{
+		Principal:      meta.Principal,
+		GRPCMethod:     meta.GRPCMethod,
+		IdempotencyKey: meta.IdempotencyKey,
+		RequestHash:    meta.RequestHash,
+		ExpiresAt:      now.Add(e.ttl),
+	})
+	if errors.Is(err, idempotency.ErrRequestHashMismatch) {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key reused with a different request")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to begin idempotent request")
+	}
+
+	var lease idempotency.Record
+	switch begin.Decision {
+	case idempotency.BeginDecisionExecute:
+		lease = *begin.Lease
+	case idempotency.BeginDecisionReplay:
+		return e.replay(meta.GRPCMethod, *begin.Existing)
+	case idempotency.BeginDecisionInProgress:
+		return nil, status.Error(codes.Aborted, "request with this idempotency key is in progress")
+	case idempotency.BeginDecisionRetryable:
+		var ok bool
+		lease, ok, err = e.reacquire(ctx, run, *begin.Existing, now)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to reacquire idempotent request")
+		}
+		if !ok {
+			return nil, status.Error(codes.Aborted, "request with this idempotency key is in progress")
+		}
+	default:
+		return nil, status.Error(codes.Internal, "unknown idempotency decision")
+	}
+
+	resp, herr := handler(ctx, req)
+	e.finish(ctx, run, meta, lease, e.completion(resp, herr))
+	return resp, herr
+}
+
+func (e *enforcer) replay(fullMethod string, rec idempotency.Record) (any, error) {
+	payload, st, err := rec.ReplayResponse()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to replay idempotent response")
+	}
+	if err := st.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := e.newResponse(fullMethod)
+	if err != nil || resp == nil {
+		return nil, status.Error(codes.Internal, "failed to replay idempotent response")
+	}
+	if err := proto.Unmarshal(payload, resp); err != nil {
+		return nil, status.Error(codes.Internal, "failed to replay idempotent response")
+	}
+	return resp, nil
+}
+
+func (e *enforcer) reacquire(ctx context.Context, run pg.Runner, rec idempotency.Record, now time.Time) (idempotency.Record, bool, error) {
+	next := now
+	if !next.After(rec.UpdatedAt) {
+		next = rec.UpdatedAt.Add(time.Microsecond)
+	}
+	ok, err := idempotency.Reacquire(ctx, e.store, run, rec, next)
+	if err != nil || !ok {
+		return idempotency.Record{}, ok, err
+	}
+	rec.Status = idempotency.StatusInProgress
+	rec.ResponseCode = 0
+	rec.ResponsePayload = nil
+	rec.ErrorMessage = ""
+	rec.UpdatedAt = next
+	return rec, true, nil
+}
+
+func (e *enforcer) completion(resp any, err error) idempotency.Completion {
+	if err != nil {
+		st := status.Convert(err)
+		done := idempotency.Completion{
+			Status:       idempotency.StatusFailedFinal,
+			ResponseCode: int32(st.Code()),
+			ErrorMessage: st.Message(),
+		}
+		if e.retryable(st.Code()) {
+			done.Status = idempotency.StatusFailedRetry
+		}
+		return done
+	}
+
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return storeFailure("response is not a protobuf message")
+	}
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return storeFailure("failed to marshal response")
+	}
+	return idempotency.Completion{Status: idempotency.StatusSucceeded, ResponsePayload: payload}
+}
+
+func (e *enforcer) finish(ctx context.Context, run pg.Runner, meta Metadata, lease idempotency.Record, done idempotency.Completion) {
+	ok, err := idempotency.Finish(context.WithoutCancel(ctx), e.store, run, lease, done)
+	if err == nil && !ok {
+		err = idempotency.ErrLeaseLost
+	}
+	if err != nil && e.onFinishErr != nil {
+		e.onFinishErr(ctx, meta, err)
+	}
+}
+
+func storeFailure(msg string) idempotency.Completion {
+	return idempotency.Completion{
+		Status:       idempotency.StatusFailedFinal,
+		ResponseCode: int32(codes.Internal),
+		ErrorMessage: msg,
+	}
+}
+
+func isRetryableCode(c codes.Code) bool {
+	switch c {
+	case codes.Canceled, codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted,
+		codes.Aborted, codes.Internal, codes.Unavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+func responseFromRegistry(fullMethod string) (proto.Message, error) {
+	name := protoreflect.FullName(strings.ReplaceAll(strings.TrimPrefix(fullMethod, "/"), "/", "."))
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(name)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := d.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("idempotencymw: %s is not a method", name)
+	}
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(md.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+	return mt.New().Interface(), nil
+}
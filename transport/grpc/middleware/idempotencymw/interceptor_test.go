@@ -2,13 +2,16 @@ package idempotencymw
 
 import (
 	"context"
+	"crypto/sha512"
 	"testing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestUnary_PutsMetadataIntoContext(t *testing.T) {
@@ -161,3 +164,71 @@ func TestUnary_Defaults(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func requestHash(t *testing.T, i grpc.UnaryServerInterceptor, req any) string {
+	t.Helper()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "k-1"))
+	var hash string
+	_, err := i(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/svc/method"}, func(ctx context.Context, req any) (any, error) {
+		m, _ := FromContext(ctx)
+		hash = m.RequestHash
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return hash
+}
+
+func mustStruct(t *testing.T, fields map[string]any) *structpb.Struct {
+	t.Helper()
+	s, err := structpb.NewStruct(fields)
+	if err != nil {
+		t.Fatalf("struct: %v", err)
+	}
+	return s
+}
+
+func TestUnary_HashFieldsExcludesVolatileFields(t *testing.T) {
+	i := Unary(Config{
+		HashFields: func(msg proto.Message) []byte {
+			s := proto.Clone(msg).(*structpb.Struct)
+			delete(s.Fields, "trace_id")
+			b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(s)
+			return b
+		},
+	})
+
+	h1 := requestHash(t, i, mustStruct(t, map[string]any{"amount": "100", "trace_id": "t-1"}))
+	h2 := requestHash(t, i, mustStruct(t, map[string]any{"amount": "100", "trace_id": "t-2"}))
+	h3 := requestHash(t, i, mustStruct(t, map[string]any{"amount": "200", "trace_id": "t-1"}))
+
+	if h1 != h2 {
+		t.Fatalf("requests differing only in excluded field must share hash: %s != %s", h1, h2)
+	}
+	if h1 == h3 {
+		t.Fatalf("requests differing in hashed field must not share hash")
+	}
+
+	full := Unary(Config{})
+	if requestHash(t, full, mustStruct(t, map[string]any{"amount": "100", "trace_id": "t-1"})) ==
+		requestHash(t, full, mustStruct(t, map[string]any{"amount": "100", "trace_id": "t-2"})) {
+		t.Fatalf("default hash must cover the full message")
+	}
+}
+
+func TestUnary_CustomHasher(t *testing.T) {
+	i := Unary(Config{Hasher: sha512.New})
+
+	h1 := requestHash(t, i, &emptypb.Empty{})
+	h2 := requestHash(t, i, &emptypb.Empty{})
+	if h1 != h2 {
+		t.Fatalf("same request should produce same hash: %s != %s", h1, h2)
+	}
+	if len(h1) != sha512.Size*2 {
+		t.Fatalf("expected sha512 hex digest, got %q", h1)
+	}
+	if h1 == requestHash(t, Unary(Config{}), &emptypb.Empty{}) {
+		t.Fatalf("custom hasher must change the digest")
+	}
+}
@@ -3,14 +3,58 @@ package recoverymw
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// Logger is satisfied by foundation/logger.LoggerInterface.
+type Logger interface {
+	ErrorwCtx(ctx context.Context, msg string, keysAndValues ...any)
+}
+
+type Metrics interface {
+	IncPanics(method string)
+}
+
 type Options struct {
 	OnPanic func(ctx context.Context, method string, recovered any)
+
+	// Logger logs each recovered panic with method, panic value and stack.
+	Logger Logger
+	// Metrics counts recovered panics per method.
+	Metrics Metrics
+	// ExposePanic appends the panic value to the Internal status message.
+	// Keep it off in production: the value may contain internal details.
+	ExposePanic bool
+	// RePanic, if set and returning true, re-panics with the recovered value
+	// (e.g. for control-flow panics such as http.ErrAbortHandler) without logging or metrics.
+	RePanic func(recovered any) bool
+}
+
+func (o Options) handle(ctx context.Context, method string, r any) error {
+	if o.RePanic != nil && o.RePanic(r) {
+		panic(r)
+	}
+	if o.Logger != nil {
+		o.Logger.ErrorwCtx(ctx, "grpc panic recovered",
+			"method", method,
+			"panic", PanicString(r),
+			"stack", string(debug.Stack()),
+		)
+	}
+	if o.Metrics != nil {
+		o.Metrics.IncPanics(method)
+	}
+	if o.OnPanic != nil {
+		o.OnPanic(ctx, method, r)
+	}
+	if o.ExposePanic {
+		return status.Error(codes.Internal, "internal server error: "+PanicString(r))
+	}
+	return status.Error(codes.Internal, "internal server error")
 }
 
 func Unary(opts Options) grpc.UnaryServerInterceptor {
@@ -20,10 +64,7 @@ func Unary(opts Options) grpc.UnaryServerInterceptor {
 			if r == nil {
 				return
 			}
-			if opts.OnPanic != nil {
-				opts.OnPanic(ctx, info.FullMethod, r)
-			}
-			err = status.Error(codes.Internal, "internal server error")
+			err = opts.handle(ctx, info.FullMethod, r)
 		}()
 		return handler(ctx, req)
 	}
@@ -36,10 +77,7 @@ func Stream(opts Options) grpc.StreamServerInterceptor {
 			if r == nil {
 				return
 			}
-			if opts.OnPanic != nil {
-				opts.OnPanic(ss.Context(), info.FullMethod, r)
-			}
-			err = status.Error(codes.Internal, "internal server error")
+			err = opts.handle(ss.Context(), info.FullMethod, r)
 		}()
 		return handler(srv, ss)
 	}
@@ -3,6 +3,8 @@ package recoverymw
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
 	"testing"
 
 	"google.golang.org/grpc"
@@ -106,3 +108,102 @@ func TestStream_NoPanic(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+type recordingLogger struct {
+	msg string
+	kv  map[string]any
+}
+
+func (l *recordingLogger) ErrorwCtx(_ context.Context, msg string, kv ...any) {
+	l.msg = msg
+	l.kv = map[string]any{}
+	for i := 0; i+1 < len(kv); i += 2 {
+		k, _ := kv[i].(string)
+		l.kv[k] = kv[i+1]
+	}
+}
+
+type countingMetrics struct {
+	panics map[string]int
+}
+
+func (m *countingMetrics) IncPanics(method string) {
+	if m.panics == nil {
+		m.panics = map[string]int{}
+	}
+	m.panics[method]++
+}
+
+func TestUnary_LoggerAndMetrics(t *testing.T) {
+	log := &recordingLogger{}
+	met := &countingMetrics{}
+	i := Unary(Options{Logger: log, Metrics: met})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/x/y/z"}, func(context.Context, any) (any, error) {
+		panic("secret db password")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", status.Code(err))
+	}
+	if strings.Contains(status.Convert(err).Message(), "secret") {
+		t.Fatalf("panic value leaked to client: %q", status.Convert(err).Message())
+	}
+	if log.msg == "" || log.kv["method"] != "/x/y/z" || log.kv["panic"] != "secret db password" {
+		t.Fatalf("unexpected log entry: %q %v", log.msg, log.kv)
+	}
+	if stack, _ := log.kv["stack"].(string); stack == "" {
+		t.Fatal("expected stack in log entry")
+	}
+	if met.panics["/x/y/z"] != 1 {
+		t.Fatalf("expected 1 panic metric, got %v", met.panics)
+	}
+}
+
+func TestStream_LoggerAndMetrics(t *testing.T) {
+	log := &recordingLogger{}
+	met := &countingMetrics{}
+	i := Stream(Options{Logger: log, Metrics: met})
+	ss := &mockStream{ctx: context.Background()}
+	err := i("srv", ss, &grpc.StreamServerInfo{FullMethod: "/x/y/s"}, func(any, grpc.ServerStream) error {
+		panic(errors.New("boom"))
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", status.Code(err))
+	}
+	if log.kv["method"] != "/x/y/s" || log.kv["panic"] != "boom" {
+		t.Fatalf("unexpected log entry: %v", log.kv)
+	}
+	if met.panics["/x/y/s"] != 1 {
+		t.Fatalf("expected 1 panic metric, got %v", met.panics)
+	}
+}
+
+func TestUnary_ExposePanic(t *testing.T) {
+	i := Unary(Options{ExposePanic: true})
+	_, err := i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/x/y/z"}, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	if got := status.Convert(err).Message(); got != "internal server error: boom" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
+
+func TestUnary_RePanic(t *testing.T) {
+	met := &countingMetrics{}
+	i := Unary(Options{
+		Metrics: met,
+		RePanic: func(r any) bool { return r == http.ErrAbortHandler },
+	})
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Fatalf("expected re-panic with ErrAbortHandler, got %v", r)
+		}
+		if len(met.panics) != 0 {
+			t.Fatalf("re-panicked value must not be counted, got %v", met.panics)
+		}
+	}()
+	_, _ = i(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/x/y/z"}, func(context.Context, any) (any, error) {
+		panic(http.ErrAbortHandler)
+	})
+	t.Fatal("expected panic to propagate")
+}